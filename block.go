@@ -1,5 +1,12 @@
 package notion
 
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kuekiko/NotionGO/errors"
+)
+
 // Block 表示块对象
 type Block struct {
 	Object         string    `json:"object"`           // 总是 "block"
@@ -46,6 +53,47 @@ type Block struct {
 	Code             *CodeBlock          `json:"code,omitempty"`
 }
 
+// MarshalJSON 实现 json.Marshaler，省略未设置的只读字段，使 Block 可以直接用于创建和追加块
+func (b Block) MarshalJSON() ([]byte, error) {
+	type block Block
+	aux := struct {
+		block
+		Object         string  `json:"object,omitempty"`
+		ID             string  `json:"id,omitempty"`
+		Parent         *Parent `json:"parent,omitempty"`
+		CreatedTime    string  `json:"created_time,omitempty"`
+		LastEditedTime string  `json:"last_edited_time,omitempty"`
+		CreatedBy      *User   `json:"created_by,omitempty"`
+		LastEditedBy   *User   `json:"last_edited_by,omitempty"`
+		HasChildren    bool    `json:"has_children,omitempty"`
+		Archived       bool    `json:"archived,omitempty"`
+	}{
+		block:          block(b),
+		Object:         b.Object,
+		ID:             b.ID,
+		CreatedTime:    b.CreatedTime,
+		LastEditedTime: b.LastEditedTime,
+		HasChildren:    b.HasChildren,
+		Archived:       b.Archived,
+	}
+	if b.Parent != (Parent{}) {
+		aux.Parent = &b.Parent
+	}
+	if b.CreatedBy.ID != "" {
+		aux.CreatedBy = &b.CreatedBy
+	}
+	if b.LastEditedBy.ID != "" {
+		aux.LastEditedBy = &b.LastEditedBy
+	}
+	return json.Marshal(aux)
+}
+
+// BlockNode 表示块及其子块组成的树
+type BlockNode struct {
+	*Block
+	Children []*BlockNode
+}
+
 // ParagraphBlock 表示段落块
 type ParagraphBlock struct {
 	RichText []RichText `json:"rich_text"`
@@ -58,6 +106,7 @@ type HeadingBlock struct {
 	RichText     []RichText `json:"rich_text"`
 	Color        Color      `json:"color"`
 	IsToggleable bool       `json:"is_toggleable"`
+	Children     []Block    `json:"children,omitempty"` // 可折叠标题的子块
 }
 
 // ListItemBlock 表示列表项块
@@ -106,7 +155,7 @@ type BookmarkBlock struct {
 // CalloutBlock 表示标注块
 type CalloutBlock struct {
 	RichText []RichText `json:"rich_text"`
-	Icon     *Icon      `json:"icon"`
+	Icon     *Icon      `json:"icon,omitempty"`
 	Color    Color      `json:"color"`
 	Children []Block    `json:"children,omitempty"`
 }
@@ -186,10 +235,10 @@ func NewBlockService(client *Client) *BlockService {
 }
 
 // Get 获取块
-func (s *BlockService) Get(blockID string) (*Block, error) {
+func (s *BlockService) Get(ctx context.Context, blockID string) (*Block, error) {
 	path := "blocks/" + blockID
 	block := new(Block)
-	err := s.client.get(path, nil, block)
+	err := s.client.get(ctx, path, nil, block)
 	if err != nil {
 		return nil, err
 	}
@@ -197,10 +246,10 @@ func (s *BlockService) Get(blockID string) (*Block, error) {
 }
 
 // Update 更新块
-func (s *BlockService) Update(blockID string, block *Block) (*Block, error) {
+func (s *BlockService) Update(ctx context.Context, blockID string, block *Block) (*Block, error) {
 	path := "blocks/" + blockID
 	response := new(Block)
-	err := s.client.patch(path, block, response)
+	err := s.client.patch(ctx, path, block, response)
 	if err != nil {
 		return nil, err
 	}
@@ -208,27 +257,80 @@ func (s *BlockService) Update(blockID string, block *Block) (*Block, error) {
 }
 
 // Delete 删除块
-func (s *BlockService) Delete(blockID string) error {
+func (s *BlockService) Delete(ctx context.Context, blockID string) error {
 	path := "blocks/" + blockID
-	return s.client.delete(path)
+	return s.client.delete(ctx, path)
 }
 
 // ListChildren 列出子块
-func (s *BlockService) ListChildren(blockID string, params *ListParams) (*ListResponse, error) {
+func (s *BlockService) ListChildren(ctx context.Context, blockID string, params *ListParams) (*BlockList, error) {
 	path := "blocks/" + blockID + "/children"
-	response := new(ListResponse)
-	err := s.client.get(path, nil, response)
+	response := new(BlockList)
+	err := s.client.get(ctx, path, params, response)
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
+// ListChildrenIter 返回遍历所有子块的迭代器
+func (s *BlockService) ListChildrenIter(ctx context.Context, blockID string, params *ListParams) *Iterator[*Block] {
+	p := ListParams{}
+	if params != nil {
+		p = *params
+	}
+	return newIterator(ctx, p.StartCursor, func(ctx context.Context, cursor string) ([]*Block, string, bool, error) {
+		p.StartCursor = cursor
+		list, err := s.ListChildren(ctx, blockID, &p)
+		if err != nil {
+			return nil, "", false, err
+		}
+		return list.Results, list.NextCursor, list.HasMore, nil
+	})
+}
+
 // AppendChildren 追加子块
-func (s *BlockService) AppendChildren(blockID string, children []Block) (*ListResponse, error) {
+//
+// 超过 Notion 单次请求上限（100 个）的子块分批追加；嵌套超过单次请求允许的两层时，
+// 更深的子块在父块创建后再追加。返回的结果只包含 children 中的顶层块。
+func (s *BlockService) AppendChildren(ctx context.Context, blockID string, children []Block) (*BlockList, error) {
+	response := &BlockList{Object: "list", Results: []*Block{}}
+	limit := errors.SizeLimits.MaxArrayElements
+	for start := 0; start < len(children) || start == 0; start += limit {
+		end := start + limit
+		if end > len(children) {
+			end = len(children)
+		}
+		send, deferred := splitNestedChildren(children[start:end], limit)
+		list, err := s.appendChildren(ctx, blockID, send)
+		if err != nil {
+			return nil, err
+		}
+		response.Results = append(response.Results, list.Results...)
+
+		for i, rest := range deferred {
+			if len(rest) == 0 {
+				continue
+			}
+			if i >= len(list.Results) {
+				return nil, errors.NewError(errors.ErrUnknown, "追加子块的响应缺少新建的块", 0)
+			}
+			if _, err := s.AppendChildren(ctx, list.Results[i].ID, rest); err != nil {
+				return nil, err
+			}
+		}
+		if end == len(children) {
+			break
+		}
+	}
+	return response, nil
+}
+
+// appendChildren 发送一次追加子块的请求
+func (s *BlockService) appendChildren(ctx context.Context, blockID string, children []Block) (*BlockList, error) {
 	path := "blocks/" + blockID + "/children"
-	response := new(ListResponse)
-	err := s.client.patch(path, map[string]interface{}{
+	response := new(BlockList)
+	err := s.client.patch(ctx, path, map[string]interface{}{
 		"children": children,
 	}, response)
 	if err != nil {
@@ -236,3 +338,45 @@ func (s *BlockService) AppendChildren(blockID string, children []Block) (*ListRe
 	}
 	return response, nil
 }
+
+// splitNestedChildren 返回可以在一次请求中发送的块，以及每个块需要在创建后追加的子块
+//
+// 子块本身还有子块或数量超过 limit 时，超出的部分推迟追加。表格和分栏必须在创建时包含子块，保持不变。
+func splitNestedChildren(blocks []Block, limit int) ([]Block, [][]Block) {
+	send := make([]Block, len(blocks))
+	deferred := make([][]Block, len(blocks))
+	for i, b := range blocks {
+		send[i] = b
+		if b.Type == TypeColumnList {
+			continue
+		}
+		_, _, children := send[i].fields()
+		if children == nil || len(*children) == 0 {
+			continue
+		}
+
+		nested := false
+		for j := range *children {
+			if c := (*children)[j]; c.Type != TypeColumnList && c.Type != TypeTable && hasChildren(&c) {
+				nested = true
+				break
+			}
+		}
+		switch {
+		case nested:
+			deferred[i] = *children
+			*children = nil
+		case len(*children) > limit:
+			deferred[i] = (*children)[limit:]
+			*children = (*children)[:limit]
+		}
+	}
+	return send, deferred
+}
+
+// hasChildren 判断块是否包含待创建的子块
+func hasChildren(b *Block) bool {
+	c := *b
+	_, _, children := c.fields()
+	return children != nil && len(*children) > 0
+}
@@ -0,0 +1,405 @@
+package notion
+
+import (
+	"strings"
+)
+
+// 本文件提供创建块的辅助函数，用法：
+//
+//	children := []notion.Block{
+//		notion.Heading1("Go 编程指南"),
+//		notion.Paragraph("访问 ", notion.Plain("Go 官网").WithLink("https://go.dev"), notion.Plain(" 下载。")),
+//		notion.Code("bash", "go version"),
+//		notion.ToDo("安装 Go", true),
+//		notion.Callout("💡", "提示").WithColor(notion.ColorGrayBackground),
+//	}
+//
+// 文本参数中超过 errors.SizeLimits.MaxRichTextContent 的内容会被拆分为多个片段。
+// child_page 和 child_database 块不能通过块接口创建，请使用 Pages.Create 和 Database.Create。
+
+// Paragraph 创建段落块，spans 追加在 text 之后
+func Paragraph(text string, spans ...RichText) Block {
+	return Block{Object: "block", Type: TypeParagraph, Paragraph: &ParagraphBlock{
+		RichText: richTextOf(text, spans),
+		Color:    ColorDefault,
+	}}
+}
+
+// Heading1 创建一级标题块
+func Heading1(text string, spans ...RichText) Block {
+	return Block{Object: "block", Type: TypeHeading1, Heading1: newHeading(text, spans)}
+}
+
+// Heading2 创建二级标题块
+func Heading2(text string, spans ...RichText) Block {
+	return Block{Object: "block", Type: TypeHeading2, Heading2: newHeading(text, spans)}
+}
+
+// Heading3 创建三级标题块
+func Heading3(text string, spans ...RichText) Block {
+	return Block{Object: "block", Type: TypeHeading3, Heading3: newHeading(text, spans)}
+}
+
+func newHeading(text string, spans []RichText) *HeadingBlock {
+	return &HeadingBlock{RichText: richTextOf(text, spans), Color: ColorDefault}
+}
+
+// BulletedListItem 创建无序列表项
+func BulletedListItem(text string, spans ...RichText) Block {
+	return Block{Object: "block", Type: TypeBulletedListItem, BulletedListItem: &ListItemBlock{
+		RichText: richTextOf(text, spans),
+		Color:    ColorDefault,
+	}}
+}
+
+// NumberedListItem 创建有序列表项
+func NumberedListItem(text string, spans ...RichText) Block {
+	return Block{Object: "block", Type: TypeNumberedListItem, NumberedListItem: &ListItemBlock{
+		RichText: richTextOf(text, spans),
+		Color:    ColorDefault,
+	}}
+}
+
+// ToDo 创建待办事项
+func ToDo(text string, checked bool) Block {
+	return Block{Object: "block", Type: TypeToDo, ToDo: &ToDoBlock{
+		RichText: richTextOf(text, nil),
+		Checked:  checked,
+		Color:    ColorDefault,
+	}}
+}
+
+// Toggle 创建折叠块
+func Toggle(text string, children ...Block) Block {
+	return Block{Object: "block", Type: TypeToggle, Toggle: &ToggleBlock{
+		RichText: richTextOf(text, nil),
+		Color:    ColorDefault,
+		Children: children,
+	}}
+}
+
+// Code 创建代码块，language 为 Notion 支持的语言名称，例如 "go"、"bash" 或 "plain text"
+func Code(language, source string) Block {
+	if language == "" {
+		language = "plain text"
+	}
+	return Block{Object: "block", Type: TypeCode, Code: &CodeBlock{
+		RichText: richTextOf(source, nil),
+		Caption:  []RichText{},
+		Language: language,
+	}}
+}
+
+// Quote 创建引用块
+func Quote(text string, spans ...RichText) Block {
+	return Block{Object: "block", Type: TypeQuote, Quote: &QuoteBlock{
+		RichText: richTextOf(text, spans),
+		Color:    ColorDefault,
+	}}
+}
+
+// Callout 创建标注块，icon 可以是 emoji 或图片 URL，为空时使用 Notion 的默认图标
+func Callout(icon, text string, spans ...RichText) Block {
+	return Block{Object: "block", Type: TypeCallout, Callout: &CalloutBlock{
+		RichText: richTextOf(text, spans),
+		Icon:     iconOf(icon),
+		Color:    ColorDefault,
+	}}
+}
+
+// iconOf 将 emoji 或 URL 转换为图标
+func iconOf(icon string) *Icon {
+	switch {
+	case icon == "":
+		return nil
+	case strings.HasPrefix(icon, "http://") || strings.HasPrefix(icon, "https://"):
+		return &Icon{Type: "external", External: &External{URL: icon}}
+	default:
+		return &Icon{Type: "emoji", Emoji: icon}
+	}
+}
+
+// Divider 创建分割线
+func Divider() Block {
+	return Block{Object: "block", Type: TypeDivider, Divider: &EmptyObject{}}
+}
+
+// externalFile 创建外部文件
+func externalFile(url string) *File {
+	return &File{Type: "external", External: &External{URL: url}}
+}
+
+// Image 创建外部图片块
+func Image(url string) Block {
+	return Block{Object: "block", Type: TypeImage, Image: externalFile(url)}
+}
+
+// Video 创建外部视频块
+func Video(url string) Block {
+	return Block{Object: "block", Type: TypeVideo, Video: externalFile(url)}
+}
+
+// FileBlock 创建外部文件块
+func FileBlock(url string) Block {
+	return Block{Object: "block", Type: TypeFile, File: externalFile(url)}
+}
+
+// PDF 创建外部 PDF 块
+func PDF(url string) Block {
+	return Block{Object: "block", Type: TypePDF, PDF: externalFile(url)}
+}
+
+// Bookmark 创建书签块
+func Bookmark(url string) Block {
+	return Block{Object: "block", Type: TypeBookmark, Bookmark: &BookmarkBlock{URL: url, Caption: []RichText{}}}
+}
+
+// Embed 创建嵌入块
+func Embed(url string) Block {
+	return Block{Object: "block", Type: TypeEmbed, Embed: &EmbedBlock{URL: url}}
+}
+
+// LinkPreview 创建链接预览块
+//
+// Notion API 只返回链接预览块，不支持创建，追加时请使用 Bookmark 或 Embed。
+func LinkPreview(url string) Block {
+	return Block{Object: "block", Type: TypeLinkPreview, LinkPreview: &LinkPreviewBlock{URL: url}}
+}
+
+// Table 创建表格块，每个元素是一行单元格文本，表格宽度取最长的一行
+func Table(rows [][]string) Block {
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	children := make([]Block, 0, len(rows))
+	for _, row := range rows {
+		cells := make([]string, width)
+		copy(cells, row)
+		children = append(children, TableRow(cells...))
+	}
+	return Block{Object: "block", Type: TypeTable, Table: &TableBlock{
+		TableWidth: width,
+		Children:   children,
+	}}
+}
+
+// TableRow 创建表格行
+func TableRow(cells ...string) Block {
+	row := make([][]RichText, len(cells))
+	for i, cell := range cells {
+		row[i] = richTextOf(cell, nil)
+	}
+	return Block{Object: "block", Type: TypeTableRow, TableRow: &TableRowBlock{Cells: row}}
+}
+
+// TableOfContents 创建目录块
+func TableOfContents() Block {
+	return Block{Object: "block", Type: TypeTableOfContents, TableOfContents: &EmptyObject{}}
+}
+
+// Equation 创建公式块，expression 为 KaTeX 表达式
+func Equation(expression string) Block {
+	return Block{Object: "block", Type: TypeEquation, Equation: &EquationBlock{Expression: expression}}
+}
+
+// ColumnList 创建分栏块，每个子块应为 Column
+func ColumnList(columns ...Block) Block {
+	return Block{Object: "block", Type: TypeColumnList, ColumnList: &ColumnListBlock{Children: columns}}
+}
+
+// Column 创建分栏中的一列
+func Column(children ...Block) Block {
+	return Block{Object: "block", Type: TypeColumn, Column: &ColumnBlock{Children: children}}
+}
+
+// Columns 创建分栏块，每个参数是一列的内容
+func Columns(columns ...[]Block) Block {
+	children := make([]Block, len(columns))
+	for i, column := range columns {
+		children[i] = Column(column...)
+	}
+	return ColumnList(children...)
+}
+
+// Breadcrumb 创建面包屑块
+func Breadcrumb() Block {
+	return Block{Object: "block", Type: TypeBreadcrumb, Breadcrumb: &EmptyObject{}}
+}
+
+// Synced 创建原始同步块
+func Synced(children ...Block) Block {
+	return Block{Object: "block", Type: TypeSyncedBlock, SyncedBlock: &SyncedBlock{Children: children}}
+}
+
+// SyncedCopy 创建引用 blockID 的同步块副本
+func SyncedCopy(blockID string) Block {
+	return Block{Object: "block", Type: TypeSyncedBlock, SyncedBlock: &SyncedBlock{
+		SyncedFrom: &SyncedFrom{BlockID: blockID},
+	}}
+}
+
+// Template 创建模板按钮块
+//
+// Notion 已停止支持创建模板块，此函数只用于兼容旧的工作区。
+func Template(text string, children ...Block) Block {
+	return Block{Object: "block", Type: TypeTemplate, Template: &TemplateBlock{
+		RichText: richTextOf(text, nil),
+		Children: children,
+	}}
+}
+
+// fields 返回块中可编辑的富文本、颜色和子块字段，不支持的字段返回 nil
+//
+// fields 会先复制块的内容，修改返回的字段不会影响其他共享该内容的块。
+func (b *Block) fields() (richText *[]RichText, color *Color, children *[]Block) {
+	switch {
+	case b.Type == TypeParagraph && b.Paragraph != nil:
+		v := *b.Paragraph
+		b.Paragraph = &v
+		return &v.RichText, &v.Color, &v.Children
+	case b.Type == TypeHeading1 && b.Heading1 != nil:
+		v := *b.Heading1
+		b.Heading1 = &v
+		return &v.RichText, &v.Color, &v.Children
+	case b.Type == TypeHeading2 && b.Heading2 != nil:
+		v := *b.Heading2
+		b.Heading2 = &v
+		return &v.RichText, &v.Color, &v.Children
+	case b.Type == TypeHeading3 && b.Heading3 != nil:
+		v := *b.Heading3
+		b.Heading3 = &v
+		return &v.RichText, &v.Color, &v.Children
+	case b.Type == TypeBulletedListItem && b.BulletedListItem != nil:
+		v := *b.BulletedListItem
+		b.BulletedListItem = &v
+		return &v.RichText, &v.Color, &v.Children
+	case b.Type == TypeNumberedListItem && b.NumberedListItem != nil:
+		v := *b.NumberedListItem
+		b.NumberedListItem = &v
+		return &v.RichText, &v.Color, &v.Children
+	case b.Type == TypeToDo && b.ToDo != nil:
+		v := *b.ToDo
+		b.ToDo = &v
+		return &v.RichText, &v.Color, &v.Children
+	case b.Type == TypeToggle && b.Toggle != nil:
+		v := *b.Toggle
+		b.Toggle = &v
+		return &v.RichText, &v.Color, &v.Children
+	case b.Type == TypeQuote && b.Quote != nil:
+		v := *b.Quote
+		b.Quote = &v
+		return &v.RichText, &v.Color, &v.Children
+	case b.Type == TypeCallout && b.Callout != nil:
+		v := *b.Callout
+		b.Callout = &v
+		return &v.RichText, &v.Color, &v.Children
+	case b.Type == TypeCode && b.Code != nil:
+		v := *b.Code
+		b.Code = &v
+		return &v.RichText, nil, nil
+	case b.Type == TypeTemplate && b.Template != nil:
+		v := *b.Template
+		b.Template = &v
+		return &v.RichText, nil, &v.Children
+	case b.Type == TypeSyncedBlock && b.SyncedBlock != nil:
+		v := *b.SyncedBlock
+		b.SyncedBlock = &v
+		return nil, nil, &v.Children
+	case b.Type == TypeColumn && b.Column != nil:
+		v := *b.Column
+		b.Column = &v
+		return nil, nil, &v.Children
+	case b.Type == TypeColumnList && b.ColumnList != nil:
+		v := *b.ColumnList
+		b.ColumnList = &v
+		return nil, nil, &v.Children
+	}
+	return nil, nil, nil
+}
+
+// WithRichText 返回替换了文本内容的块，不包含文本的块保持不变
+func (b Block) WithRichText(spans ...RichText) Block {
+	if richText, _, _ := b.fields(); richText != nil {
+		*richText = nonNilRichText(spans)
+	}
+	return b
+}
+
+// WithColor 返回设置了颜色的块，不支持颜色的块保持不变
+func (b Block) WithColor(color Color) Block {
+	if _, c, _ := b.fields(); c != nil {
+		*c = color
+	}
+	return b
+}
+
+// WithChildren 返回追加了子块的块，不支持子块的块保持不变
+//
+// 为标题块添加子块时会将其设置为可折叠标题。
+func (b Block) WithChildren(children ...Block) Block {
+	_, _, c := b.fields()
+	if c == nil {
+		return b
+	}
+	*c = append(append([]Block(nil), *c...), children...)
+	switch b.Type {
+	case TypeHeading1:
+		b.Heading1.IsToggleable = true
+	case TypeHeading2:
+		b.Heading2.IsToggleable = true
+	case TypeHeading3:
+		b.Heading3.IsToggleable = true
+	}
+	return b
+}
+
+// WithCaption 返回设置了说明文字的块，适用于代码、书签和媒体块
+func (b Block) WithCaption(caption string, spans ...RichText) Block {
+	rt := richTextOf(caption, spans)
+	switch {
+	case b.Type == TypeCode && b.Code != nil:
+		v := *b.Code
+		v.Caption = rt
+		b.Code = &v
+	case b.Type == TypeBookmark && b.Bookmark != nil:
+		v := *b.Bookmark
+		v.Caption = rt
+		b.Bookmark = &v
+	default:
+		if f := b.media(); f != nil && *f != nil {
+			v := **f
+			v.Caption = rt
+			*f = &v
+		}
+	}
+	return b
+}
+
+// media 返回媒体块的文件字段
+func (b *Block) media() **File {
+	switch b.Type {
+	case TypeImage:
+		return &b.Image
+	case TypeVideo:
+		return &b.Video
+	case TypeFile:
+		return &b.File
+	case TypePDF:
+		return &b.PDF
+	}
+	return nil
+}
+
+// WithHeaders 返回设置了表头的表格块，非表格块保持不变
+func (b Block) WithHeaders(columnHeader, rowHeader bool) Block {
+	if b.Type == TypeTable && b.Table != nil {
+		v := *b.Table
+		v.HasColumnHeader = columnHeader
+		v.HasRowHeader = rowHeader
+		b.Table = &v
+	}
+	return b
+}
@@ -0,0 +1,201 @@
+package notion
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+func TestBlockBuilders(t *testing.T) {
+	tests := []struct {
+		name  string
+		block Block
+		want  string
+	}{
+		{
+			"paragraph",
+			Paragraph("你好"),
+			`{"object":"block","type":"paragraph","paragraph":{"rich_text":[{"type":"text","text":{"content":"你好"},"plain_text":"你好"}],"color":"default"}}`,
+		},
+		{
+			"to_do",
+			ToDo("任务", true),
+			`{"object":"block","type":"to_do","to_do":{"rich_text":[{"type":"text","text":{"content":"任务"},"plain_text":"任务"}],"checked":true,"color":"default"}}`,
+		},
+		{
+			"code",
+			Code("go", "fmt.Println()"),
+			`{"object":"block","type":"code","code":{"rich_text":[{"type":"text","text":{"content":"fmt.Println()"},"plain_text":"fmt.Println()"}],"caption":[],"language":"go"}}`,
+		},
+		{
+			"callout",
+			Callout("💡", "提示"),
+			`{"object":"block","type":"callout","callout":{"rich_text":[{"type":"text","text":{"content":"提示"},"plain_text":"提示"}],"icon":{"type":"emoji","emoji":"💡"},"color":"default"}}`,
+		},
+		{
+			"divider",
+			Divider(),
+			`{"object":"block","type":"divider","divider":{}}`,
+		},
+		{
+			"image",
+			Image("https://example.com/a.png"),
+			`{"object":"block","type":"image","image":{"type":"external","external":{"url":"https://example.com/a.png"}}}`,
+		},
+		{
+			"equation",
+			Equation("e=mc^2"),
+			`{"object":"block","type":"equation","equation":{"expression":"e=mc^2"}}`,
+		},
+		{
+			"synced_copy",
+			SyncedCopy("block-1"),
+			`{"object":"block","type":"synced_block","synced_block":{"synced_from":{"block_id":"block-1"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.block)
+			if err != nil {
+				t.Fatalf("序列化失败: %v", err)
+			}
+			var got, want interface{}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("解码结果失败: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("解码期望值失败: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("JSON = %s\n期望 %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockMarshalOmitsReadOnlyFields(t *testing.T) {
+	var block Block
+	data := `{"object":"block","id":"b1","parent":{"type":"page_id","page_id":"p1"},"created_time":"2024-01-01T00:00:00.000Z","created_by":{"object":"user","id":"u1"},"has_children":true,"type":"divider","divider":{}}`
+	if err := json.Unmarshal([]byte(data), &block); err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	out, err := json.Marshal(block)
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+	for _, want := range []string{`"id":"b1"`, `"page_id":"p1"`, `"has_children":true`, `"created_by":{`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("JSON %s 缺少 %s", out, want)
+		}
+	}
+	if strings.Contains(string(out), "last_edited_by") {
+		t.Errorf("JSON %s 不应包含未设置的 last_edited_by", out)
+	}
+}
+
+func TestTableBuilder(t *testing.T) {
+	block := Table([][]string{{"名称", "数量"}, {"苹果"}}).WithHeaders(true, false)
+	if block.Table.TableWidth != 2 || !block.Table.HasColumnHeader {
+		t.Fatalf("表格 = %+v", block.Table)
+	}
+	if len(block.Table.Children) != 2 {
+		t.Fatalf("行数 = %d, 期望 2", len(block.Table.Children))
+	}
+	cells := block.Table.Children[1].TableRow.Cells
+	if len(cells) != 2 || len(cells[1]) != 0 {
+		t.Errorf("短行应补齐为 2 个单元格: %+v", cells)
+	}
+}
+
+func TestColumnsBuilder(t *testing.T) {
+	block := Columns([]Block{Paragraph("左")}, []Block{Paragraph("右"), Divider()})
+	if block.Type != TypeColumnList || len(block.ColumnList.Children) != 2 {
+		t.Fatalf("分栏 = %+v", block.ColumnList)
+	}
+	right := block.ColumnList.Children[1]
+	if right.Type != TypeColumn || len(right.Column.Children) != 2 {
+		t.Errorf("第二列 = %+v", right.Column)
+	}
+}
+
+func TestBlockModifiers(t *testing.T) {
+	base := Heading2("标题")
+	toggle := base.WithChildren(Paragraph("内容")).WithColor(ColorBlue)
+
+	if !toggle.Heading2.IsToggleable || len(toggle.Heading2.Children) != 1 || toggle.Heading2.Color != ColorBlue {
+		t.Errorf("标题 = %+v", toggle.Heading2)
+	}
+	if base.Heading2.IsToggleable || len(base.Heading2.Children) != 0 || base.Heading2.Color != ColorDefault {
+		t.Errorf("修改不应影响原来的块: %+v", base.Heading2)
+	}
+
+	rich := Paragraph("旧").WithRichText(Plain("新").Bold())
+	if plainText(rich.Paragraph.RichText) != "新" || !rich.Paragraph.RichText[0].Annotations.Bold {
+		t.Errorf("段落 = %+v", rich.Paragraph.RichText)
+	}
+
+	code := Code("", "x").WithCaption("说明")
+	if code.Code.Language != "plain text" || plainText(code.Code.Caption) != "说明" {
+		t.Errorf("代码块 = %+v", code.Code)
+	}
+
+	if divider := Divider().WithColor(ColorRed); divider.Divider == nil {
+		t.Error("不支持颜色的块应保持不变")
+	}
+}
+
+func TestRichTextBuilder(t *testing.T) {
+	rt := NewRichText().
+		Text("访问 ").
+		Link("官网", "https://go.dev").
+		Bold("粗体").
+		Color("红色", ColorRed).
+		Code("go version").
+		Build()
+
+	if len(rt) != 5 {
+		t.Fatalf("片段数 = %d, 期望 5", len(rt))
+	}
+	if rt[0].Annotations != nil {
+		t.Errorf("纯文本不应带注释: %+v", rt[0].Annotations)
+	}
+	if rt[1].Text.Link == nil || rt[1].Text.Link.URL != "https://go.dev" || rt[1].Href != "https://go.dev" {
+		t.Errorf("链接 = %+v", rt[1])
+	}
+	if !rt[2].Annotations.Bold || rt[2].Annotations.Color != ColorDefault {
+		t.Errorf("粗体 = %+v", rt[2].Annotations)
+	}
+	if rt[3].Annotations.Color != ColorRed {
+		t.Errorf("颜色 = %+v", rt[3].Annotations)
+	}
+	if !rt[4].Annotations.Code {
+		t.Errorf("代码 = %+v", rt[4].Annotations)
+	}
+	if got := plainText(rt); got != "访问 官网粗体红色go version" {
+		t.Errorf("纯文本 = %q", got)
+	}
+}
+
+func TestRichTextSplitsLongContent(t *testing.T) {
+	limit := errors.SizeLimits.MaxRichTextContent
+	content := strings.Repeat("文", limit*2+1)
+
+	block := Paragraph(content)
+	rt := block.Paragraph.RichText
+	if len(rt) != 3 {
+		t.Fatalf("片段数 = %d, 期望 3", len(rt))
+	}
+	for _, span := range rt {
+		if n := utf8.RuneCountInString(span.Text.Content); n > limit {
+			t.Errorf("片段长度 %d 超过限制 %d", n, limit)
+		}
+	}
+	if plainText(rt) != content {
+		t.Error("拆分后的内容应与原文相同")
+	}
+}
@@ -0,0 +1,90 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAppendChildrenSplitsRequests(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string][]int{} // 每个父块收到的每次请求的子块数
+	nested := map[string]bool{}    // 请求中是否出现了第三层子块
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parent := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/blocks/"), "/children")
+		var raw struct {
+			Children []map[string]json.RawMessage `json:"children"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+			t.Errorf("解码请求失败: %v", err)
+		}
+
+		mu.Lock()
+		requests[parent] = append(requests[parent], len(raw.Children))
+		for _, child := range raw.Children {
+			var typ string
+			_ = json.Unmarshal(child["type"], &typ)
+			var content struct {
+				Children []struct {
+					BulletedListItem struct {
+						Children []json.RawMessage `json:"children"`
+					} `json:"bulleted_list_item"`
+				} `json:"children"`
+			}
+			_ = json.Unmarshal(child[typ], &content)
+			for _, c := range content.Children {
+				if len(c.BulletedListItem.Children) > 0 {
+					nested[parent] = true
+				}
+			}
+		}
+		offset := 0
+		for _, n := range requests[parent][:len(requests[parent])-1] {
+			offset += n
+		}
+		mu.Unlock()
+
+		results := make([]string, len(raw.Children))
+		for i := range raw.Children {
+			results[i] = fmt.Sprintf(`{"object":"block","id":"%s-%d"}`, parent, offset+i)
+		}
+		fmt.Fprintf(w, `{"object":"list","results":[%s],"has_more":false}`, strings.Join(results, ","))
+	}))
+	defer ts.Close()
+
+	children := make([]Block, 250)
+	for i := range children {
+		children[i] = Paragraph(fmt.Sprint(i))
+	}
+	// 第 0 个块包含三层列表，第三层需要在第二层创建后追加
+	children[0] = BulletedListItem("1").WithChildren(
+		BulletedListItem("2").WithChildren(BulletedListItem("3")),
+	)
+
+	client := NewClient("test-token", WithBaseURL(ts.URL))
+	list, err := client.Blocks.AppendChildren(context.Background(), "root", children)
+	if err != nil {
+		t.Fatalf("追加子块失败: %v", err)
+	}
+	if len(list.Results) != 250 || list.Results[249].ID != "root-249" {
+		t.Fatalf("期望 250 个结果，得到 %d", len(list.Results))
+	}
+
+	if got := fmt.Sprint(requests["root"]); got != "[100 100 50]" {
+		t.Errorf("root 的请求 = %s, 期望 [100 100 50]", got)
+	}
+	if got := fmt.Sprint(requests["root-0"]); got != "[1]" {
+		t.Errorf("root-0 的请求 = %s, 期望 [1]", got)
+	}
+	for parent, ok := range nested {
+		if ok {
+			t.Errorf("%s 的请求包含超过两层的子块", parent)
+		}
+	}
+}
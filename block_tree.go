@@ -0,0 +1,212 @@
+package notion
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// defaultTreeWorkers 是获取块树的默认并发数，与 Notion 平均每秒 3 个请求的限制一致
+const defaultTreeWorkers = 3
+
+// TreeOptions 表示获取块树的选项
+type TreeOptions struct {
+	// Workers 是同时进行的请求数，默认为 3
+	Workers int
+
+	// MaxDepth 是获取的最大层数，1 表示只获取根块的直接子块，0 表示不限制
+	MaxDepth int
+
+	// ChildPages 为 true 时展开子页面的内容
+	ChildPages bool
+
+	// ChildDatabases 为 true 时查询子数据库的所有页面，作为 child_page 节点加入树中，
+	// 这些页面的内容是否展开由 ChildPages 决定
+	ChildDatabases bool
+
+	// Progress 在每次请求完成后调用，调用总是在 GetTree 所在的 goroutine 中依次进行
+	Progress func(TreeProgress)
+}
+
+// TreeProgress 表示获取块树的进度
+type TreeProgress struct {
+	Requests int // 已完成的子块列表或数据库查询数
+	Blocks   int // 已获取的块数
+	Pending  int // 等待中和进行中的请求数
+}
+
+// treeJob 表示获取一个块的所有子块的任务
+type treeJob struct {
+	blockID  string
+	depth    int          // 获取到的子块所在的层数
+	database bool         // 查询数据库中的页面，而不是列出子块
+	targets  []*BlockNode // 接收子块的节点，为空时表示根块
+
+	done     bool // 同步块的原始内容已经获取
+	children []*BlockNode
+}
+
+// treeResult 表示任务的结果
+type treeResult struct {
+	job    *treeJob
+	blocks []*Block
+	err    error
+}
+
+// GetTree 获取 rootID 下的完整块树
+//
+// 子块由 opts.Workers 个 goroutine 并发获取，每个块的所有分页都会被获取。
+// 同步块的副本通过 SyncedFrom.BlockID 获取原始块的内容，同一个原始块只获取一次，
+// 原始块和所有副本共享同一组子节点。默认不展开子页面和子数据库。
+// ctx 结束时返回 errors.ErrContextCanceled 或 errors.ErrRequestTimeout。
+func (s *BlockService) GetTree(ctx context.Context, rootID string, opts *TreeOptions) ([]*BlockNode, error) {
+	o := TreeOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Workers <= 0 {
+		o.Workers = defaultTreeWorkers
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	jobs := make(chan *treeJob)
+	results := make(chan treeResult)
+	var wg sync.WaitGroup
+	for i := 0; i < o.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				r := treeResult{job: job}
+				r.blocks, r.err = s.fetchJob(ctx, job)
+				select {
+				case results <- r:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	defer func() {
+		cancel()
+		close(jobs)
+		wg.Wait()
+	}()
+
+	var roots []*BlockNode
+	queue := []*treeJob{{blockID: rootID, depth: 1}}
+	synced := make(map[string]*treeJob)
+	progress := TreeProgress{}
+	for len(queue) > 0 || progress.Pending > 0 {
+		var send chan<- *treeJob
+		var next *treeJob
+		if len(queue) > 0 {
+			send, next = jobs, queue[0]
+		}
+
+		select {
+		case send <- next:
+			queue = queue[1:]
+			progress.Pending++
+		case r := <-results:
+			if r.err != nil {
+				return nil, r.err
+			}
+			nodes := make([]*BlockNode, len(r.blocks))
+			for i, b := range r.blocks {
+				nodes[i] = &BlockNode{Block: b}
+				if o.MaxDepth > 0 && r.job.depth >= o.MaxDepth {
+					continue
+				}
+				child := &treeJob{blockID: b.ID, depth: r.job.depth + 1, targets: []*BlockNode{nodes[i]}}
+				switch {
+				case b.Type == TypeSyncedBlock:
+					// 原始块和它的副本按原始块 ID 共享一次获取
+					id := b.ID
+					if b.SyncedBlock != nil && b.SyncedBlock.SyncedFrom != nil {
+						id = b.SyncedBlock.SyncedFrom.BlockID
+					} else if !b.HasChildren {
+						continue
+					}
+					if j, ok := synced[id]; ok {
+						if j.done {
+							nodes[i].Children = j.children
+						} else {
+							j.targets = append(j.targets, nodes[i])
+						}
+						continue
+					}
+					// 在加入队列之前登记，任务完成前遇到的引用追加到 targets
+					child.blockID = id
+					synced[id] = child
+				case b.Type == TypeChildDatabase:
+					if !o.ChildDatabases {
+						continue
+					}
+					child.database = true
+				case b.Type == TypeChildPage && !o.ChildPages, !b.HasChildren:
+					continue
+				}
+				queue = append(queue, child)
+			}
+
+			if r.job.targets == nil {
+				roots = nodes
+			}
+			for _, n := range r.job.targets {
+				n.Children = nodes
+			}
+			r.job.done, r.job.children = true, nodes
+
+			progress.Pending--
+			progress.Requests++
+			progress.Blocks += len(r.blocks)
+			if o.Progress != nil {
+				o.Progress(TreeProgress{Requests: progress.Requests, Blocks: progress.Blocks, Pending: progress.Pending + len(queue)})
+			}
+		case <-ctx.Done():
+			return nil, errors.FromContext(ctx.Err())
+		}
+	}
+	if roots == nil {
+		roots = []*BlockNode{}
+	}
+	return roots, nil
+}
+
+// fetchJob 获取任务对应的所有子块，数据库中的页面转换为 child_page 块
+func (s *BlockService) fetchJob(ctx context.Context, job *treeJob) ([]*Block, error) {
+	if !job.database {
+		return s.ListChildrenIter(ctx, job.blockID, nil).All(0)
+	}
+
+	pages, err := s.client.Database.QueryIter(ctx, job.blockID, nil).All(0)
+	if err != nil {
+		return nil, err
+	}
+	blocks := make([]*Block, len(pages))
+	for i, p := range pages {
+		title := ""
+		for _, v := range p.Properties {
+			if v.Type == PropertyTypeTitle {
+				title = v.PlainText()
+				break
+			}
+		}
+		blocks[i] = &Block{
+			Object:         "block",
+			ID:             p.ID,
+			Parent:         p.Parent,
+			Type:           TypeChildPage,
+			CreatedTime:    p.CreatedTime,
+			LastEditedTime: p.LastEditedTime,
+			CreatedBy:      p.CreatedBy,
+			LastEditedBy:   p.LastEditedBy,
+			HasChildren:    true,
+			Archived:       p.Archived,
+			ChildPage:      &ChildPageBlock{Title: title},
+		}
+	}
+	return blocks, nil
+}
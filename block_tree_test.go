@@ -0,0 +1,226 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// treeServer 返回模拟块树的测试服务器，children 的键是父块 ID，值是子块的 JSON
+func treeServer(children map[string][]string, requests map[string]int, mu *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+
+		if r.URL.Path == "/databases/db1/query" {
+			fmt.Fprint(w, `{"object":"list","results":[
+				{"object":"page","id":"row1","properties":{"Name":{"id":"title","type":"title","title":[{"plain_text":"行"}]}}}
+			],"has_more":false}`)
+			return
+		}
+
+		parent := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/blocks/"), "/children")
+		results, ok := children[parent]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"object":"error","status":404,"code":"object_not_found","message":"not found"}`)
+			return
+		}
+		// 每页最多两个块，用于验证分页
+		start := 0
+		fmt.Sscan(r.URL.Query().Get("start_cursor"), &start)
+		end := start + 2
+		if end > len(results) {
+			end = len(results)
+		}
+		more, next := "false", "null"
+		if end < len(results) {
+			more, next = "true", fmt.Sprintf(`"%d"`, end)
+		}
+		fmt.Fprintf(w, `{"object":"list","results":[%s],"has_more":%s,"next_cursor":%s}`,
+			strings.Join(results[start:end], ","), more, next)
+	}))
+}
+
+// paragraphJSON 返回段落块的 JSON
+func paragraphJSON(id string, hasChildren bool) string {
+	return fmt.Sprintf(`{"object":"block","id":"%s","type":"paragraph","has_children":%t,"paragraph":{"rich_text":[{"plain_text":"%s"}]}}`, id, hasChildren, id)
+}
+
+func TestGetTree(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	ts := treeServer(map[string][]string{
+		"root": {
+			paragraphJSON("a", true),
+			paragraphJSON("b", false),
+			`{"object":"block","id":"s1","type":"synced_block","has_children":true,"synced_block":{"synced_from":{"block_id":"orig"}}}`,
+			`{"object":"block","id":"s2","type":"synced_block","has_children":true,"synced_block":{"synced_from":{"block_id":"orig"}}}`,
+			`{"object":"block","id":"page","type":"child_page","has_children":true,"child_page":{"title":"子页面"}}`,
+		},
+		"a":    {paragraphJSON("a1", true), paragraphJSON("a2", false), paragraphJSON("a3", false)},
+		"a1":   {paragraphJSON("a1x", false)},
+		"orig": {paragraphJSON("o1", false)},
+	}, requests, &mu)
+	defer ts.Close()
+
+	var calls []TreeProgress
+	client := NewClient("test-token", WithBaseURL(ts.URL))
+	tree, err := client.Blocks.GetTree(context.Background(), "root", &TreeOptions{
+		Workers:  4,
+		Progress: func(p TreeProgress) { calls = append(calls, p) },
+	})
+	if err != nil {
+		t.Fatalf("获取块树失败: %v", err)
+	}
+
+	if len(tree) != 5 {
+		t.Fatalf("根节点数 = %d, 期望 5", len(tree))
+	}
+	if a := tree[0]; len(a.Children) != 3 || len(a.Children[0].Children) != 1 || a.Children[0].Children[0].ID != "a1x" {
+		t.Errorf("嵌套子块不完整: %+v", a.Children)
+	}
+	if tree[1].Children != nil {
+		t.Errorf("没有子块的节点不应请求子块")
+	}
+	for _, s := range tree[2:4] {
+		if len(s.Children) != 1 || s.Children[0].ID != "o1" {
+			t.Errorf("同步块 %s 的子块 = %+v, 期望原始块的内容", s.ID, s.Children)
+		}
+	}
+	if tree[4].Children != nil || requests["/blocks/page/children"] != 0 {
+		t.Error("默认不应展开子页面")
+	}
+	if requests["/blocks/orig/children"] != 1 {
+		t.Errorf("原始块请求次数 = %d, 期望 1", requests["/blocks/orig/children"])
+	}
+
+	if len(calls) != 4 {
+		t.Fatalf("进度回调次数 = %d, 期望 4", len(calls))
+	}
+	if last := calls[len(calls)-1]; last.Requests != 4 || last.Blocks != 10 || last.Pending != 0 {
+		t.Errorf("最后的进度 = %+v", last)
+	}
+}
+
+func TestGetTreeSyncedOnce(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	copyJSON := func(id string) string {
+		return `{"object":"block","id":"` + id + `","type":"synced_block","has_children":true,"synced_block":{"synced_from":{"block_id":"orig"}}}`
+	}
+	ts := treeServer(map[string][]string{
+		"root": {
+			`{"object":"block","id":"orig","type":"synced_block","has_children":true,"synced_block":{"synced_from":null}}`,
+			paragraphJSON("a", true),
+			paragraphJSON("b", true),
+		},
+		"a":    {copyJSON("s1")},
+		"b":    {paragraphJSON("b1", false), paragraphJSON("b2", false), copyJSON("s2")},
+		"orig": {paragraphJSON("o1", false)},
+	}, requests, &mu)
+	defer ts.Close()
+
+	client := NewClient("test-token", WithBaseURL(ts.URL))
+	tree, err := client.Blocks.GetTree(context.Background(), "root", &TreeOptions{Workers: 4})
+	if err != nil {
+		t.Fatalf("获取块树失败: %v", err)
+	}
+
+	if requests["/blocks/orig/children"] != 1 {
+		t.Errorf("原始块请求次数 = %d, 期望 1", requests["/blocks/orig/children"])
+	}
+	nodes := []*BlockNode{tree[0], tree[1].Children[0], tree[2].Children[2]}
+	for _, n := range nodes {
+		if len(n.Children) != 1 || n.Children[0].ID != "o1" {
+			t.Errorf("同步块 %s 的子块 = %+v, 期望原始块的内容", n.ID, n.Children)
+		}
+	}
+}
+
+func TestGetTreeOptions(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	ts := treeServer(map[string][]string{
+		"root": {
+			paragraphJSON("a", true),
+			`{"object":"block","id":"page","type":"child_page","has_children":true,"child_page":{"title":"子页面"}}`,
+			`{"object":"block","id":"db1","type":"child_database","child_database":{"title":"数据库"}}`,
+		},
+		"a":    {paragraphJSON("a1", true)},
+		"page": {paragraphJSON("p1", true)},
+		"row1": {paragraphJSON("r1", false)},
+	}, requests, &mu)
+	defer ts.Close()
+
+	client := NewClient("test-token", WithBaseURL(ts.URL))
+	tree, err := client.Blocks.GetTree(context.Background(), "root", &TreeOptions{
+		MaxDepth:       2,
+		ChildPages:     true,
+		ChildDatabases: true,
+	})
+	if err != nil {
+		t.Fatalf("获取块树失败: %v", err)
+	}
+
+	if requests["/blocks/a/children"] != 1 || requests["/blocks/a1/children"] != 0 || requests["/blocks/p1/children"] != 0 {
+		t.Errorf("MaxDepth 为 2 时只应获取两层: %v", requests)
+	}
+	if page := tree[1]; len(page.Children) != 1 || page.Children[0].ID != "p1" {
+		t.Errorf("子页面的内容 = %+v", page.Children)
+	}
+	db := tree[2]
+	if len(db.Children) != 1 || db.Children[0].Type != TypeChildPage || db.Children[0].ChildPage.Title != "行" {
+		t.Fatalf("子数据库的页面 = %+v", db.Children)
+	}
+	if requests["/blocks/row1/children"] != 0 {
+		t.Error("超过 MaxDepth 的数据库页面不应展开")
+	}
+}
+
+func TestGetTreeError(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	ts := treeServer(map[string][]string{
+		"root": {paragraphJSON("missing", true), paragraphJSON("b", false)},
+	}, requests, &mu)
+	defer ts.Close()
+
+	client := NewClient("test-token", WithBaseURL(ts.URL))
+	if _, err := client.Blocks.GetTree(context.Background(), "root", nil); err == nil {
+		t.Fatal("子块请求失败时应返回错误")
+	}
+}
+
+func TestGetTreeContext(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	client := NewClient("test-token", WithBaseURL(ts.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := client.Blocks.GetTree(ctx, "root", nil); !errors.IsCanceled(err) {
+		t.Errorf("取消 ctx 时应返回 ErrContextCanceled，得到 %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Blocks.GetTree(ctx, "root", nil); !errors.IsTimeout(err) {
+		t.Errorf("ctx 超时时应返回 ErrRequestTimeout，得到 %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+// Package cache 提供 Notion API 响应的缓存
+//
+// 缓存通过 notion.WithCache 接入客户端，只缓存页面、数据库、用户和块的 GET 请求。
+// 同一个客户端发出的修改请求会使相关的缓存失效，其他响应中出现的 last_edited_time
+// 用于重新验证缓存：时间相同的条目被续期，不同的条目被删除。
+package cache
+
+import (
+	"time"
+)
+
+const (
+	// DefaultCapacity 是内存缓存默认的最大条目数
+	DefaultCapacity = 1000
+	// DefaultTTL 是默认的缓存有效期
+	DefaultTTL = 5 * time.Minute
+)
+
+// Entry 表示一条缓存的响应
+type Entry struct {
+	Body           []byte    `json:"body"`                       // 响应体
+	LastEditedTime string    `json:"last_edited_time,omitempty"` // 响应对象的最后编辑时间
+	StoredAt       time.Time `json:"stored_at"`                  // 写入或续期的时间
+}
+
+// Cache 表示响应缓存，实现必须是并发安全的
+type Cache interface {
+	// Get 返回未过期的条目
+	Get(key string) (*Entry, bool)
+	// Set 写入条目，已存在时覆盖
+	Set(key string, entry *Entry)
+	// Delete 删除条目
+	Delete(key string)
+	// DeletePrefix 删除所有以 prefix 开头的条目
+	DeletePrefix(prefix string)
+}
+
+// Option 表示缓存的配置选项
+type Option func(*options)
+
+// options 保存缓存的配置
+type options struct {
+	capacity int
+	ttl      time.Duration
+}
+
+// defaultOptions 返回默认配置
+func defaultOptions() *options {
+	return &options{
+		capacity: DefaultCapacity,
+		ttl:      DefaultTTL,
+	}
+}
+
+// WithCapacity 设置内存缓存的最大条目数，超过时淘汰最久未使用的条目，0 表示不限制
+func WithCapacity(n int) Option {
+	return func(o *options) {
+		o.capacity = n
+	}
+}
+
+// WithTTL 设置条目的有效期，0 表示永不过期
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// expired 判断条目是否已经过期
+func (o *options) expired(e *Entry) bool {
+	return o.ttl > 0 && time.Since(e.StoredAt) > o.ttl
+}
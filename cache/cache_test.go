@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// entry 创建测试使用的条目
+func entry(body string) *Entry {
+	return &Entry{Body: []byte(body), StoredAt: time.Now()}
+}
+
+// testCache 验证所有实现共同的行为
+func testCache(t *testing.T, c Cache) {
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("空缓存不应命中")
+	}
+
+	c.Set("a", entry("1"))
+	c.Set("blocks/x/children", entry("2"))
+	c.Set("blocks/x/children?page_size=10", entry("3"))
+	c.Set("blocks/y/children", entry("4"))
+
+	if e, ok := c.Get("a"); !ok || string(e.Body) != "1" {
+		t.Fatalf("Get(a) = %v, %v", e, ok)
+	}
+	c.Set("a", entry("5"))
+	if e, _ := c.Get("a"); string(e.Body) != "5" {
+		t.Errorf("覆盖后的值 = %s, 期望 5", e.Body)
+	}
+
+	c.DeletePrefix("blocks/x/children")
+	if _, ok := c.Get("blocks/x/children"); ok {
+		t.Error("前缀匹配的条目应被删除")
+	}
+	if _, ok := c.Get("blocks/x/children?page_size=10"); ok {
+		t.Error("带查询参数的条目应被删除")
+	}
+	if _, ok := c.Get("blocks/y/children"); !ok {
+		t.Error("前缀不匹配的条目不应被删除")
+	}
+
+	c.Delete("a")
+	if _, ok := c.Get("a"); ok {
+		t.Error("删除后不应命中")
+	}
+
+	c.Set("old", &Entry{Body: []byte("x"), StoredAt: time.Now().Add(-time.Hour)})
+	if _, ok := c.Get("old"); ok {
+		t.Error("过期的条目不应命中")
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	testCache(t, NewMemoryCache())
+}
+
+func TestMemoryCacheLRU(t *testing.T) {
+	c := NewMemoryCache(WithCapacity(2), WithTTL(0))
+	c.Set("a", entry("1"))
+	c.Set("b", entry("2"))
+	c.Get("a") // a 最近被使用，b 应被淘汰
+	c.Set("c", entry("3"))
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("最久未使用的条目应被淘汰")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Error("最近使用的条目不应被淘汰")
+	}
+	if c.Len() != 2 {
+		t.Errorf("条目数 = %d, 期望 2", c.Len())
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewFileCache(dir)
+	if err != nil {
+		t.Fatalf("创建文件缓存失败: %v", err)
+	}
+	testCache(t, c)
+
+	// 新的实例可以读取之前写入的条目
+	c.Set("persist", &Entry{Body: []byte("ok"), LastEditedTime: "2024-01-01T00:00:00.000Z", StoredAt: time.Now()})
+	reopened, err := NewFileCache(dir)
+	if err != nil {
+		t.Fatalf("重新打开文件缓存失败: %v", err)
+	}
+	e, ok := reopened.Get("persist")
+	if !ok || string(e.Body) != "ok" || e.LastEditedTime != "2024-01-01T00:00:00.000Z" {
+		t.Errorf("重新打开后的条目 = %+v, %v", e, ok)
+	}
+}
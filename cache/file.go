@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileCache 是保存在目录中的缓存，每个条目一个文件，可以在进程之间保留
+//
+// 文件名是键的 SHA-256，DeletePrefix 需要读取目录中的所有条目。
+type FileCache struct {
+	mu   sync.Mutex
+	dir  string
+	opts *options
+}
+
+// fileItem 表示缓存文件的内容
+type fileItem struct {
+	Key   string `json:"key"`
+	Entry *Entry `json:"entry"`
+}
+
+// NewFileCache 创建保存在 dir 中的缓存，目录不存在时自动创建，默认有效期 5 分钟
+func NewFileCache(dir string, opts ...Option) (*FileCache, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileCache{dir: dir, opts: o}, nil
+}
+
+// path 返回键对应的文件路径
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get 实现 Cache
+func (c *FileCache) Get(key string) (*Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, err := readItem(c.path(key))
+	if err != nil || item.Key != key || item.Entry == nil {
+		return nil, false
+	}
+	if c.opts.expired(item.Entry) {
+		os.Remove(c.path(key))
+		return nil, false
+	}
+	return item.Entry, true
+}
+
+// Set 实现 Cache，写入失败时忽略
+func (c *FileCache) Set(key string, entry *Entry) {
+	data, err := json.Marshal(fileItem{Key: key, Entry: entry})
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 先写入临时文件再重命名，避免其他进程读到不完整的内容
+	tmp, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Delete 实现 Cache
+func (c *FileCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	os.Remove(c.path(key))
+}
+
+// DeletePrefix 实现 Cache
+func (c *FileCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return
+	}
+	for _, name := range files {
+		if item, err := readItem(name); err == nil && strings.HasPrefix(item.Key, prefix) {
+			os.Remove(name)
+		}
+	}
+}
+
+// readItem 读取缓存文件
+func readItem(name string) (*fileItem, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	item := new(fileItem)
+	if err := json.Unmarshal(data, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
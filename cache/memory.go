@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// MemoryCache 是带 LRU 淘汰和有效期的内存缓存
+type MemoryCache struct {
+	mu    sync.Mutex
+	opts  *options
+	order *list.List // 最近使用的条目在前
+	items map[string]*list.Element
+}
+
+// memoryItem 表示内存缓存中的条目
+type memoryItem struct {
+	key   string
+	entry *Entry
+}
+
+// NewMemoryCache 创建内存缓存，默认最多保存 1000 个条目，有效期 5 分钟
+func NewMemoryCache(opts ...Option) *MemoryCache {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &MemoryCache{
+		opts:  o,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// Get 实现 Cache
+func (c *MemoryCache) Get(key string) (*Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	item := el.Value.(*memoryItem)
+	if c.opts.expired(item.entry) {
+		c.remove(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return item.entry, true
+}
+
+// Set 实现 Cache
+func (c *MemoryCache) Set(key string, entry *Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*memoryItem).entry = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&memoryItem{key: key, entry: entry})
+	for c.opts.capacity > 0 && c.order.Len() > c.opts.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete 实现 Cache
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+// DeletePrefix 实现 Cache
+func (c *MemoryCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(el)
+		}
+	}
+}
+
+// Len 返回缓存中的条目数，包括已过期但尚未清理的条目
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove 删除条目，调用方必须持有锁
+func (c *MemoryCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*memoryItem).key)
+}
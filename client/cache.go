@@ -0,0 +1,173 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/kuekiko/NotionGO/cache"
+)
+
+// cachedObject 是响应中用于失效和重新验证缓存的字段
+type cachedObject struct {
+	Object         string `json:"object"`
+	ID             string `json:"id"`
+	LastEditedTime string `json:"last_edited_time"`
+	Parent         struct {
+		PageID     string `json:"page_id"`
+		BlockID    string `json:"block_id"`
+		DatabaseID string `json:"database_id"`
+	} `json:"parent"`
+	Results []cachedObject `json:"results"`
+}
+
+// cacheScope 返回令牌对应的键前缀，避免共用缓存的不同集成读到彼此的数据
+func cacheScope(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8]) + ":"
+}
+
+// splitPath 拆分路径和查询字符串，返回路径的各段
+func splitPath(path string) []string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// cacheKey 返回 scope 中路径对应的缓存键，对象 ID 去掉连字符并转为小写，使两种写法的 ID 对应同一个条目
+func cacheKey(scope, path string) string {
+	query := ""
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path, query = path[:i], path[i:]
+	}
+	seg := strings.Split(strings.Trim(path, "/"), "/")
+	if len(seg) >= 2 {
+		seg[1] = strings.ToLower(strings.ReplaceAll(seg[1], "-", ""))
+	}
+	return scope + strings.Join(seg, "/") + query
+}
+
+// cacheable 判断 GET 请求的响应是否可以缓存：
+// pages/{id}、databases/{id}、users、users/{id}、blocks/{id} 和 blocks/{id}/children
+func cacheable(path string) bool {
+	seg := splitPath(path)
+	switch {
+	case len(seg) == 1:
+		return seg[0] == "users"
+	case len(seg) == 2:
+		return seg[0] == "pages" || seg[0] == "databases" || seg[0] == "users" || seg[0] == "blocks"
+	case len(seg) == 3:
+		return seg[0] == "blocks" && seg[2] == "children"
+	}
+	return false
+}
+
+// cachedResponse 返回缓存的响应体
+func (c *Client) cachedResponse(scope, method, path string) ([]byte, bool) {
+	if c.cache == nil || method != "GET" || !cacheable(path) {
+		return nil, false
+	}
+	e, ok := c.cache.Get(cacheKey(scope, path))
+	if !ok {
+		return nil, false
+	}
+	return e.Body, true
+}
+
+// updateCache 根据响应写入、失效和重新验证缓存
+func (c *Client) updateCache(scope, method, path string, body []byte) {
+	if c.cache == nil {
+		return
+	}
+	var obj cachedObject
+	_ = json.Unmarshal(body, &obj)
+
+	if method != "GET" {
+		c.invalidate(scope, method, path, &obj)
+	}
+	c.revalidate(scope, &obj)
+	for i := range obj.Results {
+		c.revalidate(scope, &obj.Results[i])
+	}
+
+	if method == "GET" && cacheable(path) {
+		c.cache.Set(cacheKey(scope, path), &cache.Entry{
+			Body:           append([]byte(nil), body...),
+			LastEditedTime: obj.LastEditedTime,
+			StoredAt:       time.Now(),
+		})
+	}
+}
+
+// invalidate 删除修改请求影响的缓存
+func (c *Client) invalidate(scope, method, path string, obj *cachedObject) {
+	seg := splitPath(path)
+	if len(seg) >= 2 {
+		id := seg[1]
+		switch seg[0] {
+		case "pages", "blocks":
+			// 页面也可以作为块获取，页面的内容是它的子块
+			if len(seg) == 2 || seg[2] == "children" {
+				c.cache.Delete(cacheKey(scope, "pages/"+id))
+				c.cache.Delete(cacheKey(scope, "blocks/"+id))
+				c.cache.DeletePrefix(cacheKey(scope, "blocks/"+id+"/children"))
+			}
+		case "databases":
+			if len(seg) == 2 {
+				c.cache.Delete(cacheKey(scope, "databases/"+id))
+			}
+		}
+	}
+
+	// 创建、修改和删除都会改变父对象的子块列表，POST 请求中只有创建页面和数据库会修改内容
+	if method == "POST" && !(len(seg) == 1 && (seg[0] == "pages" || seg[0] == "databases")) {
+		return
+	}
+	for _, parent := range []string{obj.Parent.PageID, obj.Parent.BlockID} {
+		if parent != "" {
+			c.cache.DeletePrefix(cacheKey(scope, "blocks/"+parent+"/children"))
+		}
+	}
+}
+
+// revalidate 用响应中的最后编辑时间检查缓存的同一对象：时间相同则续期，不同则删除
+func (c *Client) revalidate(scope string, obj *cachedObject) {
+	if obj.ID == "" || obj.LastEditedTime == "" {
+		return
+	}
+	var keys []string
+	switch obj.Object {
+	case "page":
+		keys = []string{"pages/" + obj.ID, "blocks/" + obj.ID}
+	case "block":
+		keys = []string{"blocks/" + obj.ID}
+	case "database":
+		keys = []string{"databases/" + obj.ID}
+	default:
+		return
+	}
+
+	changed := false
+	for _, key := range keys {
+		e, ok := c.cache.Get(cacheKey(scope, key))
+		if !ok {
+			continue
+		}
+		if e.LastEditedTime != obj.LastEditedTime {
+			c.cache.Delete(cacheKey(scope, key))
+			changed = true
+			continue
+		}
+		c.cache.Set(cacheKey(scope, key), &cache.Entry{
+			Body:           e.Body,
+			LastEditedTime: e.LastEditedTime,
+			StoredAt:       time.Now(),
+		})
+	}
+	if changed {
+		c.cache.DeletePrefix(cacheKey(scope, "blocks/"+obj.ID+"/children"))
+	}
+}
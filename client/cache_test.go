@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kuekiko/NotionGO/cache"
+)
+
+// cacheServer serves a page, its children and a database query whose results
+// report the page's current last_edited_time
+type cacheServer struct {
+	mu       sync.Mutex
+	edited   string
+	requests map[string]int
+}
+
+func (s *cacheServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[r.Method+" "+r.URL.Path]++
+
+	page := fmt.Sprintf(`{"object":"page","id":"1111-2222","last_edited_time":%q,"parent":{"type":"workspace","workspace":true}}`, s.edited)
+	switch r.Method + " " + r.URL.Path {
+	case "GET /pages/11112222", "GET /pages/1111-2222", "PATCH /pages/1111-2222":
+		w.Write([]byte(page))
+	case "GET /blocks/1111-2222/children":
+		w.Write([]byte(`{"object":"list","results":[],"has_more":false}`))
+	case "POST /databases/db/query":
+		fmt.Fprintf(w, `{"object":"list","results":[%s],"has_more":false}`, page)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"not found"}`))
+	}
+}
+
+func (s *cacheServer) count(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[key]
+}
+
+func newCacheTestClient(t *testing.T) (*Client, *cacheServer) {
+	srv := &cacheServer{edited: "2024-01-01T00:00:00.000Z", requests: map[string]int{}}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return NewClient("test-token", WithBaseURL(ts.URL), WithCache(cache.NewMemoryCache())), srv
+}
+
+func TestCacheGet(t *testing.T) {
+	client, srv := newCacheTestClient(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		var page map[string]interface{}
+		if err := client.Get(ctx, "pages/1111-2222", nil, &page); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if page["id"] != "1111-2222" {
+			t.Fatalf("Unexpected page: %v", page)
+		}
+	}
+	if n := srv.count("GET /pages/1111-2222"); n != 1 {
+		t.Errorf("Expected 1 request, got %d", n)
+	}
+
+	// The same page without dashes hits the same entry
+	if err := client.Get(ctx, "pages/11112222", nil, nil); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if n := srv.count("GET /pages/11112222"); n != 0 {
+		t.Errorf("Expected undashed ID to be served from cache, got %d requests", n)
+	}
+
+	// Uncached endpoints always reach the server
+	for i := 0; i < 2; i++ {
+		client.Post(ctx, "databases/db/query", nil, nil)
+	}
+	if n := srv.count("POST /databases/db/query"); n != 2 {
+		t.Errorf("Expected POST requests not to be cached, got %d", n)
+	}
+}
+
+func TestCacheInvalidatedByPatch(t *testing.T) {
+	client, srv := newCacheTestClient(t)
+	ctx := context.Background()
+
+	client.Get(ctx, "pages/1111-2222", nil, nil)
+	client.Get(ctx, "blocks/1111-2222/children", nil, nil)
+	if err := client.Patch(ctx, "pages/1111-2222", map[string]interface{}{}, nil); err != nil {
+		t.Fatalf("Patch failed: %v", err)
+	}
+	client.Get(ctx, "pages/1111-2222", nil, nil)
+	client.Get(ctx, "blocks/1111-2222/children", nil, nil)
+
+	if n := srv.count("GET /pages/1111-2222"); n != 2 {
+		t.Errorf("Expected page to be refetched after PATCH, got %d requests", n)
+	}
+	if n := srv.count("GET /blocks/1111-2222/children"); n != 2 {
+		t.Errorf("Expected children to be refetched after PATCH, got %d requests", n)
+	}
+}
+
+func TestCacheRevalidation(t *testing.T) {
+	client, srv := newCacheTestClient(t)
+	ctx := context.Background()
+
+	client.Get(ctx, "pages/1111-2222", nil, nil)
+	client.Get(ctx, "blocks/1111-2222/children", nil, nil)
+
+	// A query reporting the same last_edited_time keeps the entries
+	client.Post(ctx, "databases/db/query", nil, nil)
+	client.Get(ctx, "pages/1111-2222", nil, nil)
+	client.Get(ctx, "blocks/1111-2222/children", nil, nil)
+	if n := srv.count("GET /pages/1111-2222"); n != 1 {
+		t.Errorf("Expected unchanged page to stay cached, got %d requests", n)
+	}
+
+	// The page is edited elsewhere; the next query reveals it
+	srv.mu.Lock()
+	srv.edited = "2024-02-01T00:00:00.000Z"
+	srv.mu.Unlock()
+	client.Post(ctx, "databases/db/query", nil, nil)
+	client.Get(ctx, "pages/1111-2222", nil, nil)
+	client.Get(ctx, "blocks/1111-2222/children", nil, nil)
+	if n := srv.count("GET /pages/1111-2222"); n != 2 {
+		t.Errorf("Expected changed page to be refetched, got %d requests", n)
+	}
+	if n := srv.count("GET /blocks/1111-2222/children"); n != 2 {
+		t.Errorf("Expected children of changed page to be refetched, got %d requests", n)
+	}
+}
+
+func TestCacheScopedByToken(t *testing.T) {
+	srv := &cacheServer{edited: "2024-01-01T00:00:00.000Z", requests: map[string]int{}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	shared := cache.NewMemoryCache()
+	a := NewClient("token-a", WithBaseURL(ts.URL), WithCache(shared))
+	b := NewClient("token-b", WithBaseURL(ts.URL), WithCache(shared))
+	a.Get(context.Background(), "pages/1111-2222", nil, nil)
+	b.Get(context.Background(), "pages/1111-2222", nil, nil)
+
+	if n := srv.count("GET /pages/1111-2222"); n != 2 {
+		t.Errorf("Expected clients with different tokens not to share entries, got %d requests", n)
+	}
+}
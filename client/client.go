@@ -3,9 +3,17 @@ package client
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/kuekiko/NotionGO/cache"
+	"github.com/kuekiko/NotionGO/errors"
 	"github.com/valyala/fasthttp"
 )
 
@@ -14,150 +22,456 @@ const (
 	BaseURL = "https://api.notion.com/v1/"
 	// APIVersion 是 Notion API 的版本
 	APIVersion = "2022-06-28"
+	// DefaultUserAgent 是默认的 User-Agent
+	DefaultUserAgent = "NotionGO"
 )
 
 // Client 表示 Notion API 客户端
 type Client struct {
-	apiKey       string
-	httpClient   *fasthttp.Client
-	retryCount   int
-	retryWaitMin time.Duration
-	retryWaitMax time.Duration
+	apiKey          string
+	authScheme      string
+	baseURL         string
+	apiVersion      string
+	userAgent       string
+	timeout         time.Duration
+	transport       Transport
+	middleware      []Middleware
+	handler         Handler // 包含所有中间件的处理链
+	retryCount      int
+	retryWaitMin    time.Duration
+	retryWaitMax    time.Duration
+	retryPolicy     RetryPolicy
+	logger          Logger
+	logBodySize     int
+	limiter         RateLimiter
+	newLimiter      func() RateLimiter // 为 ctx 覆盖的令牌创建速率限制器，为 nil 时所有令牌共用 limiter
+	limiters        limiterSet
+	sharedRateLimit bool
+	sem             semaphore
+	cache           cache.Cache
+	cacheScope      string
+	telemetry       *telemetry // 没有设置 TracerProvider 和 MeterProvider 时为 nil
 }
 
 // NewClient 创建一个新的客户端
-func NewClient(apiKey string) *Client {
-	return &Client{
-		apiKey: apiKey,
-		httpClient: &fasthttp.Client{
-			Name:                          "NotionGO",
-			MaxConnsPerHost:               10000,
-			MaxIdleConnDuration:           10 * time.Second,
-			ReadTimeout:                   10 * time.Second,
-			WriteTimeout:                  10 * time.Second,
-			MaxResponseBodySize:           50 * 1024 * 1024, // 50MB
-			DisableHeaderNamesNormalizing: true,
-			MaxConnWaitTimeout:            30 * time.Second,
-		},
-		retryCount:   3,
-		retryWaitMin: 1 * time.Second,
-		retryWaitMax: 30 * time.Second,
+func NewClient(apiKey string, opts ...Option) *Client {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
 	}
+	if o.logger == nil {
+		o.logger = nopLogger{}
+	}
+
+	authScheme := "Bearer"
+	if o.basicAuth != "" {
+		// 编码后的凭据和令牌一样在日志中被隐藏
+		apiKey, authScheme = o.basicAuth, "Basic"
+	}
+
+	limiter := o.rateLimiter
+	var newLimiter func() RateLimiter
+	if limiter == nil && o.rateLimit > 0 {
+		newLimiter = func() RateLimiter { return NewTokenBucket(o.rateLimit, o.rateBurst) }
+		if o.sharedRateLimit {
+			limiter = sharedLimiter(apiKey, newLimiter)
+			if b, ok := limiter.(*TokenBucket); ok && !b.matches(o.rateLimit, o.rateBurst) {
+				o.logger.Log(context.Background(), LogLevelWarn, "notion shared rate limit differs",
+					"rate", o.rateLimit,
+					"burst", o.rateBurst,
+					"shared_rate", b.rate,
+					"shared_burst", b.burst,
+				)
+			}
+		} else {
+			limiter = newLimiter()
+		}
+	}
+	var sem semaphore
+	if o.maxConcurrent > 0 {
+		sem = make(semaphore, o.maxConcurrent)
+	}
+
+	transport := o.transport
+	if transport == nil {
+		switch {
+		case o.httpClient != nil:
+			transport = &httpTransport{client: o.httpClient, maxResponseBodySize: o.maxResponseBodySize}
+		case o.netHTTP:
+			transport = newHTTPTransport(o)
+		default:
+			transport = newFastHTTPTransport(o)
+		}
+	}
+
+	c := &Client{
+		apiKey:          apiKey,
+		authScheme:      authScheme,
+		baseURL:         o.baseURL,
+		apiVersion:      o.apiVersion,
+		userAgent:       o.userAgent,
+		timeout:         o.timeout,
+		transport:       transport,
+		retryCount:      o.retryCount,
+		retryWaitMin:    o.retryWaitMin,
+		retryWaitMax:    o.retryWaitMax,
+		retryPolicy:     o.retryPolicy,
+		logger:          o.logger,
+		logBodySize:     o.logBodySize,
+		limiter:         limiter,
+		newLimiter:      newLimiter,
+		sharedRateLimit: o.sharedRateLimit,
+		sem:             sem,
+		cache:           o.cache,
+		cacheScope:      cacheScope(apiKey),
+		telemetry:       newTelemetry(o.tracerProvider, o.meterProvider),
+	}
+	c.middleware = o.middleware
+	c.handler = chain(o.middleware, c.roundTrip)
+	return c
+}
+
+// BaseURL 返回 API 基础 URL，以 / 结尾
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// SetRetryPolicy 设置重试策略，传入 nil 时恢复默认的指数退避策略
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retryPolicy = policy
 }
 
 // Do 执行 HTTP 请求
+//
+// ctx 带有截止时间时，截止时间会作为请求的期限传给 Transport，超时后请求会被真正中断；
+// ctx 被取消时 Do 立即返回 errors.ErrContextCanceled，Transport 关闭请求使用的连接，请求结束后释放资源。
+// 每次发送前先等待速率限制器和并发限制，收到 429 时速率限制器按 Retry-After 暂停所有请求。
+// 失败的请求按重试策略重试，重试之间的等待同样受 ctx 控制。
+// 最终得到非 2xx 响应时返回解析自响应体的 *errors.Error。
+// ctx 可以通过 ContextWithToken 和 ContextWithAPIVersion 覆盖这次调用的令牌和 API 版本。
+// 请求依次经过 WithMiddleware 添加的中间件。
+// 设置了 WithTracerProvider 时每次调用记录一个 span，重试包含在同一个 span 中。
+// 调用方负责使用 fasthttp.ReleaseResponse 释放返回的响应。
 func (c *Client) Do(ctx context.Context, method, path string, body interface{}) (*fasthttp.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req := &Request{Method: method, Path: path, Body: body}
+	if len(c.middleware) == 0 {
+		// 没有中间件时直接返回 Transport 的响应，避免复制响应体
+		return c.do(ctx, c.credentials(ctx), req)
+	}
+	resp, err := c.call(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.fastHTTPResponse(), nil
+}
+
+// do 使用 cr 中的令牌执行 HTTP 请求，包括速率限制和重试
+func (c *Client) do(ctx context.Context, cr credentials, r *Request) (_ *fasthttp.Response, err error) {
+	method, path, body := r.Method, r.Path, r.Body
+	ctx, tel := c.telemetry.start(ctx, method, path)
+	defer func() { tel.end(err) }()
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	// 如果有请求体，编码为 JSON
+	var jsonBody []byte
+	if body != nil {
+		var err error
+		jsonBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("编码请求体失败: %v", err)
+		}
+	}
+
+	policy := c.retryPolicy
+	if policy == nil {
+		policy = &ExponentialBackoff{
+			MaxRetries: c.retryCount,
+			WaitMin:    c.retryWaitMin,
+			WaitMax:    c.retryWaitMax,
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.FromContext(err)
+		}
+
+		if cr.limiter != nil {
+			if err := tel.waitLimiter(ctx, cr.limiter); err != nil {
+				return nil, errors.FromContext(err)
+			}
+		}
+		if err := c.sem.acquire(ctx); err != nil {
+			return nil, errors.FromContext(err)
+		}
+		start := time.Now()
+		resp, err := c.send(ctx, cr, method, path, r.Header, jsonBody)
+		latency := time.Since(start)
+		c.sem.release()
+
+		a := &Attempt{Method: method, Path: path, Number: attempt, Err: err}
+		fields := []interface{}{
+			"method", method,
+			"path", path,
+			"attempt", attempt,
+			"latency", latency,
+		}
+		received, notionRequestID := 0, ""
+		if resp != nil {
+			a.StatusCode = resp.StatusCode()
+			a.RetryAfter = parseRetryAfter(string(resp.Header.Peek("Retry-After")))
+			received, notionRequestID = len(resp.Body()), requestID(resp)
+			fields = append(fields, "status", a.StatusCode, "request_id", notionRequestID)
+			if c.logBodySize > 0 {
+				c.logger.Log(ctx, LogLevelDebug, "notion response", append(fields, "body", c.logBody(cr, resp.Body()))...)
+			}
+		}
+		if err != nil {
+			fields = append(fields, "error", cr.redact(err.Error()))
+		}
+		tel.attempt(ctx, a, latency, len(jsonBody), received, notionRequestID)
+
+		if err == nil && a.StatusCode < 300 {
+			c.logger.Log(ctx, LogLevelDebug, "notion request completed", fields...)
+			return resp, nil
+		}
+		if errors.IsCanceled(err) || errors.IsTimeout(err) {
+			c.logger.Log(ctx, LogLevelWarn, "notion request aborted", fields...)
+			return nil, err
+		}
+
+		wait, retry := policy.Backoff(a)
+		if a.StatusCode == http.StatusTooManyRequests && cr.limiter != nil {
+			pause := a.RetryAfter
+			if pause <= 0 && retry {
+				pause = wait
+			}
+			cr.limiter.Pause(pause)
+		}
+		if !retry {
+			c.logger.Log(ctx, LogLevelError, "notion request failed", fields...)
+			if err != nil {
+				return nil, err
+			}
+			defer fasthttp.ReleaseResponse(resp)
+			return nil, apiError(resp, a.RetryAfter)
+		}
+		c.logger.Log(ctx, LogLevelWarn, "notion request retrying", append(fields, "wait", wait)...)
+		tel.retry(a, wait)
+		if resp != nil {
+			fasthttp.ReleaseResponse(resp)
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, errors.FromContext(err)
+		}
+	}
+}
+
+// send 发送一次请求
+func (c *Client) send(ctx context.Context, cr credentials, method, path string, header http.Header, body []byte) (*fasthttp.Response, error) {
 	// 创建请求和响应对象
 	req := fasthttp.AcquireRequest()
 	resp := fasthttp.AcquireResponse()
-	defer fasthttp.ReleaseRequest(req)
 
 	// 设置 URL
-	req.SetRequestURI(BaseURL + path)
+	req.SetRequestURI(c.baseURL + path)
 	req.Header.SetMethod(method)
 
 	// 设置请求头
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Notion-Version", APIVersion)
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	// 如果有请求体，编码为 JSON
+	req.Header.Set("Notion-Version", cr.apiVersion)
+	req.Header.Set("Authorization", cr.authScheme+" "+cr.apiKey)
+	if c.userAgent != "" {
+		req.Header.SetUserAgent(c.userAgent)
+	}
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("编码请求体失败: %v", err)
+		req.SetBody(body)
+	}
+	for key, values := range header {
+		req.Header.Del(key)
+		for _, v := range values {
+			req.Header.Add(key, v)
 		}
-		req.SetBody(jsonBody)
-		fmt.Printf("请求体: %s\n", string(jsonBody))
 	}
 
-	fmt.Printf("请求 URL: %s\n", req.URI().String())
-	fmt.Printf("请求头: %+v\n", req.Header.String())
-
-	// 发送请求
-	if err := c.httpClient.Do(req, resp); err != nil {
-		return nil, fmt.Errorf("发送请求失败: %v", err)
+	if c.logBodySize > 0 {
+		c.logger.Log(ctx, LogLevelDebug, "notion request",
+			"method", method,
+			"path", path,
+			"headers", cr.redact(req.Header.String()),
+			"body", c.logBody(cr, body),
+		)
 	}
 
-	return resp, nil
-}
-
-// Get 发送 GET 请求
-func (c *Client) Get(path string, params interface{}, v interface{}) error {
-	ctx := context.Background()
-	resp, err := c.Do(ctx, "GET", path, params)
-	if err != nil {
-		return err
-	}
+	// 在独立的 goroutine 中发送请求，以便响应 ctx 的取消
+	done := make(chan error, 1)
+	go func() {
+		done <- c.transport.RoundTrip(ctx, req, resp)
+	}()
 
-	// 检查状态码
-	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
-		return fmt.Errorf("API错误 %d: %s", resp.StatusCode(), string(resp.Body()))
+	select {
+	case err := <-done:
+		fasthttp.ReleaseRequest(req)
+		if err != nil {
+			fasthttp.ReleaseResponse(resp)
+			if ctx.Err() != nil {
+				return nil, errors.FromContext(ctx.Err())
+			}
+			if isTimeout(err) {
+				return nil, errors.Wrap(errors.ErrRequestTimeout, "请求超时", err)
+			}
+			return nil, fmt.Errorf("发送请求失败: %w", err)
+		}
+		return resp, nil
+	case <-ctx.Done():
+		// 请求仍在进行中，等待其结束后再归还对象，避免复用正在使用的缓冲区
+		go func() {
+			<-done
+			fasthttp.ReleaseRequest(req)
+			fasthttp.ReleaseResponse(resp)
+		}()
+		return nil, errors.FromContext(ctx.Err())
 	}
+}
 
-	// 解码响应
-	if err := json.Unmarshal(resp.Body(), v); err != nil {
-		return fmt.Errorf("解码响应失败: %v", err)
+// isTimeout 检查 Transport 返回的错误是否是超时
+func isTimeout(err error) bool {
+	if err == fasthttp.ErrTimeout {
+		return true
 	}
+	var netErr net.Error
+	return stderrors.As(err, &netErr) && netErr.Timeout()
+}
 
-	return nil
+// apiError 将非 2xx 响应解码为 *errors.Error
+func apiError(resp *fasthttp.Response, retryAfter time.Duration) error {
+	return decodeAPIError(resp.StatusCode(), resp.Body(), requestID(resp), retryAfter)
 }
 
-// Post 发送 POST 请求
-func (c *Client) Post(path string, body interface{}, v interface{}) error {
-	ctx := context.Background()
-	resp, err := c.Do(ctx, "POST", path, body)
-	if err != nil {
-		return err
+// decodeAPIError 将非 2xx 响应的状态码和响应体解码为 *errors.Error
+func decodeAPIError(status int, body []byte, requestID string, retryAfter time.Duration) error {
+	apiErr := &errors.Error{}
+	if err := json.Unmarshal(body, apiErr); err != nil || apiErr.Message == "" {
+		apiErr.Message = string(body)
 	}
-
-	// 检查状态码
-	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
-		return fmt.Errorf("API错误 %d: %s", resp.StatusCode(), string(resp.Body()))
+	apiErr.Status = status
+	if apiErr.Code == "" {
+		apiErr.Code = errors.CodeForStatus(apiErr.Status)
 	}
-
-	// 解码响应
-	if err := json.Unmarshal(resp.Body(), v); err != nil {
-		return fmt.Errorf("解码响应失败: %v", err)
+	if retryAfter > 0 {
+		apiErr.RetryAfter = int(retryAfter.Round(time.Second) / time.Second)
 	}
-
-	return nil
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = requestID
+	}
+	return apiErr
 }
 
-// Patch 发送 PATCH 请求
-func (c *Client) Patch(path string, body interface{}, v interface{}) error {
-	ctx := context.Background()
-	resp, err := c.Do(ctx, "PATCH", path, body)
-	if err != nil {
-		return err
+// request 发送请求并将响应解码到 v，v 为 nil 时忽略响应体
+//
+// 设置了缓存时，可以缓存的 GET 请求优先使用缓存的响应。
+func (c *Client) request(ctx context.Context, method, path string, body interface{}, v interface{}) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cr := c.credentials(ctx)
+	scope := cr.cacheScope
+	data, ok := c.cachedResponse(scope, method, path)
+	if !ok {
+		req := &Request{Method: method, Path: path, Body: body}
+		if len(c.middleware) == 0 {
+			// 没有中间件时直接解码 Transport 的响应，避免复制响应体
+			resp, err := c.do(ctx, cr, req)
+			if err != nil {
+				return err
+			}
+			defer fasthttp.ReleaseResponse(resp)
+			data = resp.Body()
+		} else {
+			resp, err := c.call(ctx, req)
+			if err != nil {
+				return err
+			}
+			data = resp.Body
+		}
+		c.updateCache(scope, method, path, data)
 	}
 
-	// 检查状态码
-	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
-		return fmt.Errorf("API错误 %d: %s", resp.StatusCode(), string(resp.Body()))
+	if v == nil {
+		return nil
 	}
 
 	// 解码响应
-	if err := json.Unmarshal(resp.Body(), v); err != nil {
+	if err := json.Unmarshal(data, v); err != nil {
 		return fmt.Errorf("解码响应失败: %v", err)
 	}
 
 	return nil
 }
 
-// Delete 发送 DELETE 请求
-func (c *Client) Delete(path string) error {
-	ctx := context.Background()
-	resp, err := c.Do(ctx, "DELETE", path, nil)
+// Get 发送 GET 请求，params 会按 JSON 字段名编码为查询参数
+func (c *Client) Get(ctx context.Context, path string, params interface{}, v interface{}) error {
+	if params != nil {
+		query, err := encodeQuery(params)
+		if err != nil {
+			return err
+		}
+		if query != "" {
+			if strings.Contains(path, "?") {
+				path += "&" + query
+			} else {
+				path += "?" + query
+			}
+		}
+	}
+	return c.request(ctx, "GET", path, nil, v)
+}
+
+// encodeQuery 将结构体按 JSON 字段名编码为查询字符串，忽略空值和非标量字段
+func encodeQuery(params interface{}) (string, error) {
+	data, err := json.Marshal(params)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("编码查询参数失败: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// 不是 JSON 对象，例如 nil 指针
+		return "", nil
 	}
 
-	// 检查状态码
-	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
-		return fmt.Errorf("API错误 %d: %s", resp.StatusCode(), string(resp.Body()))
+	values := url.Values{}
+	for key, value := range fields {
+		switch value := value.(type) {
+		case string:
+			values.Set(key, value)
+		case float64:
+			values.Set(key, strconv.FormatFloat(value, 'f', -1, 64))
+		case bool:
+			values.Set(key, strconv.FormatBool(value))
+		}
 	}
+	return values.Encode(), nil
+}
 
-	return nil
+// Post 发送 POST 请求
+func (c *Client) Post(ctx context.Context, path string, body interface{}, v interface{}) error {
+	return c.request(ctx, "POST", path, body, v)
+}
+
+// Patch 发送 PATCH 请求
+func (c *Client) Patch(ctx context.Context, path string, body interface{}, v interface{}) error {
+	return c.request(ctx, "PATCH", path, body, v)
+}
+
+// Delete 发送 DELETE 请求
+func (c *Client) Delete(ctx context.Context, path string) error {
+	return c.request(ctx, "DELETE", path, nil, nil)
 }
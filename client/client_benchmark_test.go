@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"net"
 	"testing"
 
 	"github.com/valyala/fasthttp"
@@ -14,7 +15,7 @@ func BenchmarkClient(b *testing.B) {
 	defer ln.Close()
 
 	go func() {
-		err := fasthttp.ListenAndServe(ln.Addr().String(), func(ctx *fasthttp.RequestCtx) {
+		err := fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) {
 			ctx.SetContentType("application/json")
 			ctx.Write([]byte(`{
 				"object": "list",
@@ -39,12 +40,18 @@ func BenchmarkClient(b *testing.B) {
 			}`))
 		})
 		if err != nil {
-			b.Fatal(err)
+			b.Error(err)
 		}
 	}()
 
 	// 创建客户端
-	client := NewClient("test-token")
+	client := NewClient("test-token",
+		WithBaseURL("http://localhost"),
+		WithRateLimit(0, 0), // 基准测试不限制速率
+		WithDialer(func(addr string) (net.Conn, error) {
+			return ln.Dial()
+		}),
+	)
 
 	// 运行基准测试
 	b.ResetTimer()
@@ -73,17 +80,23 @@ func BenchmarkClientWithLargePayload(b *testing.B) {
 	defer ln.Close()
 
 	go func() {
-		err := fasthttp.ListenAndServe(ln.Addr().String(), func(ctx *fasthttp.RequestCtx) {
+		err := fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) {
 			ctx.SetContentType("application/json")
 			ctx.Write(largeResponse)
 		})
 		if err != nil {
-			b.Fatal(err)
+			b.Error(err)
 		}
 	}()
 
 	// 创建客户端
-	client := NewClient("test-token")
+	client := NewClient("test-token",
+		WithBaseURL("http://localhost"),
+		WithRateLimit(0, 0), // 基准测试不限制速率
+		WithDialer(func(addr string) (net.Conn, error) {
+			return ln.Dial()
+		}),
+	)
 
 	// 运行基准测试
 	b.ResetTimer()
@@ -106,17 +119,23 @@ func BenchmarkClientWithConcurrency(b *testing.B) {
 	defer ln.Close()
 
 	go func() {
-		err := fasthttp.ListenAndServe(ln.Addr().String(), func(ctx *fasthttp.RequestCtx) {
+		err := fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) {
 			ctx.SetContentType("application/json")
 			ctx.Write([]byte(`{"result": "ok"}`))
 		})
 		if err != nil {
-			b.Fatal(err)
+			b.Error(err)
 		}
 	}()
 
 	// 创建客户端
-	client := NewClient("test-token")
+	client := NewClient("test-token",
+		WithBaseURL("http://localhost"),
+		WithRateLimit(0, 0), // 基准测试不限制速率
+		WithDialer(func(addr string) (net.Conn, error) {
+			return ln.Dial()
+		}),
+	)
 
 	// 运行基准测试
 	b.ResetTimer()
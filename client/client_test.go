@@ -1,11 +1,18 @@
 package client
 
 import (
+	"bufio"
 	"context"
+	"encoding/base64"
+	stderrors "errors"
+	"fmt"
 	"net"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/kuekiko/NotionGO/errors"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/fasthttputil"
 )
@@ -51,13 +58,13 @@ func TestClientRetry(t *testing.T) {
 	})
 	defer ln.Close()
 
-	client := NewClient("test-token")
-	client.httpClient = &fasthttp.HostClient{
+	client := NewClient("test-token", WithBaseURL("http://localhost"))
+	client.transport = &fastHTTPTransport{client: &fasthttp.HostClient{
 		Addr: "localhost",
 		Dial: func(addr string) (net.Conn, error) {
 			return ln.Dial()
 		},
-	}
+	}}
 
 	client.retryWaitMin = 1
 	client.retryWaitMax = 5
@@ -82,22 +89,31 @@ func TestClientError(t *testing.T) {
 	})
 	defer ln.Close()
 
-	client := NewClient("test-token")
-	client.httpClient = &fasthttp.HostClient{
+	client := NewClient("test-token", WithBaseURL("http://localhost"))
+	client.transport = &fastHTTPTransport{client: &fasthttp.HostClient{
 		Addr: "localhost",
 		Dial: func(addr string) (net.Conn, error) {
 			return ln.Dial()
 		},
-	}
+	}}
 
 	_, err := client.Do(context.Background(), "GET", "test", nil)
 	if err == nil {
 		t.Fatal("Expected error, got nil")
 	}
 
-	expectedError := "API错误 400: {\"message\": \"Invalid request\"}"
-	if err.Error() != expectedError {
-		t.Errorf("Expected error message %q, got %q", expectedError, err.Error())
+	var apiErr *errors.Error
+	if !stderrors.As(err, &apiErr) {
+		t.Fatalf("Expected *errors.Error, got %T", err)
+	}
+	if apiErr.Status != fasthttp.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", fasthttp.StatusBadRequest, apiErr.Status)
+	}
+	if apiErr.Message != "Invalid request" {
+		t.Errorf("Expected message %q, got %q", "Invalid request", apiErr.Message)
+	}
+	if apiErr.Code != errors.ErrInvalidRequest {
+		t.Errorf("Expected code %s, got %s", errors.ErrInvalidRequest, apiErr.Code)
 	}
 }
 
@@ -110,13 +126,13 @@ func TestClientContext(t *testing.T) {
 	})
 	defer ln.Close()
 
-	client := NewClient("test-token")
-	client.httpClient = &fasthttp.HostClient{
+	client := NewClient("test-token", WithBaseURL("http://localhost"))
+	client.transport = &fastHTTPTransport{client: &fasthttp.HostClient{
 		Addr: "localhost",
 		Dial: func(addr string) (net.Conn, error) {
 			return ln.Dial()
 		},
-	}
+	}}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
 	defer cancel()
@@ -125,4 +141,262 @@ func TestClientContext(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected context deadline exceeded error, got nil")
 	}
+	if !errors.IsTimeout(err) {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+}
+
+func TestClientContextCanceled(t *testing.T) {
+	client := NewClient("test-token")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.Do(ctx, "GET", "test", nil)
+	if !errors.IsCanceled(err) {
+		t.Fatalf("Expected context canceled error, got %v", err)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	resp.SetStatusCode(fasthttp.StatusNotFound)
+	resp.SetBodyString(`{"object":"error","status":404,"code":"object_not_found","message":"Could not find page.","request_id":"req-123"}`)
+
+	err := apiError(resp, 0)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("Expected not found error, got %v", err)
+	}
+
+	var apiErr *errors.Error
+	if !stderrors.As(fmt.Errorf("wrapped: %w", err), &apiErr) {
+		t.Fatal("Expected errors.As to find *errors.Error")
+	}
+	if apiErr.Code != errors.ErrObjectNotFound {
+		t.Errorf("Expected code %s, got %s", errors.ErrObjectNotFound, apiErr.Code)
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("Expected request id %q, got %q", "req-123", apiErr.RequestID)
+	}
+}
+
+func TestAPIErrorRateLimited(t *testing.T) {
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	resp.SetStatusCode(fasthttp.StatusTooManyRequests)
+	resp.SetBodyString(`<html>Too Many Requests</html>`)
+
+	err := apiError(resp, 3*time.Second)
+	if !errors.IsRateLimited(err) {
+		t.Fatalf("Expected rate limited error, got %v", err)
+	}
+
+	apiErr := err.(*errors.Error)
+	if apiErr.RetryAfter != 3 {
+		t.Errorf("Expected RetryAfter 3, got %d", apiErr.RetryAfter)
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	var userAgent, version string
+	ln := setupTestServer(t, func(ctx *fasthttp.RequestCtx) {
+		userAgent = string(ctx.Request.Header.UserAgent())
+		version = string(ctx.Request.Header.Peek("Notion-Version"))
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	})
+	defer ln.Close()
+
+	client := NewClient("test-token",
+		WithBaseURL("http://notion.test/v1"),
+		WithAPIVersion("2099-01-01"),
+		WithUserAgent("test-agent"),
+		WithMaxRetries(5),
+		WithDialer(func(addr string) (net.Conn, error) {
+			return ln.Dial()
+		}),
+	)
+
+	if client.baseURL != "http://notion.test/v1/" {
+		t.Errorf("Expected base URL with trailing slash, got %s", client.baseURL)
+	}
+	if client.retryCount != 5 {
+		t.Errorf("Expected retry count 5, got %d", client.retryCount)
+	}
+
+	resp, err := client.Do(context.Background(), "GET", "users/me", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	fasthttp.ReleaseResponse(resp)
+
+	if userAgent != "test-agent" {
+		t.Errorf("Expected User-Agent %q, got %q", "test-agent", userAgent)
+	}
+	if version != "2099-01-01" {
+		t.Errorf("Expected Notion-Version %q, got %q", "2099-01-01", version)
+	}
+}
+
+func TestWithProxyCredentials(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	auth := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			auth <- ""
+			return
+		}
+		auth <- req.Header.Get("Proxy-Authorization")
+		conn.Write([]byte("HTTP/1.1 403 Forbidden\r\n\r\n"))
+	}()
+
+	// reserved characters in the user info are percent-encoded in the URL
+	client := NewClient("test-token",
+		WithBaseURL("http://api.notion.test/v1"),
+		WithProxy("http://user:p%40ss%3Aw%2Frd@"+ln.Addr().String()),
+		WithMaxRetries(0),
+	)
+	client.Get(context.Background(), "users/me", nil, nil)
+
+	want := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:p@ss:w/rd"))
+	select {
+	case got := <-auth:
+		if got != want {
+			t.Errorf("Expected Proxy-Authorization %q, got %q", want, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Proxy received no request")
+	}
+}
+
+func TestClientTimeoutOption(t *testing.T) {
+	ln := setupTestServer(t, func(ctx *fasthttp.RequestCtx) {
+		time.Sleep(100 * time.Millisecond)
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	})
+	defer ln.Close()
+
+	client := NewClient("test-token",
+		WithBaseURL("http://localhost"),
+		WithTimeout(20*time.Millisecond),
+		WithDialer(func(addr string) (net.Conn, error) {
+			return ln.Dial()
+		}),
+	)
+
+	_, err := client.Do(context.Background(), "GET", "test", nil)
+	if !errors.IsTimeout(err) {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+}
+
+func TestClientLogger(t *testing.T) {
+	ln := setupTestServer(t, func(ctx *fasthttp.RequestCtx) {
+		ctx.Response.Header.Set("X-Notion-Request-Id", "req-456")
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.Write([]byte(`{"object":"user","name":"0123456789abcdef"}`))
+	})
+	defer ln.Close()
+
+	var logs []string
+	fields := map[string]interface{}{}
+	logger := LoggerFunc(func(ctx context.Context, level LogLevel, msg string, args ...interface{}) {
+		line := level.String() + " " + msg + fmt.Sprint(args...)
+		logs = append(logs, line)
+		for i := 0; i+1 < len(args); i += 2 {
+			fields[msg+"."+args[i].(string)] = args[i+1]
+		}
+	})
+
+	client := NewClient("secret-token",
+		WithBaseURL("http://localhost"),
+		WithLogger(logger),
+		WithLogBody(8),
+		WithDialer(func(addr string) (net.Conn, error) {
+			return ln.Dial()
+		}),
+	)
+
+	resp, err := client.Do(context.Background(), "POST", "search", map[string]string{"query": "secret-token"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	fasthttp.ReleaseResponse(resp)
+
+	for _, line := range logs {
+		if strings.Contains(line, "secret-token") {
+			t.Errorf("Token leaked into log line: %s", line)
+		}
+	}
+	if fields["notion request completed.status"] != fasthttp.StatusOK {
+		t.Errorf("Expected status field, got %v", fields["notion request completed.status"])
+	}
+	if fields["notion request completed.request_id"] != "req-456" {
+		t.Errorf("Expected request_id field, got %v", fields["notion request completed.request_id"])
+	}
+	if body, _ := fields["notion response.body"].(string); !strings.Contains(body, "truncated") {
+		t.Errorf("Expected truncated response body, got %q", body)
+	}
+}
+
+func TestLogBodyRedactsBeforeTruncating(t *testing.T) {
+	client := NewClient("secret-token", WithLogBody(16))
+	cr := client.credentials(context.Background())
+
+	// the cut at 16 bytes falls inside the token
+	body := client.logBody(cr, []byte(`{"query":"secret-token"}`))
+	if strings.Contains(body, "secret") {
+		t.Errorf("Partial token leaked into truncated body: %q", body)
+	}
+	if !strings.HasPrefix(body, `{"query":"[REDAC`) || !strings.Contains(body, "truncated") {
+		t.Errorf("Expected redacted and truncated body, got %q", body)
+	}
+}
+
+func TestClientBasicAuth(t *testing.T) {
+	var auth string
+	ln := setupTestServer(t, func(ctx *fasthttp.RequestCtx) {
+		auth = string(ctx.Request.Header.Peek("Authorization"))
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	})
+	defer ln.Close()
+
+	var logs []string
+	client := NewClient("",
+		WithBaseURL("http://localhost"),
+		WithBasicAuth("client-id", "client-secret"),
+		WithLogger(LoggerFunc(func(ctx context.Context, level LogLevel, msg string, args ...interface{}) {
+			logs = append(logs, fmt.Sprint(args...))
+		})),
+		WithLogBody(1024),
+		WithDialer(func(addr string) (net.Conn, error) {
+			return ln.Dial()
+		}),
+	)
+
+	resp, err := client.Do(context.Background(), "POST", "oauth/token", map[string]string{"code": "c"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	fasthttp.ReleaseResponse(resp)
+
+	if want := "Basic Y2xpZW50LWlkOmNsaWVudC1zZWNyZXQ="; auth != want {
+		t.Errorf("Expected Authorization %q, got %q", want, auth)
+	}
+	for _, line := range logs {
+		if strings.Contains(line, "Y2xpZW50LWlkOmNsaWVudC1zZWNyZXQ=") {
+			t.Errorf("Credentials leaked into log line: %s", line)
+		}
+	}
 }
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// tokenKey 和 apiVersionKey 是保存调用级设置的 context 键
+type (
+	tokenKey      struct{}
+	apiVersionKey struct{}
+)
+
+// ContextWithToken 返回使用 token 发送请求的 ctx，覆盖 NewClient 传入的令牌
+//
+// 一个 Client 可以通过不同的 ctx 访问多个工作区：每个令牌使用独立的速率限制器和缓存键，
+// 连接池和其他配置仍然共用。使用 WithBasicAuth 的 Client 请求 oauth 接口，忽略 ctx 中的令牌。
+func ContextWithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// ContextWithAPIVersion 返回使用 version 作为 Notion-Version 请求头的 ctx，覆盖 WithAPIVersion 的设置
+func ContextWithAPIVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, apiVersionKey{}, version)
+}
+
+// credentials 表示一次调用使用的令牌以及按令牌区分的速率限制器和缓存键
+type credentials struct {
+	apiKey     string
+	authScheme string
+	apiVersion string
+	limiter    RateLimiter
+	cacheScope string
+}
+
+// limiterIdleTimeout 是速率限制器的最长空闲时间，超过后从 limiterSet 中清除
+//
+// 空闲这么久的令牌桶已经装满，Retry-After 的暂停也已结束，清除后重新创建的限制器行为相同。
+const limiterIdleTimeout = 10 * time.Minute
+
+// limiterSet 按令牌保存速率限制器，长时间没有使用的限制器被清除，集合不会随令牌的数量无限增长
+type limiterSet struct {
+	mu        sync.Mutex
+	m         map[string]*limiterEntry
+	lastSweep time.Time
+}
+
+// limiterEntry 是 limiterSet 中的一个速率限制器
+type limiterEntry struct {
+	limiter  RateLimiter
+	lastUsed time.Time
+}
+
+// get 返回 key 的速率限制器，不存在时使用 newLimiter 创建
+func (s *limiterSet) get(key string, newLimiter func() RateLimiter) RateLimiter {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastSweep) >= limiterIdleTimeout {
+		for k, e := range s.m {
+			if now.Sub(e.lastUsed) >= limiterIdleTimeout {
+				delete(s.m, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	e, ok := s.m[key]
+	if !ok {
+		if s.m == nil {
+			s.m = make(map[string]*limiterEntry)
+		}
+		e = &limiterEntry{limiter: newLimiter()}
+		s.m[key] = e
+	}
+	e.lastUsed = now
+	return e.limiter
+}
+
+// delete 删除 key 的速率限制器
+func (s *limiterSet) delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, key)
+}
+
+// credentials 返回 ctx 中的令牌和版本，没有覆盖时使用客户端的设置
+func (c *Client) credentials(ctx context.Context) credentials {
+	cr := credentials{
+		apiKey:     c.apiKey,
+		authScheme: c.authScheme,
+		apiVersion: c.apiVersion,
+		limiter:    c.limiter,
+		cacheScope: c.cacheScope,
+	}
+	if c.sharedRateLimit && c.newLimiter != nil {
+		// 每次从共享的集合中取，空闲后被清除并重新创建的限制器在所有 Client 之间仍然是同一个
+		cr.limiter = sharedLimiter(c.apiKey, c.newLimiter)
+	}
+	// Basic 认证的客户端只用于 oauth 接口，ContextFor 返回的 ctx 传给 Revoke 等方法时仍然使用客户端凭据
+	if token, _ := ctx.Value(tokenKey{}).(string); token != "" && token != c.apiKey && c.authScheme != "Basic" {
+		cr.apiKey, cr.authScheme = token, "Bearer"
+		cr.limiter = c.limiterFor(token)
+		cr.cacheScope = cacheScope(token)
+	}
+	if version, _ := ctx.Value(apiVersionKey{}).(string); version != "" && version != c.apiVersion {
+		// 不同版本的响应格式可能不同，不能共用缓存
+		cr.apiVersion = version
+		cr.cacheScope = cacheScope(cr.apiKey + " " + version)
+	}
+	return cr
+}
+
+// limiterFor 返回 token 使用的速率限制器
+//
+// 使用 WithRateLimiter 传入的限制器由所有令牌共用；否则每个令牌有自己的令牌桶，
+// 设置了 WithSharedRateLimit 时与其他使用该令牌的 Client 共用。
+func (c *Client) limiterFor(token string) RateLimiter {
+	if c.newLimiter == nil {
+		return c.limiter
+	}
+	if c.sharedRateLimit {
+		return sharedLimiter(token, c.newLimiter)
+	}
+	// 和缓存一样使用令牌的摘要作为键，避免明文令牌长期留在内存中
+	return c.limiters.get(cacheScope(token), c.newLimiter)
+}
+
+// ForgetToken 丢弃 token 的速率限制器，用于令牌被撤销之后
+//
+// 包括这个 Client 为 ContextWithToken 传入的令牌创建的限制器和 WithSharedRateLimit 共享的限制器。
+// 之后再使用该令牌时重新创建。长时间没有使用的限制器也会被自动清除。
+func (c *Client) ForgetToken(token string) {
+	c.limiters.delete(cacheScope(token))
+	ForgetToken(token)
+}
+
+// ForgetToken 丢弃 WithSharedRateLimit 为 token 在进程内共享的速率限制器
+func ForgetToken(token string) {
+	sharedLimiters.delete(cacheScope(token))
+}
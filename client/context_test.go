@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kuekiko/NotionGO/cache"
+)
+
+// headerServer records the Authorization and Notion-Version headers of each request
+type headerServer struct {
+	mu       sync.Mutex
+	auth     []string
+	versions []string
+}
+
+func (s *headerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+	s.versions = append(s.versions, r.Header.Get("Notion-Version"))
+	fmt.Fprintf(w, `{"object":"page","id":"p1","token":%q}`, r.Header.Get("Authorization"))
+}
+
+func TestContextWithToken(t *testing.T) {
+	srv := &headerServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	client := NewClient("default-token", WithBaseURL(ts.URL), WithAPIVersion("2022-06-28"))
+	ctx := context.Background()
+	if err := client.Get(ctx, "users/me", nil, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if err := client.Get(ContextWithToken(ctx, "workspace-token"), "users/me", nil, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if err := client.Get(ContextWithAPIVersion(ContextWithToken(ctx, "workspace-token"), "2099-01-01"), "users/me", nil, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	wantAuth := []string{"Bearer default-token", "Bearer workspace-token", "Bearer workspace-token"}
+	wantVersions := []string{"2022-06-28", "2022-06-28", "2099-01-01"}
+	for i := range wantAuth {
+		if srv.auth[i] != wantAuth[i] || srv.versions[i] != wantVersions[i] {
+			t.Errorf("Request %d: expected %q with version %q, got %q with version %q",
+				i, wantAuth[i], wantVersions[i], srv.auth[i], srv.versions[i])
+		}
+	}
+}
+
+func TestContextWithTokenRateLimit(t *testing.T) {
+	client := NewClient("default-token")
+	ctx := context.Background()
+
+	a := client.credentials(ContextWithToken(ctx, "token-a"))
+	b := client.credentials(ContextWithToken(ctx, "token-b"))
+	again := client.credentials(ContextWithToken(ctx, "token-a"))
+	base := client.credentials(ContextWithToken(ctx, "default-token"))
+
+	if a.limiter == b.limiter || a.limiter == client.limiter {
+		t.Error("Expected each token to have its own limiter")
+	}
+	if a.limiter != again.limiter {
+		t.Error("Expected calls with the same token to share a limiter")
+	}
+	if base.limiter != client.limiter {
+		t.Error("Expected the client's own token to use the client's limiter")
+	}
+
+	shared := NewClient("default-token", WithSharedRateLimit())
+	other := NewClient("token-a", WithSharedRateLimit())
+	if shared.credentials(ContextWithToken(ctx, "token-a")).limiter != other.limiter {
+		t.Error("Expected WithSharedRateLimit to share the limiter with clients using the token")
+	}
+
+	custom := &recordingLimiter{}
+	c := NewClient("default-token", WithRateLimiter(custom))
+	if c.credentials(ContextWithToken(ctx, "token-a")).limiter != custom {
+		t.Error("Expected a custom limiter to be used for every token")
+	}
+}
+
+func TestLimiterSetIdleExpiry(t *testing.T) {
+	var set limiterSet
+	newLimiter := func() RateLimiter { return NewTokenBucket(3, 10) }
+	old := set.get("old-token", newLimiter)
+	active := set.get("active-token", newLimiter)
+
+	// pretend old-token has been idle and a sweep is due
+	past := time.Now().Add(-2 * limiterIdleTimeout)
+	set.m["old-token"].lastUsed = past
+	set.lastSweep = past
+	set.get("new-token", newLimiter)
+
+	if _, ok := set.m["old-token"]; ok {
+		t.Error("Expected the idle limiter to be removed")
+	}
+	if set.get("active-token", newLimiter) != active {
+		t.Error("Expected the recently used limiter to be kept")
+	}
+	if set.get("old-token", newLimiter) == old {
+		t.Error("Expected a new limiter after expiry")
+	}
+	if len(set.m) != 3 {
+		t.Errorf("Expected 3 limiters, got %d", len(set.m))
+	}
+}
+
+func TestForgetToken(t *testing.T) {
+	ctx := ContextWithToken(context.Background(), "revoked-token")
+
+	client := NewClient("default-token")
+	before := client.credentials(ctx).limiter
+	if _, ok := client.limiters.m["revoked-token"]; ok {
+		t.Error("Expected limiters to be keyed by the token digest, not the token")
+	}
+	if _, ok := client.limiters.m[cacheScope("revoked-token")]; !ok {
+		t.Error("Expected a limiter keyed by the token digest")
+	}
+	client.ForgetToken("revoked-token")
+	if _, ok := client.limiters.m[cacheScope("revoked-token")]; ok {
+		t.Error("Expected the token's limiter to be removed")
+	}
+	if client.credentials(ctx).limiter == before {
+		t.Error("Expected a new limiter after ForgetToken")
+	}
+
+	shared := NewClient("default-token", WithSharedRateLimit())
+	shared.credentials(ctx)
+	ForgetToken("revoked-token")
+	sharedLimiters.mu.Lock()
+	_, ok := sharedLimiters.m[cacheScope("revoked-token")]
+	sharedLimiters.mu.Unlock()
+	if ok {
+		t.Error("Expected the shared limiter to be removed")
+	}
+}
+
+func TestContextWithTokenCache(t *testing.T) {
+	srv := &headerServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	client := NewClient("default-token", WithBaseURL(ts.URL), WithCache(cache.NewMemoryCache()))
+	ctx := context.Background()
+	get := func(ctx context.Context) string {
+		var page struct{ Token string }
+		if err := client.Get(ctx, "pages/p1", nil, &page); err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		return page.Token
+	}
+
+	if got := get(ctx); got != "Bearer default-token" {
+		t.Errorf("Expected response for the default token, got %q", got)
+	}
+	if got := get(ContextWithToken(ctx, "workspace-token")); got != "Bearer workspace-token" {
+		t.Errorf("Expected the cache to be separate per token, got %q", got)
+	}
+	get(ContextWithToken(ctx, "workspace-token"))
+	if len(srv.auth) != 2 {
+		t.Errorf("Expected 2 requests, got %d", len(srv.auth))
+	}
+}
+
+func TestContextWithTokenRedacted(t *testing.T) {
+	srv := &headerServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	var logs []string
+	client := NewClient("default-token",
+		WithBaseURL(ts.URL),
+		WithLogBody(1024),
+		WithLogger(LoggerFunc(func(ctx context.Context, level LogLevel, msg string, args ...interface{}) {
+			logs = append(logs, fmt.Sprint(args...))
+		})),
+	)
+	if err := client.Get(ContextWithToken(context.Background(), "workspace-token"), "pages/p1", nil, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	for _, line := range logs {
+		if strings.Contains(line, "workspace-token") {
+			t.Errorf("Token leaked into log line: %s", line)
+		}
+	}
+}
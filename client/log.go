@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// LogLevel 表示日志级别，取值与 log/slog 的级别一致
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = -4
+	LogLevelInfo  LogLevel = 0
+	LogLevelWarn  LogLevel = 4
+	LogLevelError LogLevel = 8
+)
+
+// String 返回日志级别的名称
+func (l LogLevel) String() string {
+	switch {
+	case l < LogLevelInfo:
+		return "DEBUG"
+	case l < LogLevelWarn:
+		return "INFO"
+	case l < LogLevelError:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+// Logger 表示日志记录器
+//
+// args 是交替出现的键值对，与 slog.Logger.Log 的约定相同。
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, args ...interface{})
+}
+
+// LoggerFunc 将普通函数适配为 Logger
+type LoggerFunc func(ctx context.Context, level LogLevel, msg string, args ...interface{})
+
+// Log 实现 Logger
+func (f LoggerFunc) Log(ctx context.Context, level LogLevel, msg string, args ...interface{}) {
+	f(ctx, level, msg, args...)
+}
+
+// nopLogger 丢弃所有日志
+type nopLogger struct{}
+
+func (nopLogger) Log(context.Context, LogLevel, string, ...interface{}) {}
+
+// redactedToken 是日志中替代令牌的占位符
+const redactedToken = "[REDACTED]"
+
+// redact 去除字符串中出现的令牌
+func (cr credentials) redact(s string) string {
+	if cr.apiKey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, cr.apiKey, redactedToken)
+}
+
+// logBody 返回用于日志的请求体或响应体，超过 logBodySize 的部分被截断
+//
+// 先去除令牌再截断，避免跨过截断位置的令牌只被截掉一半而残留在日志中。
+func (c *Client) logBody(cr credentials, body []byte) string {
+	s := cr.redact(string(body))
+	if len(s) > c.logBodySize {
+		return s[:c.logBodySize] + fmt.Sprintf("...(%d bytes truncated)", len(s)-c.logBodySize)
+	}
+	return s
+}
+
+// requestID 返回 Notion 在响应头中给出的请求 ID
+func requestID(resp *fasthttp.Response) string {
+	if id := resp.Header.Peek("X-Notion-Request-Id"); len(id) > 0 {
+		return string(id)
+	}
+	return string(resp.Header.Peek("X-Request-Id"))
+}
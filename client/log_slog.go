@@ -0,0 +1,15 @@
+//go:build go1.21
+
+package client
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogLogger 将 *slog.Logger 适配为 Logger
+func NewSlogLogger(l *slog.Logger) Logger {
+	return LoggerFunc(func(ctx context.Context, level LogLevel, msg string, args ...interface{}) {
+		l.Log(ctx, slog.Level(level), msg, args...)
+	})
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kuekiko/NotionGO/errors"
+	"github.com/valyala/fasthttp"
+)
+
+// Request 表示经过中间件的一次调用，中间件可以修改其中的任何字段
+type Request struct {
+	Method string      // 请求方法，例如 "GET"
+	Path   string      // 相对于 BaseURL 的路径，可以带查询字符串，例如 "blocks/abc/children?page_size=10"
+	Body   interface{} // 编码为 JSON 之前的请求体，为 nil 时不发送请求体
+	Header http.Header // 额外的请求头，在默认请求头之后设置，同名时覆盖默认值
+}
+
+// Response 表示调用的响应
+type Response struct {
+	StatusCode int // 状态码，中间件返回的响应为 0 时视为 200
+	Header     http.Header
+	Body       []byte
+}
+
+// Handler 处理一次调用，返回 2xx 响应或错误
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware 包装 Handler，可以在调用 next 之前修改请求、不调用 next 直接返回响应，
+// 或者在 next 返回后检查响应和错误
+//
+// 中间件包在整个调用外面：重试、速率限制和并发限制都在 next 内部完成，每次调用只经过中间件一次。
+// 非 2xx 响应以 *errors.Error 的形式返回，Status 为状态码。
+// 缓存命中的 GET 请求不经过中间件。
+type Middleware func(next Handler) Handler
+
+// chain 把中间件按顺序包在 h 外面，第一个中间件最先看到请求、最后看到响应
+func chain(middleware []Middleware, h Handler) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
+
+// roundTrip 是中间件链末端的 Handler，使用 ctx 中的令牌执行请求
+func (c *Client) roundTrip(ctx context.Context, req *Request) (*Response, error) {
+	resp, err := c.do(ctx, c.credentials(ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	defer fasthttp.ReleaseResponse(resp)
+
+	r := &Response{
+		StatusCode: resp.StatusCode(),
+		Header:     make(http.Header),
+		Body:       append([]byte(nil), resp.Body()...),
+	}
+	resp.Header.VisitAll(func(key, value []byte) {
+		r.Header.Add(string(key), string(value))
+	})
+	return r, nil
+}
+
+// call 通过中间件链执行请求，中间件返回的非 2xx 响应同样转换为 *errors.Error
+func (c *Client) call(ctx context.Context, req *Request) (*Response, error) {
+	resp, err := c.handler(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("中间件没有返回响应: %s %s", req.Method, req.Path)
+	}
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusOK
+	}
+	if resp.StatusCode >= 300 {
+		return nil, decodeAPIError(resp.StatusCode, resp.Body, resp.Header.Get("X-Notion-Request-Id"), 0)
+	}
+	return resp, nil
+}
+
+// fastHTTPResponse 把中间件链返回的响应转换为 Do 返回的 *fasthttp.Response
+func (r *Response) fastHTTPResponse() *fasthttp.Response {
+	resp := fasthttp.AcquireResponse()
+	resp.SetStatusCode(r.StatusCode)
+	for key, values := range r.Header {
+		for _, v := range values {
+			resp.Header.Add(key, v)
+		}
+	}
+	resp.SetBody(r.Body)
+	return resp
+}
+
+// SetHeaders 返回给每个请求添加请求头的中间件，例如 X-Tenant 或审计用的标识
+func SetHeaders(headers map[string]string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if req.Header == nil {
+				req.Header = make(http.Header)
+			}
+			for key, value := range headers {
+				req.Header.Set(key, value)
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// ReadOnly 返回拒绝所有修改请求的中间件，用于只读的任务
+//
+// GET 请求、数据库查询和搜索可以通过，其他请求不发送，直接返回 errors.ErrReadOnly。
+func ReadOnly() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if !isReadRequest(req.Method, req.Path) {
+				msg := fmt.Sprintf("只读模式下不允许 %s %s", req.Method, req.Path)
+				return nil, errors.NewError(errors.ErrReadOnly, msg, 0)
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// isReadRequest 判断请求是否只读取数据
+func isReadRequest(method, path string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	case "POST":
+		seg := splitPath(path)
+		// search 和 databases/{id}/query 使用 POST 传递查询条件
+		return (len(seg) == 1 && seg[0] == "search") ||
+			(len(seg) == 3 && seg[0] == "databases" && seg[2] == "query")
+	}
+	return false
+}
+
+// requestIDKey 是保存调用方请求 ID 的 context 键
+type requestIDKey struct{}
+
+// ContextWithRequestID 返回带有请求 ID 的 ctx，由 PropagateRequestID 中间件发送给服务端
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext 返回 ContextWithRequestID 设置的请求 ID
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// PropagateRequestID 返回传递请求 ID 的中间件
+//
+// ctx 中有 ContextWithRequestID 设置的请求 ID 时，以 header 请求头（为空时使用 X-Request-Id）发送，
+// 便于在代理和服务端日志中关联上游的请求。onResponse 不为 nil 时，以调用方的请求 ID 和 Notion 返回的
+// 请求 ID 调用，失败的调用使用 *errors.Error 中的 RequestID。
+func PropagateRequestID(header string, onResponse func(ctx context.Context, requestID, notionRequestID string)) Middleware {
+	if header == "" {
+		header = "X-Request-Id"
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			id := RequestIDFromContext(ctx)
+			if id != "" {
+				if req.Header == nil {
+					req.Header = make(http.Header)
+				}
+				req.Header.Set(header, id)
+			}
+			resp, err := next(ctx, req)
+			if onResponse != nil {
+				notionID := ""
+				if resp != nil {
+					notionID = resp.Header.Get("X-Notion-Request-Id")
+				} else if apiErr := (*errors.Error)(nil); stderrors.As(err, &apiErr) {
+					notionID = apiErr.RequestID
+				}
+				onResponse(ctx, id, notionID)
+			}
+			return resp, err
+		}
+	}
+}
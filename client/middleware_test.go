@@ -0,0 +1,268 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kuekiko/NotionGO/errors"
+	"github.com/valyala/fasthttp"
+)
+
+// recordServer records every request it receives and answers with handler
+type recordServer struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+	handler  func(w http.ResponseWriter, r *http.Request, n int)
+}
+
+func (s *recordServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	s.bodies = append(s.bodies, string(body))
+	n := len(s.requests)
+	s.mu.Unlock()
+	w.Header().Set("X-Notion-Request-Id", "notion-req")
+	if s.handler != nil {
+		s.handler(w, r, n)
+		return
+	}
+	w.Write([]byte(`{"object":"page","id":"p1"}`))
+}
+
+func newMiddlewareTestClient(t *testing.T, srv *recordServer, middleware ...Middleware) *Client {
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return NewClient("test-token",
+		WithBaseURL(ts.URL),
+		WithRateLimit(0, 0),
+		WithRetryWaitTime(time.Millisecond, 10*time.Millisecond),
+		WithMiddleware(middleware...),
+	)
+}
+
+// tracer appends name before and after calling next
+func tracer(name string, trace *[]string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			*trace = append(*trace, name+" in")
+			resp, err := next(ctx, req)
+			*trace = append(*trace, name+" out")
+			return resp, err
+		}
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	srv := &recordServer{handler: func(w http.ResponseWriter, r *http.Request, n int) {
+		if n == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}}
+	var trace []string
+	client := newMiddlewareTestClient(t, srv, tracer("a", &trace), tracer("b", &trace))
+
+	if err := client.Get(context.Background(), "users/me", nil, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	want := "a in,b in,b out,a out"
+	if got := strings.Join(trace, ","); got != want {
+		t.Errorf("Expected middleware order %q, got %q", want, got)
+	}
+	if len(srv.requests) != 2 {
+		t.Errorf("Expected the retry to happen inside the chain, got %d requests", len(srv.requests))
+	}
+}
+
+func TestMiddlewareModifyRequest(t *testing.T) {
+	srv := &recordServer{}
+	rewrite := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			req.Path = "pages/p2"
+			req.Body = map[string]bool{"archived": true}
+			req.Header = http.Header{"X-Audit": {"job-7"}, "Notion-Version": {"2099-01-01"}}
+			return next(ctx, req)
+		}
+	}
+	client := newMiddlewareTestClient(t, srv, rewrite)
+
+	if err := client.Patch(context.Background(), "pages/p1", map[string]bool{"archived": false}, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	r := srv.requests[0]
+	if r.URL.Path != "/pages/p2" || srv.bodies[0] != `{"archived":true}` {
+		t.Errorf("Expected rewritten request, got %s %s", r.URL.Path, srv.bodies[0])
+	}
+	if r.Header.Get("X-Audit") != "job-7" || r.Header.Get("Notion-Version") != "2099-01-01" {
+		t.Errorf("Expected extra headers to be sent, got %v", r.Header)
+	}
+	if r.Header.Get("Authorization") != "Bearer test-token" {
+		t.Errorf("Expected default headers to be kept, got %v", r.Header)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	srv := &recordServer{}
+	stub := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if req.Path == "pages/missing" {
+				return &Response{StatusCode: http.StatusNotFound, Body: []byte(`{"code":"object_not_found","message":"stubbed"}`)}, nil
+			}
+			return &Response{Body: []byte(`{"object":"page","id":"stub"}`)}, nil
+		}
+	}
+	client := newMiddlewareTestClient(t, srv, stub)
+	ctx := context.Background()
+
+	var page struct{ ID string }
+	if err := client.Get(ctx, "pages/p1", nil, &page); err != nil || page.ID != "stub" {
+		t.Errorf("Expected the synthetic response, got %+v, %v", page, err)
+	}
+	resp, err := client.Do(ctx, "GET", "pages/p1", nil)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if resp.StatusCode() != http.StatusOK || !strings.Contains(string(resp.Body()), "stub") {
+		t.Errorf("Expected Do to return the synthetic response, got %d %s", resp.StatusCode(), resp.Body())
+	}
+	fasthttp.ReleaseResponse(resp)
+
+	err = client.Get(ctx, "pages/missing", nil, nil)
+	if !errors.IsNotFound(err) || !strings.Contains(err.Error(), "stubbed") {
+		t.Errorf("Expected a synthetic 404 to become an API error, got %v", err)
+	}
+	if len(srv.requests) != 0 {
+		t.Errorf("Expected no requests to reach the server, got %d", len(srv.requests))
+	}
+}
+
+func TestRequestWithoutMiddleware(t *testing.T) {
+	srv := &recordServer{handler: func(w http.ResponseWriter, r *http.Request, n int) {
+		if r.URL.Path == "/pages/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":"object_not_found","message":"missing"}`))
+			return
+		}
+		w.Write([]byte(`{"object":"page","id":"p1"}`))
+	}}
+	client := newMiddlewareTestClient(t, srv)
+	client.handler = func(ctx context.Context, req *Request) (*Response, error) {
+		t.Errorf("Expected %s %s to bypass the middleware chain", req.Method, req.Path)
+		return nil, nil
+	}
+	ctx := context.Background()
+
+	var page struct{ ID string }
+	if err := client.Get(ctx, "pages/p1", nil, &page); err != nil || page.ID != "p1" {
+		t.Errorf("Expected the server response, got %+v, %v", page, err)
+	}
+	err := client.Get(ctx, "pages/missing", nil, nil)
+	var apiErr *errors.Error
+	if !stderrors.As(err, &apiErr) || apiErr.Code != errors.ErrObjectNotFound || apiErr.RequestID != "notion-req" {
+		t.Errorf("Expected an object_not_found error with the request ID, got %v", err)
+	}
+}
+
+func TestMiddlewareObserveError(t *testing.T) {
+	srv := &recordServer{handler: func(w http.ResponseWriter, r *http.Request, n int) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"object":"error","status":400,"code":"validation_error","message":"bad"}`))
+	}}
+	var status int
+	observe := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			resp, err := next(ctx, req)
+			if apiErr, ok := err.(*errors.Error); ok {
+				status = apiErr.Status
+			}
+			return resp, err
+		}
+	}
+	client := newMiddlewareTestClient(t, srv, observe)
+
+	if err := client.Post(context.Background(), "pages", map[string]string{}, nil); !errors.IsValidationError(err) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected the middleware to observe status 400, got %d", status)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	srv := &recordServer{}
+	client := newMiddlewareTestClient(t, srv, ReadOnly())
+	ctx := context.Background()
+
+	allowed := []struct{ method, path string }{
+		{"GET", "pages/p1"},
+		{"POST", "search"},
+		{"POST", "databases/db1/query?filter_properties=title"},
+	}
+	for _, r := range allowed {
+		if err := client.request(ctx, r.method, r.path, map[string]string{}, nil); err != nil {
+			t.Errorf("Expected %s %s to be allowed, got %v", r.method, r.path, err)
+		}
+	}
+
+	blocked := []struct{ method, path string }{
+		{"PATCH", "pages/p1"},
+		{"POST", "pages"},
+		{"DELETE", "blocks/b1"},
+		{"POST", "comments"},
+	}
+	for _, r := range blocked {
+		err := client.request(ctx, r.method, r.path, map[string]string{}, nil)
+		if e, ok := err.(*errors.Error); !ok || e.Code != errors.ErrReadOnly {
+			t.Errorf("Expected %s %s to be blocked, got %v", r.method, r.path, err)
+		}
+	}
+	if len(srv.requests) != len(allowed) {
+		t.Errorf("Expected only read requests to reach the server, got %d", len(srv.requests))
+	}
+}
+
+func TestPropagateRequestID(t *testing.T) {
+	srv := &recordServer{}
+	var got [2]string
+	client := newMiddlewareTestClient(t, srv,
+		SetHeaders(map[string]string{"X-Tenant": "acme"}),
+		PropagateRequestID("", func(ctx context.Context, requestID, notionRequestID string) {
+			got = [2]string{requestID, notionRequestID}
+		}),
+	)
+
+	ctx := ContextWithRequestID(context.Background(), "upstream-1")
+	if err := client.Get(ctx, "pages/p1", nil, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	h := srv.requests[0].Header
+	if h.Get("X-Request-Id") != "upstream-1" || h.Get("X-Tenant") != "acme" {
+		t.Errorf("Expected propagated headers, got %v", h)
+	}
+	if got != [2]string{"upstream-1", "notion-req"} {
+		t.Errorf("Expected callback with both request ids, got %v", got)
+	}
+
+	// failed calls report the request id from the error
+	srv.handler = func(w http.ResponseWriter, r *http.Request, n int) {
+		w.Header().Set("X-Notion-Request-Id", "notion-err")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": "object_not_found", "message": "missing"})
+	}
+	client.Get(ctx, "pages/p2", nil, nil)
+	if got[1] != "notion-err" {
+		t.Errorf("Expected the request id from the error, got %v", got)
+	}
+}
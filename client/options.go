@@ -0,0 +1,337 @@
+package client
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kuekiko/NotionGO/cache"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpproxy"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option 表示客户端配置选项
+type Option func(*options)
+
+// options 保存 NewClient 使用的配置
+type options struct {
+	baseURL    string
+	apiVersion string
+	userAgent  string
+	timeout    time.Duration
+
+	readTimeout         time.Duration
+	writeTimeout        time.Duration
+	maxConnsPerHost     int
+	maxIdleConnDuration time.Duration
+	maxConnWaitTimeout  time.Duration
+	maxResponseBodySize int
+	dial                fasthttp.DialFunc
+	proxyURL            string
+	tlsConfig           *tls.Config
+
+	transport  Transport
+	netHTTP    bool
+	httpClient *http.Client
+
+	retryCount   int
+	retryWaitMin time.Duration
+	retryWaitMax time.Duration
+	retryPolicy  RetryPolicy
+
+	logger      Logger
+	logBodySize int
+
+	rateLimit       float64
+	rateBurst       int
+	rateLimiter     RateLimiter
+	sharedRateLimit bool
+	maxConcurrent   int
+
+	cache cache.Cache
+
+	basicAuth string
+
+	middleware []Middleware
+
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// defaultOptions 返回默认配置
+func defaultOptions() *options {
+	return &options{
+		baseURL:             BaseURL,
+		apiVersion:          APIVersion,
+		userAgent:           DefaultUserAgent,
+		readTimeout:         10 * time.Second,
+		writeTimeout:        10 * time.Second,
+		maxConnsPerHost:     10000,
+		maxIdleConnDuration: 10 * time.Second,
+		maxConnWaitTimeout:  30 * time.Second,
+		maxResponseBodySize: 50 * 1024 * 1024, // 50MB
+		retryCount:          3,
+		retryWaitMin:        1 * time.Second,
+		retryWaitMax:        30 * time.Second,
+		rateLimit:           DefaultRateLimit,
+		rateBurst:           DefaultRateBurst,
+	}
+}
+
+// WithBaseURL 设置 API 基础 URL，例如指向本地模拟服务器或企业出口代理
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+		o.baseURL = baseURL
+	}
+}
+
+// WithAPIVersion 设置 Notion-Version 请求头
+func WithAPIVersion(version string) Option {
+	return func(o *options) {
+		o.apiVersion = version
+	}
+}
+
+// WithBasicAuth 使用 HTTP Basic 认证代替 Bearer 令牌，用于 OAuth 的令牌端点
+func WithBasicAuth(clientID, clientSecret string) Option {
+	return func(o *options) {
+		o.basicAuth = base64.StdEncoding.EncodeToString([]byte(clientID + ":" + clientSecret))
+	}
+}
+
+// WithUserAgent 设置 User-Agent 请求头
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithTimeout 设置单次调用（包括重试）的总超时时间，0 表示不限制
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithReadTimeout 设置读取响应的超时时间
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.readTimeout = timeout
+	}
+}
+
+// WithWriteTimeout 设置发送请求的超时时间
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.writeTimeout = timeout
+	}
+}
+
+// WithMaxConnsPerHost 设置每个主机的最大连接数
+func WithMaxConnsPerHost(n int) Option {
+	return func(o *options) {
+		o.maxConnsPerHost = n
+	}
+}
+
+// WithMaxIdleConnDuration 设置空闲连接的保留时间
+func WithMaxIdleConnDuration(d time.Duration) Option {
+	return func(o *options) {
+		o.maxIdleConnDuration = d
+	}
+}
+
+// WithMaxConnWaitTimeout 设置连接数达到上限时等待空闲连接的最长时间
+func WithMaxConnWaitTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.maxConnWaitTimeout = d
+	}
+}
+
+// WithMaxResponseBodySize 设置响应体的最大字节数
+func WithMaxResponseBodySize(size int) Option {
+	return func(o *options) {
+		o.maxResponseBodySize = size
+	}
+}
+
+// WithDialer 设置建立连接使用的拨号函数
+func WithDialer(dial fasthttp.DialFunc) Option {
+	return func(o *options) {
+		o.dial = dial
+	}
+}
+
+// WithProxy 通过代理服务器发送请求，支持 http://[user:pass@]host:port 和 socks5://host:port
+//
+// https:// 代理需要和代理服务器建立 TLS 连接，只有 WithHTTPClient 使用的 net/http 传输支持，
+// 默认的 fasthttp 传输发送请求时返回错误。
+func WithProxy(proxyURL string) Option {
+	return func(o *options) {
+		o.proxyURL = proxyURL
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			// 不带协议的地址按 HTTP 代理处理
+			o.dial = fasthttpproxy.FasthttpHTTPDialer(proxyURL)
+			return
+		}
+		switch u.Scheme {
+		case "socks5", "socks5h":
+			o.dial = fasthttpproxy.FasthttpSocksDialer(proxyURL)
+		case "https":
+			o.dial = func(string) (net.Conn, error) {
+				return nil, fmt.Errorf("fasthttp 传输不支持 https 代理 %s，请同时使用 WithHTTPClient(nil)", u.Redacted())
+			}
+		default:
+			addr := u.Host
+			if u.User != nil {
+				// fasthttpproxy 原样编码 @ 之前的部分，使用解码后的用户名和密码
+				userinfo := u.User.Username()
+				if password, ok := u.User.Password(); ok {
+					userinfo += ":" + password
+				}
+				addr = userinfo + "@" + addr
+			}
+			o.dial = fasthttpproxy.FasthttpHTTPDialer(addr)
+		}
+	}
+}
+
+// WithTLSConfig 设置 TLS 配置
+func WithTLSConfig(config *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = config
+	}
+}
+
+// WithTransport 使用自定义的 Transport 发送请求，优先于 WithHTTPClient 和 fasthttp 相关的选项
+func WithTransport(t Transport) Option {
+	return func(o *options) {
+		o.transport = t
+	}
+}
+
+// WithHTTPClient 使用 net/http 代替 fasthttp 发送请求
+//
+// c 为 nil 时根据 WithTLSConfig、WithProxy、WithDialer、WithReadTimeout、WithMaxConnsPerHost 等选项创建，
+// 支持 HTTP/2，没有设置 WithProxy 时读取 HTTPS_PROXY 等环境变量；c 不为 nil 时这些选项不生效。
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.netHTTP = true
+		o.httpClient = c
+	}
+}
+
+// WithMiddleware 添加中间件，可以多次使用
+//
+// 中间件按添加的顺序执行：先添加的中间件先看到请求，后看到响应和错误。
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(o *options) {
+		o.middleware = append(o.middleware, middleware...)
+	}
+}
+
+// WithMaxRetries 设置默认重试策略的最大重试次数
+func WithMaxRetries(n int) Option {
+	return func(o *options) {
+		o.retryCount = n
+	}
+}
+
+// WithRetryWaitTime 设置默认重试策略的最小和最大等待时间
+func WithRetryWaitTime(min, max time.Duration) Option {
+	return func(o *options) {
+		o.retryWaitMin = min
+		o.retryWaitMax = max
+	}
+}
+
+// WithRetryPolicy 设置重试策略，优先于 WithMaxRetries 和 WithRetryWaitTime
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithLogger 设置日志记录器，默认不输出日志
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithLogBody 在 Debug 级别记录请求体和响应体，超过 maxBytes 的部分被截断，0 表示不记录
+func WithLogBody(maxBytes int) Option {
+	return func(o *options) {
+		o.logBodySize = maxBytes
+	}
+}
+
+// WithTracerProvider 为每次调用记录一个 span，默认不记录
+//
+// span 以发起请求的服务方法命名，例如 "Pages.Get"，记录路径模板、对象 ID、状态码、
+// Notion 返回的请求 ID、重试次数和等待速率限制器的时间。缓存命中和被中间件直接返回的调用不记录 span。
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider 记录请求数、耗时、429 次数和请求体、响应体的字节数，默认不记录
+//
+// 指标按每次发送记录，重试单独计数，属性包括请求方法、路径模板和状态码。
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(o *options) {
+		o.meterProvider = mp
+	}
+}
+
+// WithRateLimit 设置每秒允许的请求数和突发请求数，默认为每秒 3 个、突发 10 个，
+// requestsPerSecond 不大于 0 时不限制速率
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(o *options) {
+		o.rateLimit = requestsPerSecond
+		o.rateBurst = burst
+	}
+}
+
+// WithRateLimiter 使用自定义的速率限制器，优先于 WithRateLimit 和 WithSharedRateLimit
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(o *options) {
+		o.rateLimiter = limiter
+	}
+}
+
+// WithSharedRateLimit 让使用同一个令牌的所有 Client 共用一个速率限制器
+//
+// 第一个创建的 Client 的 WithRateLimit 设置生效，之后设置不同的 Client 仍然使用已有的限制器，
+// 并通过 Logger 记录一条 Warn 日志。
+func WithSharedRateLimit() Option {
+	return func(o *options) {
+		o.sharedRateLimit = true
+	}
+}
+
+// WithMaxConcurrentRequests 设置同时进行的最大请求数，0 表示不限制
+func WithMaxConcurrentRequests(n int) Option {
+	return func(o *options) {
+		o.maxConcurrent = n
+	}
+}
+
+// WithCache 缓存页面、数据库、用户和块的 GET 响应，默认不缓存
+func WithCache(c cache.Cache) Option {
+	return func(o *options) {
+		o.cache = c
+	}
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRateLimit 是默认每秒允许的请求数，与 Notion 对每个集成的平均限制一致
+	DefaultRateLimit = 3
+	// DefaultRateBurst 是默认允许的突发请求数
+	DefaultRateBurst = 10
+)
+
+// RateLimiter 限制发送请求的速率
+//
+// 同一个 RateLimiter 可以被多个 Client 共用，实现必须是并发安全的。
+type RateLimiter interface {
+	// Wait 阻塞直到可以发送下一个请求，ctx 结束时返回 ctx 的错误
+	Wait(ctx context.Context) error
+	// Pause 在 d 时间内暂停发送请求，用于响应 429 和 Retry-After
+	Pause(d time.Duration)
+}
+
+// TokenBucket 是令牌桶速率限制器
+//
+// 令牌以固定速率补充，最多积累 burst 个。等待中的请求按到达顺序获得令牌，
+// Pause 会清空剩余的令牌，所有请求都在暂停结束后重新按速率发送。
+type TokenBucket struct {
+	mu          sync.Mutex
+	rate        float64   // 每秒补充的令牌数
+	burst       float64   // 令牌上限
+	tokens      float64   // last 时刻的令牌数，为负数时表示已经预约的请求
+	last        time.Time // tokens 对应的时刻，暂停时可能在未来
+	pausedUntil time.Time
+}
+
+// NewTokenBucket 创建每秒允许 rate 个请求、最多突发 burst 个请求的令牌桶
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait 实现 RateLimiter
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if err := sleep(ctx, b.reserve()); err != nil {
+		b.cancel()
+		return err
+	}
+	// 等待期间收到 429 时，继续等到暂停结束
+	for {
+		b.mu.Lock()
+		wait := time.Until(b.pausedUntil)
+		b.mu.Unlock()
+		if wait <= 0 {
+			return nil
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve 预约一个令牌，返回需要等待的时间
+func (b *TokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.refill(now)
+	b.tokens--
+	at := b.last
+	if b.tokens < 0 {
+		at = at.Add(time.Duration(-b.tokens / b.rate * float64(time.Second)))
+	}
+	return at.Sub(now)
+}
+
+// cancel 归还未使用的令牌
+func (b *TokenBucket) cancel() {
+	b.mu.Lock()
+	b.tokens++
+	b.mu.Unlock()
+}
+
+// refill 补充到 now 时刻的令牌
+func (b *TokenBucket) refill(now time.Time) {
+	if !now.After(b.last) {
+		return
+	}
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// Pause 实现 RateLimiter
+func (b *TokenBucket) Pause(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	until := now.Add(d)
+	b.refill(now)
+	if b.tokens > 0 {
+		b.tokens = 0
+	}
+	if until.After(b.last) {
+		b.last = until
+	}
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// sharedLimiters 保存按令牌共享的速率限制器，键是令牌的哈希，不在内存中保留明文令牌
+var sharedLimiters limiterSet
+
+// sharedLimiter 返回 apiKey 共用的速率限制器，不存在时使用 newLimiter 创建
+func sharedLimiter(apiKey string, newLimiter func() RateLimiter) RateLimiter {
+	return sharedLimiters.get(cacheScope(apiKey), newLimiter)
+}
+
+// matches 判断令牌桶是否按 rate 和 burst 创建
+func (b *TokenBucket) matches(rate float64, burst int) bool {
+	if burst < 1 {
+		burst = 1
+	}
+	return b.rate == rate && b.burst == float64(burst)
+}
+
+// semaphore 限制同时进行的请求数，nil 表示不限制
+type semaphore chan struct{}
+
+// acquire 占用一个位置，ctx 结束时返回 ctx 的错误
+func (s semaphore) acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release 释放 acquire 占用的位置
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurstAndRate(t *testing.T) {
+	b := NewTokenBucket(50, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := b.Wait(ctx); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("Expected burst requests to pass immediately, took %v", elapsed)
+	}
+
+	// Two more requests need two new tokens at 50/s = 40ms
+	for i := 0; i < 2; i++ {
+		if err := b.Wait(ctx); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("Expected requests beyond burst to be throttled, took %v", elapsed)
+	}
+}
+
+func TestTokenBucketPause(t *testing.T) {
+	b := NewTokenBucket(1000, 10)
+	b.Pause(50 * time.Millisecond)
+
+	start := time.Now()
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("Expected Wait to block until the pause ends, took %v", elapsed)
+	}
+}
+
+func TestTokenBucketCanceled(t *testing.T) {
+	b := NewTokenBucket(1, 1)
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); err == nil {
+		t.Fatal("Expected error when context expires before a token is available")
+	}
+
+	// The canceled reservation must not delay later requests
+	b.mu.Lock()
+	tokens := b.tokens
+	b.mu.Unlock()
+	if tokens < -0.01 {
+		t.Errorf("Expected canceled reservation to be returned, tokens = %v", tokens)
+	}
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	var inFlight, peak int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient("test-token", WithBaseURL(ts.URL), WithRateLimit(0, 0), WithMaxConcurrentRequests(2))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.Get(context.Background(), "users/me", nil, nil); err != nil {
+				t.Errorf("Request failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", peak)
+	}
+}
+
+// recordingLimiter records calls to Pause
+type recordingLimiter struct {
+	mu     sync.Mutex
+	waits  int
+	pauses []time.Duration
+}
+
+func (l *recordingLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.waits++
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *recordingLimiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	l.pauses = append(l.pauses, d)
+	l.mu.Unlock()
+}
+
+func TestRateLimiterPausesOnRetryAfter(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"object":"error","status":429,"code":"rate_limited","message":"slow down"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	limiter := &recordingLimiter{}
+	client := NewClient("test-token", WithBaseURL(ts.URL), WithRateLimiter(limiter), WithRetryWaitTime(time.Millisecond, time.Second))
+	if err := client.Get(context.Background(), "users/me", nil, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	if limiter.waits != 2 {
+		t.Errorf("Expected the limiter to be consulted before each attempt, got %d", limiter.waits)
+	}
+	if len(limiter.pauses) != 1 || limiter.pauses[0] != time.Second {
+		t.Errorf("Expected a single 1s pause, got %v", limiter.pauses)
+	}
+}
+
+func TestSharedRateLimit(t *testing.T) {
+	a := NewClient("shared-token", WithSharedRateLimit())
+	b := NewClient("shared-token", WithSharedRateLimit())
+	c := NewClient("other-token", WithSharedRateLimit())
+	d := NewClient("shared-token")
+
+	if a.limiter != b.limiter {
+		t.Error("Expected clients with the same token to share a limiter")
+	}
+	if a.limiter == c.limiter {
+		t.Error("Expected clients with different tokens to use separate limiters")
+	}
+	if a.limiter == d.limiter {
+		t.Error("Expected clients without WithSharedRateLimit to use their own limiter")
+	}
+}
+
+func TestSharedRateLimitKey(t *testing.T) {
+	NewClient("plaintext-token", WithSharedRateLimit())
+
+	sharedLimiters.mu.Lock()
+	defer sharedLimiters.mu.Unlock()
+	if _, ok := sharedLimiters.m[cacheScope("plaintext-token")]; !ok {
+		t.Error("Expected the shared limiter to be keyed by the token hash")
+	}
+	for key := range sharedLimiters.m {
+		if strings.Contains(key, "plaintext-token") {
+			t.Errorf("Token stored in plaintext as %q", key)
+		}
+	}
+}
+
+func TestSharedRateLimitConflict(t *testing.T) {
+	var warnings []string
+	logger := LoggerFunc(func(ctx context.Context, level LogLevel, msg string, args ...interface{}) {
+		if level == LogLevelWarn {
+			warnings = append(warnings, msg)
+		}
+	})
+
+	first := NewClient("conflict-token", WithSharedRateLimit(), WithRateLimit(3, 10), WithLogger(logger))
+	same := NewClient("conflict-token", WithSharedRateLimit(), WithRateLimit(3, 10), WithLogger(logger))
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warning for matching settings, got %v", warnings)
+	}
+
+	other := NewClient("conflict-token", WithSharedRateLimit(), WithRateLimit(1, 1), WithLogger(logger))
+	if len(warnings) != 1 {
+		t.Errorf("Expected a warning for conflicting settings, got %v", warnings)
+	}
+	if first.limiter != same.limiter || first.limiter != other.limiter {
+		t.Error("Expected the first client's limiter to be shared")
+	}
+}
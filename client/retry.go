@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Attempt 描述一次已完成的请求尝试，供 RetryPolicy 决定是否重试
+type Attempt struct {
+	Method     string        // 请求方法
+	Path       string        // 请求路径
+	Number     int           // 已完成的尝试次数，从 1 开始
+	StatusCode int           // 响应状态码，请求未得到响应时为 0
+	RetryAfter time.Duration // 响应 Retry-After 头给出的等待时间，未设置时为 0
+	Err        error         // 发送请求时的错误，得到响应时为 nil
+}
+
+// RetryPolicy 表示重试策略
+type RetryPolicy interface {
+	// Backoff 返回下一次重试前的等待时间，第二个返回值为 false 时不再重试
+	Backoff(a *Attempt) (time.Duration, bool)
+}
+
+// RetryPolicyFunc 将普通函数适配为 RetryPolicy
+type RetryPolicyFunc func(a *Attempt) (time.Duration, bool)
+
+// Backoff 实现 RetryPolicy
+func (f RetryPolicyFunc) Backoff(a *Attempt) (time.Duration, bool) {
+	return f(a)
+}
+
+// NoRetry 是从不重试的策略
+var NoRetry RetryPolicy = RetryPolicyFunc(func(*Attempt) (time.Duration, bool) {
+	return 0, false
+})
+
+// ExponentialBackoff 是带随机抖动的指数退避重试策略
+//
+// 429 响应对所有方法都会重试，因为 Notion 在处理请求之前就会拒绝超限的请求；
+// 502/503/504 和连接被重置只对幂等方法重试，除非设置了 RetryNonIdempotent；
+// 建立连接失败时请求还没有发出，对所有方法都会重试。
+type ExponentialBackoff struct {
+	MaxRetries         int           // 最大重试次数
+	WaitMin            time.Duration // 最小等待时间
+	WaitMax            time.Duration // 最大等待时间
+	RetryNonIdempotent bool          // 是否重试 POST、PATCH 等非幂等请求
+}
+
+// Backoff 实现 RetryPolicy
+func (p *ExponentialBackoff) Backoff(a *Attempt) (time.Duration, bool) {
+	if a.Number > p.MaxRetries {
+		return 0, false
+	}
+
+	switch {
+	case a.StatusCode == http.StatusTooManyRequests:
+		if a.RetryAfter > 0 {
+			return a.RetryAfter, true
+		}
+	case a.Err != nil && isDialError(a.Err):
+	case a.StatusCode == http.StatusBadGateway,
+		a.StatusCode == http.StatusServiceUnavailable,
+		a.StatusCode == http.StatusGatewayTimeout,
+		a.Err != nil && isConnectionReset(a.Err):
+		if !p.RetryNonIdempotent && !isIdempotent(a.Method) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	return p.wait(a.Number), true
+}
+
+// wait 计算第 n 次重试的等待时间：WaitMin * 2^(n-1)，上限为 WaitMax，并在后半段随机抖动
+func (p *ExponentialBackoff) wait(n int) time.Duration {
+	wait := p.WaitMin
+	for i := 1; i < n && wait < p.WaitMax; i++ {
+		wait *= 2
+	}
+	if wait > p.WaitMax {
+		wait = p.WaitMax
+	}
+	if half := int64(wait / 2); half > 0 {
+		wait = time.Duration(half + rand.Int63n(half+1))
+	}
+	if wait < p.WaitMin {
+		wait = p.WaitMin
+	}
+	return wait
+}
+
+// isIdempotent 检查请求方法是否幂等
+func isIdempotent(method string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// isConnectionReset 检查错误是否由连接被重置或提前关闭引起
+func isConnectionReset(err error) bool {
+	return stderrors.Is(err, syscall.ECONNRESET) ||
+		stderrors.Is(err, syscall.EPIPE) ||
+		stderrors.Is(err, io.EOF) ||
+		stderrors.Is(err, io.ErrUnexpectedEOF) ||
+		stderrors.Is(err, fasthttp.ErrConnectionClosed)
+}
+
+// isDialError 检查错误是否发生在建立连接时，例如连接被拒绝、DNS 解析失败或网络不可达
+func isDialError(err error) bool {
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &dnsErr) {
+		return !dnsErr.Timeout()
+	}
+	var opErr *net.OpError
+	return stderrors.As(err, &opErr) && opErr.Op == "dial" && !opErr.Timeout()
+}
+
+// parseRetryAfter 解析 Retry-After 头，支持秒数和 HTTP 日期两种格式
+func parseRetryAfter(v string) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleep 等待 d，ctx 结束时提前返回 ctx 的错误
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
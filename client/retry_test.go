@@ -0,0 +1,149 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	policy := &ExponentialBackoff{
+		MaxRetries: 3,
+		WaitMin:    100 * time.Millisecond,
+		WaitMax:    time.Second,
+	}
+
+	tests := []struct {
+		name    string
+		attempt Attempt
+		retry   bool
+	}{
+		{"429 GET", Attempt{Method: "GET", Number: 1, StatusCode: http.StatusTooManyRequests}, true},
+		{"429 POST", Attempt{Method: "POST", Number: 1, StatusCode: http.StatusTooManyRequests}, true},
+		{"503 GET", Attempt{Method: "GET", Number: 2, StatusCode: http.StatusServiceUnavailable}, true},
+		{"503 POST", Attempt{Method: "POST", Number: 1, StatusCode: http.StatusServiceUnavailable}, false},
+		{"502 DELETE", Attempt{Method: "DELETE", Number: 1, StatusCode: http.StatusBadGateway}, true},
+		{"reset GET", Attempt{Method: "GET", Number: 1, Err: syscall.ECONNRESET}, true},
+		{"reset PATCH", Attempt{Method: "PATCH", Number: 1, Err: syscall.ECONNRESET}, false},
+		{"refused POST", Attempt{Method: "POST", Number: 1, Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}, true},
+		{"read error GET", Attempt{Method: "GET", Number: 1, Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("tls: bad record MAC")}}, false},
+		{"400 GET", Attempt{Method: "GET", Number: 1, StatusCode: http.StatusBadRequest}, false},
+		{"500 GET", Attempt{Method: "GET", Number: 1, StatusCode: http.StatusInternalServerError}, false},
+		{"exhausted", Attempt{Method: "GET", Number: 4, StatusCode: http.StatusTooManyRequests}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, retry := policy.Backoff(&tt.attempt)
+			if retry != tt.retry {
+				t.Fatalf("Expected retry %v, got %v", tt.retry, retry)
+			}
+			if retry && (wait < policy.WaitMin || wait > policy.WaitMax) {
+				t.Errorf("Expected wait in [%v, %v], got %v", policy.WaitMin, policy.WaitMax, wait)
+			}
+		})
+	}
+}
+
+func TestIsConnectionReset(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"ECONNRESET", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, true},
+		{"EPIPE", &net.OpError{Op: "write", Net: "tcp", Err: syscall.EPIPE}, true},
+		{"EOF", fmt.Errorf("read response: %w", io.EOF), true},
+		{"connection closed", fasthttp.ErrConnectionClosed, true},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, false},
+		{"dns", &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "api.notion.invalid", IsNotFound: true}}, false},
+		{"other read error", &net.OpError{Op: "read", Net: "tcp", Err: errors.New("tls: bad record MAC")}, false},
+	}
+	for _, tt := range tests {
+		if got := isConnectionReset(tt.err); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestIsDialError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{"network unreachable", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ENETUNREACH}, true},
+		{"dns", &net.DNSError{Err: "no such host", Name: "api.notion.invalid", IsNotFound: true}, true},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", Name: "api.notion.invalid", IsTimeout: true}, false},
+		{"reset", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, false},
+		{"EOF", io.EOF, false},
+	}
+	for _, tt := range tests {
+		if got := isDialError(tt.err); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	// a real dial against a closed port
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	if _, err := fasthttp.Dial(addr); err == nil || !isDialError(err) || isConnectionReset(err) {
+		t.Errorf("Expected a dial error for a closed port, got %v", err)
+	}
+}
+
+func TestExponentialBackoffNonIdempotent(t *testing.T) {
+	policy := &ExponentialBackoff{
+		MaxRetries:         1,
+		WaitMin:            time.Millisecond,
+		WaitMax:            time.Millisecond,
+		RetryNonIdempotent: true,
+	}
+
+	if _, retry := policy.Backoff(&Attempt{Method: "POST", Number: 1, StatusCode: http.StatusGatewayTimeout}); !retry {
+		t.Error("Expected POST to be retried when RetryNonIdempotent is set")
+	}
+}
+
+func TestExponentialBackoffRetryAfter(t *testing.T) {
+	policy := &ExponentialBackoff{
+		MaxRetries: 3,
+		WaitMin:    time.Millisecond,
+		WaitMax:    10 * time.Millisecond,
+	}
+
+	wait, retry := policy.Backoff(&Attempt{
+		Method:     "POST",
+		Number:     1,
+		StatusCode: http.StatusTooManyRequests,
+		RetryAfter: 2 * time.Second,
+	})
+	if !retry || wait != 2*time.Second {
+		t.Errorf("Expected to wait Retry-After 2s, got %v (retry=%v)", wait, retry)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := parseRetryAfter("3"); d != 3*time.Second {
+		t.Errorf("Expected 3s, got %v", d)
+	}
+	if d := parseRetryAfter(""); d != 0 {
+		t.Errorf("Expected 0, got %v", d)
+	}
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if d := parseRetryAfter(future); d <= 0 || d > time.Minute {
+		t.Errorf("Expected a positive duration up to 1m, got %v", d)
+	}
+}
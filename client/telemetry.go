@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationName 是创建 Tracer 和 Meter 时使用的名称
+const instrumentationName = "github.com/kuekiko/NotionGO/client"
+
+// span 和指标使用的属性名
+const (
+	attrMethod        = attribute.Key("http.request.method")
+	attrStatusCode    = attribute.Key("http.response.status_code")
+	attrPath          = attribute.Key("notion.path")
+	attrObjectID      = attribute.Key("notion.object_id")
+	attrRequestID     = attribute.Key("notion.request_id")
+	attrRetryAttempts = attribute.Key("notion.retry_attempts")
+	attrRateLimitWait = attribute.Key("notion.rate_limit.wait_ms")
+)
+
+// operationNames 把请求方法和路径模板映射为 span 名称，即发起请求的服务方法
+var operationNames = map[string]string{
+	"POST pages":                              "Pages.Create",
+	"GET pages/{id}":                          "Pages.Get",
+	"PATCH pages/{id}":                        "Pages.Update",
+	"DELETE pages/{id}":                       "Pages.Delete",
+	"GET pages/{id}/properties":               "Pages.GetPropertyList",
+	"GET pages/{id}/properties/{property_id}": "Pages.GetProperty",
+	"POST databases":                          "Database.Create",
+	"GET databases":                           "Database.List",
+	"GET databases/{id}":                      "Database.Get",
+	"PATCH databases/{id}":                    "Database.Update",
+	"DELETE databases/{id}":                   "Database.Delete",
+	"POST databases/{id}/query":               "Database.Query",
+	"GET blocks/{id}":                         "Blocks.Get",
+	"PATCH blocks/{id}":                       "Blocks.Update",
+	"DELETE blocks/{id}":                      "Blocks.Delete",
+	"GET blocks/{id}/children":                "Blocks.ListChildren",
+	"PATCH blocks/{id}/children":              "Blocks.AppendChildren",
+	"GET users":                               "Users.List",
+	"GET users/{id}":                          "Users.Get",
+	"GET users/me":                            "Users.Me",
+	"POST search":                             "Search.Search",
+	"GET comments":                            "Comments.List",
+	"POST comments":                           "Comments.Create",
+	"POST oauth/token":                        "OAuth.Exchange",
+	"POST oauth/introspect":                   "OAuth.Introspect",
+	"POST oauth/revoke":                       "OAuth.Revoke",
+}
+
+// telemetry 保存创建好的 Tracer 和指标，没有设置 TracerProvider 和 MeterProvider 时为 nil
+type telemetry struct {
+	tracer        trace.Tracer
+	requests      metric.Int64Counter
+	duration      metric.Float64Histogram
+	rateLimited   metric.Int64Counter
+	bytesSent     metric.Int64Counter
+	bytesReceived metric.Int64Counter
+}
+
+// newTelemetry 使用 tp 和 mp 创建 telemetry，两者都为 nil 时返回 nil，请求不做任何额外的工作
+func newTelemetry(tp trace.TracerProvider, mp metric.MeterProvider) *telemetry {
+	if tp == nil && mp == nil {
+		return nil
+	}
+	if tp == nil {
+		tp = tracenoop.NewTracerProvider()
+	}
+	if mp == nil {
+		mp = metricnoop.NewMeterProvider()
+	}
+	meter := mp.Meter(instrumentationName)
+	t := &telemetry{tracer: tp.Tracer(instrumentationName)}
+	// 指标名称是固定的，创建失败时 OpenTelemetry 仍然返回可用的实现，忽略错误
+	t.requests, _ = meter.Int64Counter("notion.client.requests",
+		metric.WithDescription("发送的 HTTP 请求数，每次重试单独计数"),
+		metric.WithUnit("{request}"))
+	t.duration, _ = meter.Float64Histogram("notion.client.request.duration",
+		metric.WithDescription("单次 HTTP 请求的耗时"),
+		metric.WithUnit("s"))
+	t.rateLimited, _ = meter.Int64Counter("notion.client.rate_limited",
+		metric.WithDescription("收到 429 响应的次数"),
+		metric.WithUnit("{response}"))
+	t.bytesSent, _ = meter.Int64Counter("notion.client.request.body.size",
+		metric.WithDescription("发送的请求体字节数"),
+		metric.WithUnit("By"))
+	t.bytesReceived, _ = meter.Int64Counter("notion.client.response.body.size",
+		metric.WithDescription("收到的响应体字节数"),
+		metric.WithUnit("By"))
+	return t
+}
+
+// pathTemplate 把路径中的对象 ID 替换为占位符，返回路径模板和对象 ID
+//
+// 例如 "blocks/abc/children?page_size=10" 返回 "blocks/{id}/children" 和 "abc"。
+func pathTemplate(path string) (template, objectID string) {
+	seg := splitPath(path)
+	if len(seg) >= 2 && seg[0] != "oauth" && !(seg[0] == "users" && seg[1] == "me") {
+		objectID = seg[1]
+		seg[1] = "{id}"
+	}
+	if len(seg) >= 4 && seg[2] == "properties" {
+		seg[3] = "{property_id}"
+	}
+	return strings.Join(seg, "/"), objectID
+}
+
+// callTelemetry 记录一次调用的 span 和每次发送的指标，为 nil 时所有方法都不做任何事
+type callTelemetry struct {
+	t         *telemetry
+	span      trace.Span
+	method    attribute.KeyValue
+	path      attribute.KeyValue
+	attempts  int
+	status    int
+	requestID string
+	wait      time.Duration
+}
+
+// start 为一次调用开始 span，返回的 ctx 带有该 span
+func (t *telemetry) start(ctx context.Context, method, path string) (context.Context, *callTelemetry) {
+	if t == nil {
+		return ctx, nil
+	}
+	method = strings.ToUpper(method)
+	template, objectID := pathTemplate(path)
+	name, ok := operationNames[method+" "+template]
+	if !ok {
+		name = method + " " + template
+	}
+	c := &callTelemetry{
+		t:      t,
+		method: attrMethod.String(method),
+		path:   attrPath.String(template),
+	}
+	ctx, c.span = t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+	if c.span.IsRecording() {
+		c.span.SetAttributes(c.method, c.path)
+		if objectID != "" {
+			c.span.SetAttributes(attrObjectID.String(objectID))
+		}
+	}
+	return ctx, c
+}
+
+// waitLimiter 等待速率限制器，并累计等待的时间
+func (c *callTelemetry) waitLimiter(ctx context.Context, l RateLimiter) error {
+	if c == nil {
+		return l.Wait(ctx)
+	}
+	start := time.Now()
+	err := l.Wait(ctx)
+	c.wait += time.Since(start)
+	return err
+}
+
+// attempt 记录一次发送的请求数、耗时、字节数和 429 次数
+func (c *callTelemetry) attempt(ctx context.Context, a *Attempt, latency time.Duration, sent, received int, requestID string) {
+	if c == nil {
+		return
+	}
+	c.attempts = a.Number
+	c.status = a.StatusCode
+	c.requestID = requestID
+
+	attrs := []attribute.KeyValue{c.method, c.path}
+	if a.StatusCode > 0 {
+		attrs = append(attrs, attrStatusCode.Int(a.StatusCode))
+	}
+	opt := metric.WithAttributes(attrs...)
+	c.t.requests.Add(ctx, 1, opt)
+	c.t.duration.Record(ctx, latency.Seconds(), opt)
+	c.t.bytesSent.Add(ctx, int64(sent), opt)
+	c.t.bytesReceived.Add(ctx, int64(received), opt)
+	if a.StatusCode == http.StatusTooManyRequests {
+		c.t.rateLimited.Add(ctx, 1, opt)
+	}
+}
+
+// retry 在 span 中添加一个重试事件
+func (c *callTelemetry) retry(a *Attempt, wait time.Duration) {
+	if c == nil || !c.span.IsRecording() {
+		return
+	}
+	attrs := []attribute.KeyValue{
+		attribute.Int("notion.attempt", a.Number),
+		attribute.Int64("notion.retry.wait_ms", wait.Milliseconds()),
+	}
+	if a.StatusCode > 0 {
+		attrs = append(attrs, attrStatusCode.Int(a.StatusCode))
+	}
+	c.span.AddEvent("retry", trace.WithAttributes(attrs...))
+}
+
+// end 结束 span，err 不为 nil 时把 span 标记为失败
+func (c *callTelemetry) end(err error) {
+	if c == nil {
+		return
+	}
+	if c.span.IsRecording() {
+		retries := 0
+		if c.attempts > 1 {
+			retries = c.attempts - 1
+		}
+		c.span.SetAttributes(
+			attrRetryAttempts.Int(retries),
+			attrRateLimitWait.Int64(c.wait.Milliseconds()),
+		)
+		if c.status > 0 {
+			c.span.SetAttributes(attrStatusCode.Int(c.status))
+		}
+		if c.requestID != "" {
+			c.span.SetAttributes(attrRequestID.String(c.requestID))
+		}
+		if err != nil {
+			c.span.RecordError(err)
+			c.span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	c.span.End()
+}
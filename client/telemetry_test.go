@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTelemetryTestClient(t *testing.T, srv *recordServer) (*Client, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	client := newMiddlewareTestClient(t, srv)
+	client.telemetry = newTelemetry(
+		sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	)
+	return client, spans, reader
+}
+
+// spanAttrs returns the attributes of span keyed by name
+func spanAttrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+// sumOf returns the total of the int64 sum metric named name
+func sumOf(t *testing.T, rm metricdata.ResourceMetrics, name string) int64 {
+	t.Helper()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			var total int64
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				total += dp.Value
+			}
+			return total
+		}
+	}
+	return 0
+}
+
+func TestTelemetryDisabledByDefault(t *testing.T) {
+	client := NewClient("test-token")
+	if client.telemetry != nil {
+		t.Error("Expected no telemetry without a tracer or meter provider")
+	}
+	ctx, tel := client.telemetry.start(context.Background(), "GET", "pages/p1")
+	if tel != nil || trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		t.Error("Expected start to be a no-op when telemetry is disabled")
+	}
+	tel.end(nil)
+}
+
+func TestTelemetrySpan(t *testing.T) {
+	srv := &recordServer{handler: func(w http.ResponseWriter, r *http.Request, n int) {
+		if n == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"object":"error","status":429,"code":"rate_limited","message":"slow down"}`))
+			return
+		}
+		w.Write([]byte(`{"object":"page","id":"p1"}`))
+	}}
+	client, spans, reader := newTelemetryTestClient(t, srv)
+
+	if err := client.Patch(context.Background(), "pages/p1", map[string]bool{"archived": true}, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("Expected one span for the call, got %d", len(ended))
+	}
+	span := ended[0]
+	if span.Name() != "Pages.Update" || span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("Expected client span Pages.Update, got %s %v", span.Name(), span.SpanKind())
+	}
+	attrs := spanAttrs(span)
+	checks := map[attribute.Key]string{
+		"http.request.method":       "PATCH",
+		"notion.path":               "pages/{id}",
+		"notion.object_id":          "p1",
+		"notion.request_id":         "notion-req",
+		"http.response.status_code": "200",
+		"notion.retry_attempts":     "1",
+	}
+	for key, want := range checks {
+		if got := attrs[key].Emit(); got != want {
+			t.Errorf("Expected %s %q, got %q", key, want, got)
+		}
+	}
+	if _, ok := attrs["notion.rate_limit.wait_ms"]; !ok {
+		t.Error("Expected the rate limit wait to be recorded")
+	}
+	if events := span.Events(); len(events) != 1 || events[0].Name != "retry" {
+		t.Errorf("Expected one retry event, got %+v", events)
+	}
+	if span.Status().Code == codes.Error {
+		t.Errorf("Expected the span to succeed, got %+v", span.Status())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	if n := sumOf(t, rm, "notion.client.requests"); n != 2 {
+		t.Errorf("Expected 2 requests, got %d", n)
+	}
+	if n := sumOf(t, rm, "notion.client.rate_limited"); n != 1 {
+		t.Errorf("Expected 1 rate limited response, got %d", n)
+	}
+	if n := sumOf(t, rm, "notion.client.request.body.size"); n != 2*int64(len(`{"archived":true}`)) {
+		t.Errorf("Expected the request body to be counted twice, got %d bytes", n)
+	}
+	if n := sumOf(t, rm, "notion.client.response.body.size"); n == 0 {
+		t.Error("Expected response bytes to be counted")
+	}
+	var histogram bool
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name == "notion.client.request.duration" {
+			var count uint64
+			for _, dp := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+				count += dp.Count
+			}
+			histogram = count == 2
+		}
+	}
+	if !histogram {
+		t.Error("Expected 2 latency observations")
+	}
+}
+
+func TestTelemetryError(t *testing.T) {
+	srv := &recordServer{handler: func(w http.ResponseWriter, r *http.Request, n int) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"missing"}`))
+	}}
+	client, spans, _ := newTelemetryTestClient(t, srv)
+
+	if err := client.Get(context.Background(), "blocks/b1/children?page_size=10", nil, nil); err == nil {
+		t.Fatal("Expected an error")
+	}
+	span := spans.Ended()[0]
+	if span.Name() != "Blocks.ListChildren" {
+		t.Errorf("Expected span Blocks.ListChildren, got %s", span.Name())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("Expected an error status, got %+v", span.Status())
+	}
+	if attrs := spanAttrs(span); attrs["http.response.status_code"].AsInt64() != 404 {
+		t.Errorf("Expected status 404, got %v", attrs["http.response.status_code"].Emit())
+	}
+}
+
+func TestPathTemplate(t *testing.T) {
+	tests := []struct {
+		method, path, template, objectID, name string
+	}{
+		{"GET", "pages/p1", "pages/{id}", "p1", "Pages.Get"},
+		{"GET", "pages/p1/properties/title", "pages/{id}/properties/{property_id}", "p1", "Pages.GetProperty"},
+		{"POST", "databases/db1/query?filter_properties=a", "databases/{id}/query", "db1", "Database.Query"},
+		{"GET", "users/me", "users/me", "", "Users.Me"},
+		{"POST", "oauth/token", "oauth/token", "", "OAuth.Exchange"},
+		{"get", "comments?block_id=b1", "comments", "", "Comments.List"},
+		{"PUT", "files/f1", "files/{id}", "f1", "PUT files/{id}"},
+	}
+	spans := tracetest.NewSpanRecorder()
+	tel := newTelemetry(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)), nil)
+	for _, tt := range tests {
+		template, objectID := pathTemplate(tt.path)
+		if template != tt.template || objectID != tt.objectID {
+			t.Errorf("pathTemplate(%q) = %q, %q, want %q, %q", tt.path, template, objectID, tt.template, tt.objectID)
+		}
+		_, c := tel.start(context.Background(), tt.method, tt.path)
+		c.end(nil)
+		if got := spans.Ended()[len(spans.Ended())-1].Name(); got != tt.name {
+			t.Errorf("Expected span name %q for %s %s, got %q", tt.name, tt.method, tt.path, got)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Transport 发送单个 HTTP 请求，实现必须是并发安全的
+//
+// 重试、速率限制、日志和错误解码都由 Client 完成，Transport 只需要发送 req 并把响应写入 resp。
+// ctx 带有截止时间时应作为请求的期限，ctx 被取消时应中断请求并关闭其连接；
+// Client 不等待 RoundTrip 返回，但会在它返回后才释放 req 和 resp。
+type Transport interface {
+	RoundTrip(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error
+}
+
+// TransportFunc 把函数转换为 Transport
+type TransportFunc func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error
+
+// RoundTrip 实现 Transport
+func (f TransportFunc) RoundTrip(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	return f(ctx, req, resp)
+}
+
+// httpDoer 表示可以发送 fasthttp 请求的客户端，*fasthttp.Client 和 *fasthttp.HostClient 均满足该接口
+type httpDoer interface {
+	Do(req *fasthttp.Request, resp *fasthttp.Response) error
+	DoDeadline(req *fasthttp.Request, resp *fasthttp.Response, deadline time.Time) error
+}
+
+// fastHTTPTransport 使用 fasthttp 发送请求，是默认的 Transport
+type fastHTTPTransport struct {
+	client httpDoer
+	// cancelable 发送 ctx 可以取消的请求，为 nil 时这类请求只能等待读写超时结束
+	cancelable *cancelPool
+}
+
+// NewFastHTTPTransport 返回使用 c 发送请求的 Transport
+func NewFastHTTPTransport(c *fasthttp.Client) Transport {
+	return &fastHTTPTransport{client: c, cancelable: newCancelPool(c)}
+}
+
+// RoundTrip 实现 Transport，ctx 的截止时间作为连接的读写期限，ctx 被取消时关闭请求使用的连接
+func (t *fastHTTPTransport) RoundTrip(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	if ctx.Done() != nil && t.cancelable != nil {
+		return t.cancelable.do(ctx, req, resp)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		return t.client.DoDeadline(req, resp, deadline)
+	}
+	return t.client.Do(req, resp)
+}
+
+// cancelPool 缓存只有一个连接的 HostClient
+//
+// fasthttp 从共享的连接池中取连接，无法知道某个请求使用的是哪个连接。
+// 可以取消的请求独占一个 HostClient，ctx 取消时关闭它唯一的连接即可中断请求；
+// 请求正常结束后 HostClient 放回池中，连接仍然可以复用。
+// 每个主机同时使用的 HostClient 数量受 MaxConnsPerHost 限制，达到上限时最多等待 MaxConnWaitTimeout；
+// 空闲超过 MaxIdleConnDuration 的 HostClient 被丢弃。
+type cancelPool struct {
+	config *fasthttp.Client
+
+	mu    sync.Mutex
+	hosts map[string]*cancelHost
+}
+
+// cancelHost 是一个主机的 HostClient 和连接数限制
+type cancelHost struct {
+	sem  chan struct{} // 每个进行中的请求占用一个位置
+	idle []*cancelClient
+}
+
+// newCancelPool 返回按 c 的配置创建 HostClient 的 cancelPool
+func newCancelPool(c *fasthttp.Client) *cancelPool {
+	return &cancelPool{config: c, hosts: make(map[string]*cancelHost)}
+}
+
+// do 使用独占的 HostClient 发送请求，ctx 取消时关闭连接
+func (p *cancelPool) do(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	uri := req.URI()
+	isTLS := bytes.EqualFold(uri.Scheme(), []byte("https"))
+	key := hostAddr(string(uri.Host()), isTLS)
+	if isTLS {
+		key = "https://" + key
+	}
+	h := p.host(key)
+	if err := p.acquire(ctx, h); err != nil {
+		return err
+	}
+	defer func() { <-h.sem }()
+	cc := p.get(h, key, isTLS)
+
+	stop := make(chan struct{})
+	aborted := make(chan bool, 1)
+	go func() {
+		select {
+		case <-ctx.Done():
+			cc.abort()
+			aborted <- true
+		case <-stop:
+			aborted <- false
+		}
+	}()
+
+	var err error
+	if deadline, ok := ctx.Deadline(); ok {
+		err = cc.hc.DoDeadline(req, resp, deadline)
+	} else {
+		err = cc.hc.Do(req, resp)
+	}
+	close(stop)
+	if <-aborted {
+		// 连接已被关闭，丢弃这个 HostClient 以免下一个请求拿到关闭的连接
+		cc.hc.CloseIdleConnections()
+		return err
+	}
+	p.put(h, cc)
+	return err
+}
+
+// host 返回 key 对应的 cancelHost，不存在时创建
+func (p *cancelPool) host(key string) *cancelHost {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h, ok := p.hosts[key]
+	if !ok {
+		maxConns := p.config.MaxConnsPerHost
+		if maxConns <= 0 {
+			maxConns = fasthttp.DefaultMaxConnsPerHost
+		}
+		h = &cancelHost{sem: make(chan struct{}, maxConns)}
+		p.hosts[key] = h
+	}
+	return h
+}
+
+// acquire 占用 h 的一个连接位置，已满时最多等待 MaxConnWaitTimeout，与 fasthttp 一样超时后返回 fasthttp.ErrNoFreeConns
+func (p *cancelPool) acquire(ctx context.Context, h *cancelHost) error {
+	select {
+	case h.sem <- struct{}{}:
+		return nil
+	default:
+	}
+	if p.config.MaxConnWaitTimeout <= 0 {
+		return fasthttp.ErrNoFreeConns
+	}
+	timer := time.NewTimer(p.config.MaxConnWaitTimeout)
+	defer timer.Stop()
+	select {
+	case h.sem <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return fasthttp.ErrNoFreeConns
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// get 取出 h 中的空闲 HostClient，没有时新建
+func (p *cancelPool) get(h *cancelHost, key string, isTLS bool) *cancelClient {
+	p.mu.Lock()
+	p.prune(h)
+	if n := len(h.idle); n > 0 {
+		cc := h.idle[n-1]
+		h.idle[n-1] = nil
+		h.idle = h.idle[:n-1]
+		p.mu.Unlock()
+		return cc
+	}
+	p.mu.Unlock()
+
+	c := p.config
+	cc := &cancelClient{}
+	dial := c.Dial
+	if dial == nil {
+		dial = fasthttp.Dial
+		if c.DialDualStack {
+			dial = fasthttp.DialDualStack
+		}
+	}
+	cc.hc = &fasthttp.HostClient{
+		Addr:                     strings.TrimPrefix(key, "https://"),
+		IsTLS:                    isTLS,
+		Name:                     c.Name,
+		NoDefaultUserAgentHeader: c.NoDefaultUserAgentHeader,
+		Dial:                     cc.dial(dial),
+		TLSConfig:                c.TLSConfig,
+		MaxConns:                 1,
+		MaxIdleConnDuration:      c.MaxIdleConnDuration,
+		MaxConnDuration:          c.MaxConnDuration,
+		// 关闭连接后 fasthttp 不能在新连接上重发被取消的请求，重试由 Client 负责
+		MaxIdemponentCallAttempts:     1,
+		ReadBufferSize:                c.ReadBufferSize,
+		WriteBufferSize:               c.WriteBufferSize,
+		ReadTimeout:                   c.ReadTimeout,
+		WriteTimeout:                  c.WriteTimeout,
+		MaxResponseBodySize:           c.MaxResponseBodySize,
+		DisableHeaderNamesNormalizing: c.DisableHeaderNamesNormalizing,
+		DisablePathNormalizing:        c.DisablePathNormalizing,
+	}
+	return cc
+}
+
+// put 把 HostClient 放回 h
+func (p *cancelPool) put(h *cancelHost, cc *cancelClient) {
+	cc.lastUsed = time.Now()
+	p.mu.Lock()
+	h.idle = append(h.idle, cc)
+	p.prune(h)
+	p.mu.Unlock()
+}
+
+// prune 丢弃空闲超过 MaxIdleConnDuration 的 HostClient，调用时必须持有 p.mu
+func (p *cancelPool) prune(h *cancelHost) {
+	maxIdle := p.config.MaxIdleConnDuration
+	if maxIdle <= 0 {
+		maxIdle = fasthttp.DefaultMaxIdleConnDuration
+	}
+	// idle 按放回的时间排序，最早的在前面
+	n := 0
+	for n < len(h.idle) && time.Since(h.idle[n].lastUsed) > maxIdle {
+		h.idle[n].hc.CloseIdleConnections()
+		n++
+	}
+	if n > 0 {
+		h.idle = append(h.idle[:0], h.idle[n:]...)
+	}
+}
+
+// cancelClient 是只有一个连接的 HostClient，记录当前的连接以便在 ctx 取消时关闭
+type cancelClient struct {
+	hc       *fasthttp.HostClient
+	lastUsed time.Time // 最近一次放回池中的时间
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// dial 包装 dial，记录新建的连接
+func (c *cancelClient) dial(dial fasthttp.DialFunc) fasthttp.DialFunc {
+	return func(addr string) (net.Conn, error) {
+		conn, err := dial(addr)
+		if err == nil {
+			c.mu.Lock()
+			c.conn = conn
+			c.mu.Unlock()
+		}
+		return conn, err
+	}
+}
+
+// abort 关闭当前的连接，使进行中的读写立即返回错误
+func (c *cancelClient) abort() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// hostAddr 为 host 补上默认端口
+func hostAddr(host string, isTLS bool) string {
+	if i := strings.LastIndexByte(host, ':'); i > strings.LastIndexByte(host, ']') {
+		return host
+	}
+	if isTLS {
+		return host + ":443"
+	}
+	return host + ":80"
+}
+
+// newFastHTTPTransport 根据配置创建默认的 fasthttp Transport
+func newFastHTTPTransport(o *options) Transport {
+	return NewFastHTTPTransport(&fasthttp.Client{
+		Name:                          o.userAgent,
+		MaxConnsPerHost:               o.maxConnsPerHost,
+		MaxIdleConnDuration:           o.maxIdleConnDuration,
+		ReadTimeout:                   o.readTimeout,
+		WriteTimeout:                  o.writeTimeout,
+		MaxResponseBodySize:           o.maxResponseBodySize,
+		DisableHeaderNamesNormalizing: true,
+		MaxConnWaitTimeout:            o.maxConnWaitTimeout,
+		Dial:                          o.dial,
+		TLSConfig:                     o.tlsConfig,
+	})
+}
+
+// httpTransport 使用 net/http 发送请求
+type httpTransport struct {
+	client              *http.Client
+	maxResponseBodySize int
+}
+
+// NewHTTPTransport 返回使用 net/http 的 c 发送请求的 Transport，c 为 nil 时使用 http.DefaultClient
+//
+// 可以配合 http.RoundTripper 中间件、httptest.Server 或需要 HTTP/2 的代理使用。
+func NewHTTPTransport(c *http.Client) Transport {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	return &httpTransport{client: c}
+}
+
+// newHTTPTransport 根据配置创建 net/http Transport，支持 HTTP/2，代理默认读取环境变量
+func newHTTPTransport(o *options) Transport {
+	tr := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		ForceAttemptHTTP2:     true,
+		TLSClientConfig:       o.tlsConfig,
+		MaxConnsPerHost:       o.maxConnsPerHost,
+		MaxIdleConnsPerHost:   100,
+		IdleConnTimeout:       o.maxIdleConnDuration,
+		ResponseHeaderTimeout: o.readTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+	}
+	if o.proxyURL != "" {
+		u, err := url.Parse(o.proxyURL)
+		if err != nil || u.Host == "" {
+			// 不带协议的地址按 HTTP 代理处理，保留其中的用户名和密码
+			u, err = url.Parse("http://" + o.proxyURL)
+		}
+		if err != nil {
+			tr.Proxy = func(*http.Request) (*url.URL, error) {
+				return nil, fmt.Errorf("无效的代理地址: %w", err)
+			}
+		} else {
+			tr.Proxy = http.ProxyURL(u)
+		}
+	} else if o.dial != nil {
+		dial := o.dial
+		tr.Proxy = nil
+		tr.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dial(addr)
+		}
+	}
+	return &httpTransport{
+		client:              &http.Client{Transport: tr},
+		maxResponseBodySize: o.maxResponseBodySize,
+	}
+}
+
+// RoundTrip 实现 Transport
+func (t *httpTransport) RoundTrip(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	var body io.Reader
+	if b := req.Body(); len(b) > 0 {
+		body = bytes.NewReader(b)
+	}
+	hreq, err := http.NewRequestWithContext(ctx, string(req.Header.Method()), req.URI().String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.VisitAll(func(key, value []byte) {
+		switch http.CanonicalHeaderKey(string(key)) {
+		case "Host", "Content-Length":
+			// 由 net/http 根据 URL 和请求体设置
+		default:
+			hreq.Header.Add(string(key), string(value))
+		}
+	})
+
+	hresp, err := t.client.Do(hreq)
+	if err != nil {
+		return err
+	}
+	defer hresp.Body.Close()
+
+	var r io.Reader = hresp.Body
+	if t.maxResponseBodySize > 0 {
+		r = io.LimitReader(hresp.Body, int64(t.maxResponseBodySize)+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if t.maxResponseBodySize > 0 && len(data) > t.maxResponseBodySize {
+		return fmt.Errorf("响应体超过 %d 字节: %w", t.maxResponseBodySize, fasthttp.ErrBodyTooLarge)
+	}
+
+	resp.SetStatusCode(hresp.StatusCode)
+	for key, values := range hresp.Header {
+		switch key {
+		case "Content-Length", "Transfer-Encoding", "Connection":
+		default:
+			for _, v := range values {
+				resp.Header.Add(key, v)
+			}
+		}
+	}
+	resp.SetBody(data)
+	return nil
+}
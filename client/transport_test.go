@@ -0,0 +1,439 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kuekiko/NotionGO/errors"
+	"github.com/valyala/fasthttp"
+)
+
+// transports lists the backends every transport test runs against
+var transports = []struct {
+	name string
+	opts []Option
+}{
+	{"fasthttp", nil},
+	{"net/http", []Option{WithHTTPClient(nil)}},
+}
+
+// forEachTransport runs fn once per backend with a client pointing at handler
+func forEachTransport(t *testing.T, handler http.HandlerFunc, fn func(t *testing.T, client *Client), opts ...Option) {
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			ts := httptest.NewServer(handler)
+			defer ts.Close()
+			base := []Option{WithBaseURL(ts.URL), WithRateLimit(0, 0), WithRetryWaitTime(time.Millisecond, 10*time.Millisecond)}
+			base = append(base, opts...)
+			fn(t, NewClient("test-token", append(base, tr.opts...)...))
+		})
+	}
+}
+
+func TestTransportRequest(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		checks := map[string][2]string{
+			"method":         {r.Method, "POST"},
+			"path":           {r.URL.Path, "/search"},
+			"query":          {r.URL.RawQuery, "a=1"},
+			"authorization":  {r.Header.Get("Authorization"), "Bearer test-token"},
+			"notion-version": {r.Header.Get("Notion-Version"), APIVersion},
+			"content-type":   {r.Header.Get("Content-Type"), "application/json"},
+			"user-agent":     {r.Header.Get("User-Agent"), DefaultUserAgent},
+			"body":           {string(body), `{"query":"x"}`},
+		}
+		for name, c := range checks {
+			if c[0] != c[1] {
+				t.Errorf("Expected %s %q, got %q", name, c[1], c[0])
+			}
+		}
+		w.Header().Set("X-Notion-Request-Id", "req-1")
+		w.Write([]byte(`{"object":"list","results":[]}`))
+	}
+	forEachTransport(t, handler, func(t *testing.T, client *Client) {
+		var v struct{ Object string }
+		if err := client.Post(context.Background(), "search?a=1", map[string]string{"query": "x"}, &v); err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		if v.Object != "list" {
+			t.Errorf("Expected decoded response, got %+v", v)
+		}
+
+		resp, err := client.Do(context.Background(), "POST", "search?a=1", map[string]string{"query": "x"})
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		defer fasthttp.ReleaseResponse(resp)
+		if requestID(resp) != "req-1" {
+			t.Errorf("Expected response headers to be copied, got request id %q", requestID(resp))
+		}
+	})
+}
+
+func TestTransportRetry(t *testing.T) {
+	var attempts int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"object":"error","status":429,"code":"rate_limited","message":"slow down"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}
+	forEachTransport(t, handler, func(t *testing.T, client *Client) {
+		atomic.StoreInt32(&attempts, 0)
+		if err := client.Get(context.Background(), "users/me", nil, nil); err != nil {
+			t.Fatalf("Expected the retry to succeed, got %v", err)
+		}
+		if n := atomic.LoadInt32(&attempts); n != 2 {
+			t.Errorf("Expected 2 attempts, got %d", n)
+		}
+	})
+}
+
+func TestTransportConnectionReset(t *testing.T) {
+	var attempts int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Write([]byte(`{}`))
+	}
+	forEachTransport(t, handler, func(t *testing.T, client *Client) {
+		atomic.StoreInt32(&attempts, 0)
+		if err := client.Get(context.Background(), "users/me", nil, nil); err != nil {
+			t.Fatalf("Expected the closed connection to be retried, got %v", err)
+		}
+	})
+}
+
+func TestTransportAPIError(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Notion-Request-Id", "req-2")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"Could not find page."}`))
+	}
+	forEachTransport(t, handler, func(t *testing.T, client *Client) {
+		err := client.Get(context.Background(), "pages/p1", nil, nil)
+		if !errors.IsNotFound(err) {
+			t.Fatalf("Expected not found error, got %v", err)
+		}
+		apiErr := err.(*errors.Error)
+		if apiErr.Code != errors.ErrObjectNotFound || apiErr.Message != "Could not find page." || apiErr.RequestID != "req-2" {
+			t.Errorf("Unexpected error fields: %+v", apiErr)
+		}
+	})
+}
+
+func TestTransportTimeout(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}
+	forEachTransport(t, handler, func(t *testing.T, client *Client) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := client.Get(ctx, "users/me", nil, nil)
+		if !errors.IsTimeout(err) {
+			t.Fatalf("Expected timeout error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+			t.Errorf("Expected the request to be aborted, took %v", elapsed)
+		}
+	})
+}
+
+func TestTransportCancel(t *testing.T) {
+	closed := make(chan struct{}, 1)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/me" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			closed <- struct{}{}
+		case <-time.After(5 * time.Second):
+		}
+	}
+	forEachTransport(t, handler, func(t *testing.T, client *Client) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+		if err := client.Get(ctx, "pages/p1", nil, nil); !errors.IsCanceled(err) {
+			t.Fatalf("Expected canceled error, got %v", err)
+		}
+		select {
+		case <-closed:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Expected the server to see the connection closed")
+		}
+
+		// later cancelable requests must not pick up the closed connection
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		for i := 0; i < 2; i++ {
+			if err := client.Get(ctx, "users/me", nil, nil); err != nil {
+				t.Fatalf("Expected request after cancellation to succeed, got %v", err)
+			}
+		}
+	})
+}
+
+func TestTransportCancelNotResent(t *testing.T) {
+	var hits int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(300 * time.Millisecond):
+			w.Write([]byte(`{}`))
+		}
+	}
+	forEachTransport(t, handler, func(t *testing.T, client *Client) {
+		atomic.StoreInt32(&hits, 0)
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		if err := client.Get(ctx, "users/me", nil, nil); !errors.IsCanceled(err) {
+			t.Fatalf("Expected canceled error, got %v", err)
+		}
+		time.Sleep(400 * time.Millisecond)
+		if n := atomic.LoadInt32(&hits); n != 1 {
+			t.Errorf("Expected the canceled GET to reach the server once, got %d hits", n)
+		}
+	})
+}
+
+func TestTransportCancelMaxConns(t *testing.T) {
+	var active, peak int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		w.Write([]byte(`{}`))
+	}
+	ts := httptest.NewServer(http.HandlerFunc(handler))
+	defer ts.Close()
+	client := NewClient("test-token", WithBaseURL(ts.URL), WithRateLimit(0, 0), WithMaxConnsPerHost(2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 6)
+	for i := 0; i < 6; i++ {
+		go func() { done <- client.Get(ctx, "users/me", nil, nil) }()
+	}
+	for i := 0; i < 6; i++ {
+		if err := <-done; err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+	}
+	if p := atomic.LoadInt32(&peak); p > 2 {
+		t.Errorf("Expected at most 2 concurrent connections, got %d", p)
+	}
+}
+
+func TestCancelPoolIdleExpiry(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	pool := newCancelPool(&fasthttp.Client{MaxIdleConnDuration: 20 * time.Millisecond})
+	send := func() {
+		req, resp := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseRequest(req)
+		defer fasthttp.ReleaseResponse(resp)
+		req.SetRequestURI(ts.URL)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if err := pool.do(ctx, req, resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idle := func() []*cancelClient {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		var all []*cancelClient
+		for _, h := range pool.hosts {
+			all = append(all, h.idle...)
+		}
+		return all
+	}
+
+	send()
+	first := idle()
+	if len(first) != 1 {
+		t.Fatalf("Expected 1 idle client, got %d", len(first))
+	}
+	send()
+	if again := idle(); len(again) != 1 || again[0] != first[0] {
+		t.Fatal("Expected the idle client to be reused")
+	}
+	time.Sleep(50 * time.Millisecond)
+	send()
+	if last := idle(); len(last) != 1 || last[0] == first[0] {
+		t.Error("Expected the expired client to be dropped and replaced")
+	}
+}
+
+func TestTransportMaxResponseBodySize(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"` + strings.Repeat("x", 64) + `"}`))
+	}
+	forEachTransport(t, handler, func(t *testing.T, client *Client) {
+		if err := client.Get(context.Background(), "users/me", nil, nil); err == nil {
+			t.Error("Expected an error for an oversized response body")
+		}
+	}, WithMaxResponseBodySize(16), WithMaxRetries(0))
+}
+
+func TestHTTPTransportProxy(t *testing.T) {
+	var auth atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "api.notion.test" {
+			t.Errorf("Expected an absolute request for api.notion.test, got %s", r.URL)
+		}
+		auth.Store(r.Header.Get("Proxy-Authorization"))
+		w.Write([]byte(`{"object":"user","id":"via-proxy"}`))
+	}))
+	defer proxy.Close()
+
+	addr := strings.TrimPrefix(proxy.URL, "http://")
+	tests := []struct {
+		name  string
+		proxy string
+		auth  string
+	}{
+		{"url", proxy.URL, ""},
+		{"without scheme", "user:secret@" + addr, "Basic dXNlcjpzZWNyZXQ="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("test-token",
+				WithBaseURL("http://api.notion.test/v1"),
+				WithHTTPClient(nil),
+				WithProxy(tt.proxy),
+			)
+			var user struct{ ID string }
+			if err := client.Get(context.Background(), "users/me", nil, &user); err != nil {
+				t.Fatalf("Request through proxy failed: %v", err)
+			}
+			if user.ID != "via-proxy" {
+				t.Errorf("Expected response from the proxy, got %+v", user)
+			}
+			if got := auth.Load(); got != tt.auth {
+				t.Errorf("Expected Proxy-Authorization %q, got %q", tt.auth, got)
+			}
+		})
+	}
+}
+
+func TestHTTPSProxy(t *testing.T) {
+	proxy := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"object":"user","id":"via-proxy"}`))
+	}))
+	defer proxy.Close()
+	proxyURL := strings.Replace(proxy.URL, "https://", "https://user:secret@", 1)
+	roots := proxy.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	t.Run("fasthttp", func(t *testing.T) {
+		client := NewClient("test-token",
+			WithBaseURL("http://api.notion.test/v1"),
+			WithProxy(proxyURL),
+			WithMaxRetries(0),
+		)
+		err := client.Get(context.Background(), "users/me", nil, nil)
+		if err == nil || !strings.Contains(err.Error(), "WithHTTPClient") {
+			t.Fatalf("Expected an error pointing to WithHTTPClient, got %v", err)
+		}
+		if strings.Contains(err.Error(), "secret") {
+			t.Errorf("Expected the proxy password to be redacted, got %v", err)
+		}
+	})
+
+	t.Run("net/http", func(t *testing.T) {
+		client := NewClient("test-token",
+			WithBaseURL("http://api.notion.test/v1"),
+			WithHTTPClient(nil),
+			WithTLSConfig(&tls.Config{RootCAs: roots}),
+			WithProxy(proxyURL),
+		)
+		var user struct{ ID string }
+		if err := client.Get(context.Background(), "users/me", nil, &user); err != nil {
+			t.Fatalf("Request through the https proxy failed: %v", err)
+		}
+		if user.ID != "via-proxy" {
+			t.Errorf("Expected response from the proxy, got %+v", user)
+		}
+	})
+}
+
+func TestHTTPTransportHTTP2(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			t.Errorf("Expected HTTP/2, got %s", r.Proto)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	roots := ts.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	client := NewClient("test-token",
+		WithBaseURL(ts.URL),
+		WithHTTPClient(nil),
+		WithTLSConfig(&tls.Config{RootCAs: roots}),
+	)
+	if err := client.Get(context.Background(), "users/me", nil, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	// the httptest.Server client wrapped in a custom RoundTripper
+	var calls int32
+	hc := ts.Client()
+	next := hc.Transport
+	hc.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		return next.RoundTrip(r)
+	})
+	client := NewClient("test-token", WithBaseURL(ts.URL), WithHTTPClient(hc))
+	if err := client.Get(context.Background(), "users/me", nil, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("Expected the custom RoundTripper to be used, got %d calls", calls)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// model 表示一个数据库生成的代码
+type model struct {
+	name     string // 结构体名称
+	database *notion.Database
+	fields   []field
+	enums    []enum
+	skipped  []string // 无法映射的属性及原因
+}
+
+// field 表示结构体字段
+type field struct {
+	name       string
+	property   string
+	propertyID string
+	fromID     bool // 属性名无法转换为标识符，字段名由属性 ID 生成
+	typ        notion.PropertyType
+	goType     string
+	enum       *enum
+	filter     string // 过滤条件构建器函数名称
+	match      string // 枚举选项的过滤条件函数名称
+}
+
+// enum 表示选择和状态属性的选项常量
+type enum struct {
+	name     string
+	property string
+	values   []enumValue
+}
+
+// enumValue 表示一个选项常量
+type enumValue struct {
+	name  string
+	value string
+}
+
+// fieldTypes 是各属性类型对应的字段类型，选择类属性有选项时使用生成的枚举类型
+var fieldTypes = map[notion.PropertyType]string{
+	notion.PropertyTypeTitle:          "string",
+	notion.PropertyTypeRichText:       "string",
+	notion.PropertyTypeNumber:         "float64",
+	notion.PropertyTypeSelect:         "string",
+	notion.PropertyTypeMultiSelect:    "[]string",
+	notion.PropertyTypeStatus:         "string",
+	notion.PropertyTypeDate:           "time.Time",
+	notion.PropertyTypePeople:         "[]string",
+	notion.PropertyTypeFiles:          "[]notion.File",
+	notion.PropertyTypeCheckbox:       "bool",
+	notion.PropertyTypeURL:            "string",
+	notion.PropertyTypeEmail:          "string",
+	notion.PropertyTypePhoneNumber:    "string",
+	notion.PropertyTypeFormula:        "notion.PropertyValue",
+	notion.PropertyTypeRelation:       "[]string",
+	notion.PropertyTypeRollup:         "notion.PropertyValue",
+	notion.PropertyTypeCreatedTime:    "time.Time",
+	notion.PropertyTypeCreatedBy:      "string",
+	notion.PropertyTypeLastEditedTime: "time.Time",
+	notion.PropertyTypeLastEditedBy:   "string",
+	notion.PropertyTypeUniqueID:       "string",
+	notion.PropertyTypeVerification:   "notion.PropertyValue",
+}
+
+// filterBuilders 是各属性类型对应的过滤条件构建器和返回类型，%s 为属性名称
+var filterBuilders = map[notion.PropertyType][2]string{
+	notion.PropertyTypeTitle:          {"filter.Title(%s)", "*filter.TextCondition"},
+	notion.PropertyTypeRichText:       {"filter.RichText(%s)", "*filter.TextCondition"},
+	notion.PropertyTypeURL:            {"filter.URL(%s)", "*filter.TextCondition"},
+	notion.PropertyTypeEmail:          {"filter.Email(%s)", "*filter.TextCondition"},
+	notion.PropertyTypePhoneNumber:    {"filter.PhoneNumber(%s)", "*filter.TextCondition"},
+	notion.PropertyTypeNumber:         {"filter.Number(%s)", "*filter.NumberCondition"},
+	notion.PropertyTypeUniqueID:       {"filter.UniqueID(%s)", "*filter.NumberCondition"},
+	notion.PropertyTypeCheckbox:       {"filter.Checkbox(%s)", "*filter.CheckboxCondition"},
+	notion.PropertyTypeSelect:         {"filter.Select(%s)", "*filter.SelectCondition"},
+	notion.PropertyTypeStatus:         {"filter.Status(%s)", "*filter.SelectCondition"},
+	notion.PropertyTypeMultiSelect:    {"filter.MultiSelect(%s)", "*filter.ContainsCondition"},
+	notion.PropertyTypePeople:         {"filter.People(%s)", "*filter.ContainsCondition"},
+	notion.PropertyTypeCreatedBy:      {"filter.CreatedBy(%s)", "*filter.ContainsCondition"},
+	notion.PropertyTypeLastEditedBy:   {"filter.LastEditedBy(%s)", "*filter.ContainsCondition"},
+	notion.PropertyTypeRelation:       {"filter.Relation(%s)", "*filter.ContainsCondition"},
+	notion.PropertyTypeFiles:          {"filter.Files(%s)", "*filter.FilesCondition"},
+	notion.PropertyTypeDate:           {"filter.Date(%s)", "*filter.DateCondition"},
+	notion.PropertyTypeFormula:        {"filter.Formula(%s)", "*filter.FormulaCondition"},
+	notion.PropertyTypeRollup:         {"filter.Rollup(%s)", "*filter.RollupCondition"},
+	notion.PropertyTypeCreatedTime:    {"filter.CreatedTime()", "*filter.DateCondition"},
+	notion.PropertyTypeLastEditedTime: {"filter.LastEditedTime()", "*filter.DateCondition"},
+}
+
+// generate 为数据库生成 Go 代码，names 按数据库 ID 指定结构体名称
+//
+// 属性按名称排序，选项保持数据库中的顺序，相同的输入总是生成相同的代码。
+func generate(pkg string, databases []*notion.Database, names map[string]string) ([]byte, error) {
+	sorted := make([]*notion.Database, len(databases))
+	copy(sorted, databases)
+	structName := func(db *notion.Database) string {
+		if name := names[db.ID]; name != "" {
+			return name
+		}
+		return typeName(db)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return structName(sorted[i]) < structName(sorted[j]) })
+
+	// 先占用所有结构体名称和数据库 ID 常量，其他顶层标识符与之冲突时添加后缀
+	used := map[string]bool{}
+	for _, db := range sorted {
+		name := structName(db)
+		if used[name] {
+			return nil, fmt.Errorf("数据库 %s 的结构体名称 %s 重复，请使用 -name 指定", db.ID, name)
+		}
+		used[name] = true
+		used[name+"DatabaseID"] = true
+	}
+	models := make([]*model, len(sorted))
+	for i, db := range sorted {
+		models[i] = newModel(structName(db), db, used)
+	}
+
+	var body bytes.Buffer
+	imports := map[string]bool{}
+	for _, m := range models {
+		m.write(&body, imports)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by notiongen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		fmt.Fprintf(&out, "import (\n")
+		for _, path := range paths {
+			if path == "time" {
+				fmt.Fprintf(&out, "\t%q\n\n", path)
+			}
+		}
+		for _, path := range paths {
+			switch path {
+			case "time":
+			case "github.com/kuekiko/NotionGO":
+				fmt.Fprintf(&out, "\tnotion %q\n", path)
+			default:
+				fmt.Fprintf(&out, "\t%q\n", path)
+			}
+		}
+		fmt.Fprintf(&out, ")\n")
+	}
+	out.Write(body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化生成的代码失败: %w", err)
+	}
+	return src, nil
+}
+
+// newModel 根据数据库结构创建 model，used 记录文件中已使用的顶层标识符
+func newModel(name string, db *notion.Database, used map[string]bool) *model {
+	m := &model{name: name, database: db}
+	props := make([]string, 0, len(db.Properties))
+	for prop := range db.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+
+	fieldNames := map[string]bool{"ID": true}
+	for _, prop := range props {
+		p := db.Properties[prop]
+		if strings.ContainsAny(prop, ",\"`\\") {
+			m.skipped = append(m.skipped, fmt.Sprintf("%s: 属性名包含标签不支持的字符", prop))
+			continue
+		}
+		goType, ok := fieldTypes[p.Type]
+		if !ok {
+			m.skipped = append(m.skipped, fmt.Sprintf("%s: 不支持的属性类型 %s", prop, p.Type))
+			continue
+		}
+
+		f := field{property: prop, propertyID: p.ID, typ: p.Type, goType: goType}
+		f.name = identifier(prop)
+		switch {
+		case f.name != "":
+		case p.Type == notion.PropertyTypeTitle:
+			f.name = "Title"
+		default:
+			f.name, f.fromID = "Prop"+idIdentifier(p.ID), true
+		}
+		f.name = unique(f.name, "", fieldNames)
+		if options := optionsOf(p); len(options) > 0 {
+			e := &enum{name: unique(name+f.name, "", used), property: prop}
+			for i, option := range options {
+				valueName := fmt.Sprintf("%sOption%d", e.name, i+1)
+				if id := identifier(option); id != "" {
+					valueName = e.name + id
+				}
+				e.values = append(e.values, enumValue{name: unique(valueName, "", used), value: option})
+			}
+			f.enum = e
+			f.goType = e.name
+			if p.Type == notion.PropertyTypeMultiSelect {
+				f.goType = "[]" + e.name
+			}
+			m.enums = append(m.enums, *e)
+		}
+		m.fields = append(m.fields, f)
+	}
+
+	// 过滤条件函数在常量之后命名，避免与选项常量冲突
+	for i := range m.fields {
+		f := &m.fields[i]
+		if _, ok := filterBuilders[f.typ]; !ok {
+			continue
+		}
+		f.filter = unique(name+f.name+"Filter", "", used)
+		switch {
+		case f.enum != nil && f.typ == notion.PropertyTypeMultiSelect:
+			f.match = unique(name+f.name+"Contains", "", used)
+		case f.enum != nil:
+			f.match = unique(name+f.name+"Equals", "", used)
+		}
+	}
+	return m
+}
+
+// optionsOf 返回选择、多选和状态属性的选项名称
+func optionsOf(p notion.Property) []string {
+	var names []string
+	switch {
+	case p.Type == notion.PropertyTypeSelect && p.Select != nil:
+		for _, o := range p.Select.Options {
+			names = append(names, o.Name)
+		}
+	case p.Type == notion.PropertyTypeMultiSelect && p.MultiSelect != nil:
+		for _, o := range p.MultiSelect.Options {
+			names = append(names, o.Name)
+		}
+	case p.Type == notion.PropertyTypeStatus && p.Status != nil:
+		for _, o := range p.Status.Options {
+			names = append(names, o.Name)
+		}
+	}
+	return names
+}
+
+// write 写入结构体、枚举常量和过滤条件函数，imports 记录用到的包
+func (m *model) write(w *bytes.Buffer, imports map[string]bool) {
+	db := m.database
+	title := plainText(db.Title)
+
+	fmt.Fprintf(w, "\n// %sDatabaseID 是数据库「%s」的 ID\n", m.name, title)
+	fmt.Fprintf(w, "const %sDatabaseID = %q\n", m.name, db.ID)
+
+	fmt.Fprintf(w, "\n// %s 表示数据库「%s」中的一行\n", m.name, title)
+	for _, s := range m.skipped {
+		fmt.Fprintf(w, "//\n// 未生成字段的属性 %s\n", s)
+	}
+	fmt.Fprintf(w, "type %s struct {\n", m.name)
+	fmt.Fprintf(w, "\tID string `notion:\",id\"`\n")
+	for _, f := range m.fields {
+		tag := f.property + "," + string(f.typ)
+		if f.typ == notion.PropertyTypeDate {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(w, "\t%s %s `notion:%q`", f.name, f.goType, tag)
+		if f.fromID {
+			fmt.Fprintf(w, " // 属性「%s」，名称由属性 ID %s 生成", f.property, f.propertyID)
+		}
+		fmt.Fprintf(w, "\n")
+		switch {
+		case strings.Contains(f.goType, "time."):
+			imports["time"] = true
+		case strings.Contains(f.goType, "notion."):
+			imports["github.com/kuekiko/NotionGO"] = true
+		}
+	}
+	fmt.Fprintf(w, "}\n")
+
+	for _, e := range m.enums {
+		fmt.Fprintf(w, "\n// %s 是属性「%s」的选项\n", e.name, e.property)
+		fmt.Fprintf(w, "type %s string\n\n", e.name)
+		fmt.Fprintf(w, "// 属性「%s」的选项\n", e.property)
+		fmt.Fprintf(w, "const (\n")
+		for _, v := range e.values {
+			fmt.Fprintf(w, "\t%s %s = %q\n", v.name, e.name, v.value)
+		}
+		fmt.Fprintf(w, ")\n")
+	}
+
+	for _, f := range m.fields {
+		if f.filter == "" {
+			continue
+		}
+		builder := filterBuilders[f.typ]
+		imports["github.com/kuekiko/NotionGO/filter"] = true
+		call := builder[0]
+		if strings.Contains(call, "%s") {
+			call = fmt.Sprintf(call, strconv.Quote(f.property))
+		}
+		fmt.Fprintf(w, "\n// %s 返回属性「%s」的过滤条件构建器\n", f.filter, f.property)
+		fmt.Fprintf(w, "func %s() %s {\n\treturn %s\n}\n", f.filter, builder[1], call)
+
+		switch {
+		case f.match == "":
+		case f.typ == notion.PropertyTypeMultiSelect:
+			fmt.Fprintf(w, "\n// %s 匹配属性「%s」包含选项 v 的行\n", f.match, f.property)
+			fmt.Fprintf(w, "func %s(v %s) *filter.Condition {\n\treturn %s.Contains(string(v))\n}\n", f.match, f.enum.name, call)
+		default:
+			fmt.Fprintf(w, "\n// %s 匹配属性「%s」为选项 v 的行\n", f.match, f.property)
+			fmt.Fprintf(w, "func %s(v %s) *filter.Condition {\n\treturn %s.Equals(string(v))\n}\n", f.match, f.enum.name, call)
+		}
+	}
+}
+
+// typeName 根据数据库标题生成结构体名称，标题无法转换时使用 ID
+func typeName(db *notion.Database) string {
+	if name := identifier(plainText(db.Title)); name != "" {
+		return name
+	}
+	id := strings.ReplaceAll(db.ID, "-", "")
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return "Database" + id
+}
+
+// initialisms 是按 Go 习惯全部大写的缩写
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "JSON": true, "URL": true, "UUID": true,
+}
+
+// identifier 将名称中的 ASCII 字母和数字转换为导出的驼峰标识符，没有可用字符时返回空字符串
+func identifier(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	name := b.String()
+	if name != "" && unicode.IsDigit(rune(name[0])) {
+		name = "N" + name
+	}
+	return name
+}
+
+// idIdentifier 将属性 ID 转换为标识符的一部分
+//
+// 属性 ID 在属性改名后保持不变。ID 先按 URL 编码解码，ASCII 字母和数字原样保留（首字母大写），
+// 其他字节写作 _ 加两位十六进制数，例如 "c%3Ac" 转换为 "C_3Ac"。
+func idIdentifier(id string) string {
+	if s, err := url.PathUnescape(id); err == nil {
+		id = s
+	}
+	var b strings.Builder
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+			if b.Len() == 0 {
+				c = byte(unicode.ToUpper(rune(c)))
+			}
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "_%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// unique 返回未使用的名称，name 为空时使用 fallback，重复时添加数字后缀
+func unique(name, fallback string, used map[string]bool) string {
+	if name == "" {
+		name = fallback
+	}
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// plainText 返回富文本的纯文本
+func plainText(rt []notion.RichText) string {
+	var b strings.Builder
+	for _, t := range rt {
+		if t.PlainText != "" {
+			b.WriteString(t.PlainText)
+		} else if t.Text != nil {
+			b.WriteString(t.Text.Content)
+		}
+	}
+	return b.String()
+}
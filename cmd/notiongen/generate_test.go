@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+var update = flag.Bool("update", false, "更新 testdata 中的期望输出")
+
+const tasksID = "1f2e3d4c-5b6a-4789-8abc-def012345678"
+
+func TestGenerateGolden(t *testing.T) {
+	db, err := loadSchema("testdata/tasks.json")
+	if err != nil {
+		t.Fatalf("读取数据库结构失败: %v", err)
+	}
+	got, err := generate("models", []*notion.Database{db}, map[string]string{tasksID: "Task"})
+	if err != nil {
+		t.Fatalf("生成代码失败: %v", err)
+	}
+
+	golden := filepath.Join("testdata", "tasks.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("读取期望输出失败: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("生成的代码与 %s 不同，使用 -update 更新\n%s", golden, got)
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	tasks, err := loadSchema("testdata/tasks.json")
+	if err != nil {
+		t.Fatalf("读取数据库结构失败: %v", err)
+	}
+	projects := &notion.Database{
+		ID:    "2a2a2a2a-0000-4000-8000-000000000000",
+		Title: []notion.RichText{{PlainText: "项目"}},
+		Properties: map[string]notion.Property{
+			"名称": {ID: "title", Type: notion.PropertyTypeTitle},
+			"阶段": {ID: "st", Type: notion.PropertyTypeSelect, Select: &notion.SelectConfig{
+				Options: []notion.Option{{Name: "计划"}, {Name: "Done"}},
+			}},
+		},
+	}
+
+	first, err := generate("models", []*notion.Database{tasks, projects}, nil)
+	if err != nil {
+		t.Fatalf("生成代码失败: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		again, err := generate("models", []*notion.Database{projects, tasks}, nil)
+		if err != nil {
+			t.Fatalf("生成代码失败: %v", err)
+		}
+		if !bytes.Equal(first, again) {
+			t.Fatal("输入顺序不同时生成的代码不同")
+		}
+	}
+
+	src := string(first)
+	for _, want := range []string{
+		"type Database2a2a2a2a struct",
+		"Title  string                 `notion:\"名称,title\"`",
+		"PropSt Database2a2a2a2aPropSt `notion:\"阶段,select\"`",
+		"Database2a2a2a2aPropStOption1 Database2a2a2a2aPropSt = \"计划\"",
+		"Database2a2a2a2aPropStDone    Database2a2a2a2aPropSt = \"Done\"",
+		"type Tasks struct",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("生成的代码缺少 %q", want)
+		}
+	}
+}
+
+func TestGenerateDuplicateNames(t *testing.T) {
+	a := &notion.Database{ID: "a", Title: []notion.RichText{{PlainText: "Tasks"}}}
+	b := &notion.Database{ID: "b", Title: []notion.RichText{{PlainText: "tasks"}}}
+	if _, err := generate("models", []*notion.Database{a, b}, nil); err == nil {
+		t.Error("结构体名称重复时应返回错误")
+	}
+	if _, err := generate("models", []*notion.Database{a, b}, map[string]string{"b": "OldTasks"}); err != nil {
+		t.Errorf("使用 -name 指定名称后不应返回错误: %v", err)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"Due date":     "DueDate",
+		"homepage url": "HomepageURL",
+		"user_id":      "UserID",
+		"2024 目标":      "N2024",
+		"完成":           "",
+		"Q1-OKR":       "Q1OKR",
+	}
+	for in, want := range tests {
+		if got := identifier(in); got != want {
+			t.Errorf("identifier(%q) = %q，期望 %q", in, got, want)
+		}
+	}
+}
+
+func TestIDIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"st":     "St",
+		"c%3Ac":  "C_3Ac",
+		"%3AUPp": "_3AUPp",
+		"a%5Ew":  "A_5Ew",
+		"%E2%9C": "_E2_9C",
+	}
+	for in, want := range tests {
+		if got := idIdentifier(in); got != want {
+			t.Errorf("idIdentifier(%q) = %q，期望 %q", in, got, want)
+		}
+	}
+}
+
+func TestRunSavedSchema(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "tasks.go")
+	names := map[string]string{tasksID: "Task"}
+	if err := run("models", output, "", "", time.Second, names, []string{"testdata/tasks.json"}); err != nil {
+		t.Fatalf("run 失败: %v", err)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "tasks.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("run 写入的代码与期望输出不同")
+	}
+
+	if err := run("models", output, "", "", time.Second, nil, []string{tasksID}); err == nil {
+		t.Error("没有令牌时获取数据库应返回错误")
+	}
+}
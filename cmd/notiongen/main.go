@@ -0,0 +1,162 @@
+// notiongen 根据 Notion 数据库结构生成 Go 代码
+//
+// 每个数据库生成一个带 notion 标签的结构体（可以用于 notion.Marshal、notion.Unmarshal 和
+// table.Table）、选择和状态属性的选项常量，以及每个属性的过滤条件函数。
+//
+// 用法：
+//
+//	notiongen [参数] <数据库 ID 或结构文件>...
+//
+// 以 .json 结尾或存在的文件作为保存的数据库结构（DatabaseService.Get 的响应）读取，其他参数作为
+// 数据库 ID 通过 API 获取，需要设置 -token 或环境变量 NOTION_TOKEN。使用 -save 把获取的结构保存到目录中，
+// 之后即可离线生成：
+//
+//	notiongen -save schema -pkg models -o models/tasks.go 1a2b3c...
+//	notiongen -pkg models -o models/tasks.go schema/1a2b3c....json
+//
+// 字段名由属性名中的 ASCII 字母和数字生成，属性名没有可用字符时由属性 ID 生成，例如 PropC_3Ac，
+// 行尾注释注明原属性名。字段与属性的对应只由 notion:"属性名,类型" 标签决定，
+// 需要其他字段名时可以在自己的结构体中使用相同的标签。
+//
+// 用于 go:generate 时可以写作：
+//
+//	//go:generate go run github.com/kuekiko/NotionGO/cmd/notiongen -pkg models -o tasks.go schema/tasks.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// nameFlags 收集 -name 参数，格式为 数据库ID=结构体名称
+type nameFlags map[string]string
+
+func (n nameFlags) String() string {
+	return fmt.Sprint(map[string]string(n))
+}
+
+func (n nameFlags) Set(value string) error {
+	id, name, ok := strings.Cut(value, "=")
+	if !ok || id == "" || name == "" {
+		return fmt.Errorf("格式应为 数据库ID=结构体名称")
+	}
+	n[id] = name
+	return nil
+}
+
+func main() {
+	names := nameFlags{}
+	var (
+		pkg     = flag.String("pkg", "models", "生成代码的包名")
+		output  = flag.String("o", "", "输出文件，默认写到标准输出")
+		token   = flag.String("token", os.Getenv("NOTION_TOKEN"), "Notion API 令牌，默认读取环境变量 NOTION_TOKEN")
+		saveDir = flag.String("save", "", "把通过 API 获取的数据库结构保存到该目录，文件名为 <数据库 ID>.json")
+		timeout = flag.Duration("timeout", 30*time.Second, "获取数据库结构的超时时间")
+	)
+	flag.Var(names, "name", "指定数据库的结构体名称，格式为 数据库ID=结构体名称，可以重复")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "用法: notiongen [参数] <数据库 ID 或结构文件>...\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*pkg, *output, *token, *saveDir, *timeout, names, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "notiongen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run 读取或获取所有数据库结构并写入生成的代码
+func run(pkg, output, token, saveDir string, timeout time.Duration, names map[string]string, inputs []string) error {
+	var client *notion.Client
+	databases := make([]*notion.Database, 0, len(inputs))
+	for _, input := range inputs {
+		if isSchemaFile(input) {
+			db, err := loadSchema(input)
+			if err != nil {
+				return err
+			}
+			databases = append(databases, db)
+			continue
+		}
+
+		if token == "" {
+			return fmt.Errorf("获取数据库 %s 需要 -token 或环境变量 NOTION_TOKEN", input)
+		}
+		if client == nil {
+			client = notion.NewClient(token)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		db, err := client.Database.Get(ctx, input)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("获取数据库 %s 失败: %w", input, err)
+		}
+		if saveDir != "" {
+			if err := saveSchema(saveDir, db); err != nil {
+				return err
+			}
+		}
+		databases = append(databases, db)
+	}
+
+	src, err := generate(pkg, databases, names)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(output, src, 0644)
+}
+
+// isSchemaFile 判断参数是否是数据库结构文件
+func isSchemaFile(input string) bool {
+	if strings.HasSuffix(input, ".json") {
+		return true
+	}
+	info, err := os.Stat(input)
+	return err == nil && !info.IsDir()
+}
+
+// loadSchema 读取保存的数据库结构
+func loadSchema(path string) (*notion.Database, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	db := new(notion.Database)
+	if err := json.Unmarshal(data, db); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+	if db.ID == "" || len(db.Properties) == 0 {
+		return nil, fmt.Errorf("%s 不是数据库对象", path)
+	}
+	return db, nil
+}
+
+// saveSchema 把数据库结构保存到 dir/<数据库 ID>.json，属性按名称排序，便于比较差异
+func saveSchema(dir string, db *notion.Database) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, db.ID+".json")
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
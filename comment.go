@@ -1,5 +1,9 @@
 package notion
 
+import (
+	"context"
+)
+
 // CommentService 表示评论服务
 type CommentService struct {
 	client *Client
@@ -11,9 +15,9 @@ func NewCommentService(client *Client) *CommentService {
 }
 
 // Create 创建评论
-func (s *CommentService) Create(params *CreateCommentParams) (*Comment, error) {
+func (s *CommentService) Create(ctx context.Context, params *CreateCommentParams) (*Comment, error) {
 	comment := new(Comment)
-	err := s.client.post("comments", params, comment)
+	err := s.client.post(ctx, "comments", params, comment)
 	if err != nil {
 		return nil, err
 	}
@@ -21,15 +25,31 @@ func (s *CommentService) Create(params *CreateCommentParams) (*Comment, error) {
 }
 
 // List 列出评论
-func (s *CommentService) List(blockID string, params *ListParams) (*ListResponse, error) {
+func (s *CommentService) List(ctx context.Context, blockID string, params *ListParams) (*CommentList, error) {
 	path := "comments"
 	if blockID != "" {
 		path += "?block_id=" + blockID
 	}
-	response := new(ListResponse)
-	err := s.client.get(path, params, response)
+	response := new(CommentList)
+	err := s.client.get(ctx, path, params, response)
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
+
+// ListIter 返回遍历所有评论的迭代器
+func (s *CommentService) ListIter(ctx context.Context, blockID string, params *ListParams) *Iterator[*Comment] {
+	p := ListParams{}
+	if params != nil {
+		p = *params
+	}
+	return newIterator(ctx, p.StartCursor, func(ctx context.Context, cursor string) ([]*Comment, string, bool, error) {
+		p.StartCursor = cursor
+		list, err := s.List(ctx, blockID, &p)
+		if err != nil {
+			return nil, "", false, err
+		}
+		return list.Results, list.NextCursor, list.HasMore, nil
+	})
+}
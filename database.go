@@ -1,5 +1,9 @@
 package notion
 
+import (
+	"context"
+)
+
 // Database 表示数据库对象
 type Database struct {
 	Object         string              `json:"object"`           // 总是 "database"
@@ -40,9 +44,9 @@ type DatabaseCreateParams struct {
 }
 
 // Create 创建数据库
-func (s *DatabaseService) Create(params *DatabaseCreateParams) (*Database, error) {
+func (s *DatabaseService) Create(ctx context.Context, params *DatabaseCreateParams) (*Database, error) {
 	database := new(Database)
-	err := s.client.post("databases", params, database)
+	err := s.client.post(ctx, "databases", params, database)
 	if err != nil {
 		return nil, err
 	}
@@ -50,10 +54,10 @@ func (s *DatabaseService) Create(params *DatabaseCreateParams) (*Database, error
 }
 
 // Get 获取数据库
-func (s *DatabaseService) Get(databaseID string) (*Database, error) {
+func (s *DatabaseService) Get(ctx context.Context, databaseID string) (*Database, error) {
 	path := "databases/" + databaseID
 	database := new(Database)
-	err := s.client.get(path, nil, database)
+	err := s.client.get(ctx, path, nil, database)
 	if err != nil {
 		return nil, err
 	}
@@ -61,10 +65,10 @@ func (s *DatabaseService) Get(databaseID string) (*Database, error) {
 }
 
 // Update 更新数据库
-func (s *DatabaseService) Update(databaseID string, params *Database) (*Database, error) {
+func (s *DatabaseService) Update(ctx context.Context, databaseID string, params *Database) (*Database, error) {
 	path := "databases/" + databaseID
 	database := new(Database)
-	err := s.client.patch(path, params, database)
+	err := s.client.patch(ctx, path, params, database)
 	if err != nil {
 		return nil, err
 	}
@@ -72,24 +76,42 @@ func (s *DatabaseService) Update(databaseID string, params *Database) (*Database
 }
 
 // Delete 删除数据库
-func (s *DatabaseService) Delete(databaseID string) error {
+//
+// Deprecated: Notion API 没有删除数据库的端点，请使用 Update 设置 Archived 归档。
+func (s *DatabaseService) Delete(ctx context.Context, databaseID string) error {
 	path := "databases/" + databaseID
-	return s.client.delete(path)
+	return s.client.delete(ctx, path)
 }
 
 // List 列出数据库
-func (s *DatabaseService) List(params *ListParams) (*ListResponse, error) {
-	response := new(ListResponse)
-	err := s.client.get("databases", params, response)
+func (s *DatabaseService) List(ctx context.Context, params *ListParams) (*DatabaseList, error) {
+	response := new(DatabaseList)
+	err := s.client.get(ctx, "databases", params, response)
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
+// ListIter 返回遍历所有数据库的迭代器
+func (s *DatabaseService) ListIter(ctx context.Context, params *ListParams) *Iterator[*Database] {
+	p := ListParams{}
+	if params != nil {
+		p = *params
+	}
+	return newIterator(ctx, p.StartCursor, func(ctx context.Context, cursor string) ([]*Database, string, bool, error) {
+		p.StartCursor = cursor
+		list, err := s.List(ctx, &p)
+		if err != nil {
+			return nil, "", false, err
+		}
+		return list.Results, list.NextCursor, list.HasMore, nil
+	})
+}
+
 // DatabaseQueryParams 表示查询数据库的参数
 type DatabaseQueryParams struct {
-	Filter      interface{} `json:"filter,omitempty"`       // 过滤条件
+	Filter      interface{} `json:"filter,omitempty"`       // 过滤条件，通常使用 filter 包构建
 	Sorts       []Sort      `json:"sorts,omitempty"`        // 排序条件
 	StartCursor string      `json:"start_cursor,omitempty"` // 起始游标
 	PageSize    int         `json:"page_size,omitempty"`    // 页面大小
@@ -97,18 +119,51 @@ type DatabaseQueryParams struct {
 
 // Sort 表示排序条件
 type Sort struct {
-	Property  string `json:"property"`            // 属性名称
+	Property  string `json:"property,omitempty"`  // 属性名称
 	Direction string `json:"direction"`           // 排序方向："ascending" 或 "descending"
 	Timestamp string `json:"timestamp,omitempty"` // 时间戳字段："created_time" 或 "last_edited_time"
 }
 
 // Query 查询数据库
-func (s *DatabaseService) Query(databaseID string, params *DatabaseQueryParams) (*ListResponse, error) {
+func (s *DatabaseService) Query(ctx context.Context, databaseID string, params *DatabaseQueryParams) (*PageList, error) {
+	if params != nil {
+		if f, ok := params.Filter.(interface{ Validate() error }); ok {
+			if err := f.Validate(); err != nil {
+				return nil, err
+			}
+		}
+	}
 	path := "databases/" + databaseID + "/query"
-	response := new(ListResponse)
-	err := s.client.post(path, params, response)
+	response := new(PageList)
+	err := s.client.post(ctx, path, params, response)
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
+
+// QueryIter 返回遍历所有查询结果的迭代器
+func (s *DatabaseService) QueryIter(ctx context.Context, databaseID string, params *DatabaseQueryParams) *Iterator[*Page] {
+	p := DatabaseQueryParams{}
+	if params != nil {
+		p = *params
+	}
+	return newIterator(ctx, p.StartCursor, func(ctx context.Context, cursor string) ([]*Page, string, bool, error) {
+		p.StartCursor = cursor
+		list, err := s.Query(ctx, databaseID, &p)
+		if err != nil {
+			return nil, "", false, err
+		}
+		return list.Results, list.NextCursor, list.HasMore, nil
+	})
+}
+
+// QueryStream 对每个查询结果调用 fn，自动处理分页，fn 返回错误时停止
+func (s *DatabaseService) QueryStream(ctx context.Context, databaseID string, params *DatabaseQueryParams, fn func(*Page) error) error {
+	return s.QueryIter(ctx, databaseID, params).ForEach(fn)
+}
+
+// QueryAll 返回所有查询结果，maxItems 大于 0 时最多返回 maxItems 个结果
+func (s *DatabaseService) QueryAll(ctx context.Context, databaseID string, params *DatabaseQueryParams, maxItems int) ([]*Page, error) {
+	return s.QueryIter(ctx, databaseID, params).All(maxItems)
+}
@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"net/http"
 )
@@ -10,21 +12,33 @@ type ErrorCode string
 
 const (
 	// API 错误代码
-	ErrValidation          ErrorCode = "validation_error"
-	ErrInvalidRequest      ErrorCode = "invalid_request"
-	ErrInvalidJSON         ErrorCode = "invalid_json"
-	ErrMissingVersion      ErrorCode = "missing_version"
-	ErrUnauthorized        ErrorCode = "unauthorized"
-	ErrRateLimited         ErrorCode = "rate_limited"
-	ErrInternalServerError ErrorCode = "internal_server_error"
-	ErrConflict            ErrorCode = "conflict"
-	ErrServiceUnavailable  ErrorCode = "service_unavailable"
+	ErrInvalidJSON                   ErrorCode = "invalid_json"
+	ErrInvalidRequestURL             ErrorCode = "invalid_request_url"
+	ErrInvalidRequest                ErrorCode = "invalid_request"
+	ErrInvalidGrant                  ErrorCode = "invalid_grant"
+	ErrValidation                    ErrorCode = "validation_error"
+	ErrMissingVersion                ErrorCode = "missing_version"
+	ErrUnauthorized                  ErrorCode = "unauthorized"
+	ErrRestrictedResource            ErrorCode = "restricted_resource"
+	ErrObjectNotFound                ErrorCode = "object_not_found"
+	ErrConflict                      ErrorCode = "conflict_error"
+	ErrRateLimited                   ErrorCode = "rate_limited"
+	ErrInternalServerError           ErrorCode = "internal_server_error"
+	ErrBadGateway                    ErrorCode = "bad_gateway"
+	ErrServiceUnavailable            ErrorCode = "service_unavailable"
+	ErrDatabaseConnectionUnavailable ErrorCode = "database_connection_unavailable"
+	ErrGatewayTimeout                ErrorCode = "gateway_timeout"
 
 	// SDK 错误代码
-	ErrInvalidInput      ErrorCode = "invalid_input"
-	ErrSizeLimitExceeded ErrorCode = "size_limit_exceeded"
-	ErrRequestTimeout    ErrorCode = "request_timeout"
-	ErrContextCanceled   ErrorCode = "context_canceled"
+	ErrInvalidInput         ErrorCode = "invalid_input"
+	ErrSizeLimitExceeded    ErrorCode = "size_limit_exceeded"
+	ErrRequestTimeout       ErrorCode = "request_timeout"
+	ErrContextCanceled      ErrorCode = "context_canceled"
+	ErrPropertyNotFound     ErrorCode = "property_not_found"
+	ErrPropertyTypeMismatch ErrorCode = "property_type_mismatch"
+	ErrTokenNotFound        ErrorCode = "token_not_found"
+	ErrReadOnly             ErrorCode = "read_only"
+	ErrUnknown              ErrorCode = "unknown_error"
 )
 
 // Error 表示 Notion API 错误
@@ -32,11 +46,28 @@ type Error struct {
 	Code       ErrorCode `json:"code"`
 	Message    string    `json:"message"`
 	Status     int       `json:"status"`
-	RetryAfter int       `json:"retry_after,omitempty"`
+	RetryAfter int       `json:"retry_after,omitempty"` // 秒
+	RequestID  string    `json:"request_id,omitempty"`
+
+	// Err 是导致该错误的底层错误，可能为 nil
+	Err error `json:"-"`
 }
 
 func (e *Error) Error() string {
-	return fmt.Sprintf("notion: %s (status: %d, code: %s)", e.Message, e.Status, e.Code)
+	msg := fmt.Sprintf("notion: %s (status: %d, code: %s", e.Message, e.Status, e.Code)
+	if e.RequestID != "" {
+		msg += ", request_id: " + e.RequestID
+	}
+	msg += ")"
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap 返回底层错误，使 errors.Is 和 errors.As 可以穿透 *Error
+func (e *Error) Unwrap() error {
+	return e.Err
 }
 
 // NewError 创建一个新的错误
@@ -48,9 +79,62 @@ func NewError(code ErrorCode, message string, status int) *Error {
 	}
 }
 
+// Wrap 创建一个包装底层错误的 SDK 错误
+func Wrap(code ErrorCode, message string, err error) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// FromContext 将 ctx.Err() 返回的错误转换为 ErrRequestTimeout 或 ErrContextCanceled
+func FromContext(err error) error {
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return Wrap(ErrRequestTimeout, "请求超时", err)
+	}
+	return Wrap(ErrContextCanceled, "请求已取消", err)
+}
+
+// CodeForStatus 返回 HTTP 状态码对应的默认错误代码，用于响应体不是 Notion 错误对象的情况
+func CodeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrInvalidRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrRestrictedResource
+	case http.StatusNotFound:
+		return ErrObjectNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusInternalServerError:
+		return ErrInternalServerError
+	case http.StatusBadGateway:
+		return ErrBadGateway
+	case http.StatusServiceUnavailable:
+		return ErrServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return ErrGatewayTimeout
+	}
+	return ErrUnknown
+}
+
+// asError 在错误链中查找 *Error
+func asError(err error) (*Error, bool) {
+	var e *Error
+	if stderrors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
 // IsNotFound 检查是否是 404 错误
 func IsNotFound(err error) bool {
-	if e, ok := err.(*Error); ok {
+	if e, ok := asError(err); ok {
 		return e.Status == http.StatusNotFound
 	}
 	return false
@@ -58,15 +142,39 @@ func IsNotFound(err error) bool {
 
 // IsRateLimited 检查是否是速率限制错误
 func IsRateLimited(err error) bool {
-	if e, ok := err.(*Error); ok {
+	if e, ok := asError(err); ok {
 		return e.Code == ErrRateLimited
 	}
 	return false
 }
 
+// IsUnauthorized 检查是否是认证失败错误
+func IsUnauthorized(err error) bool {
+	if e, ok := asError(err); ok {
+		return e.Code == ErrUnauthorized
+	}
+	return false
+}
+
+// IsRestricted 检查是否是无权访问资源的错误
+func IsRestricted(err error) bool {
+	if e, ok := asError(err); ok {
+		return e.Code == ErrRestrictedResource
+	}
+	return false
+}
+
+// IsConflict 检查是否是数据冲突错误
+func IsConflict(err error) bool {
+	if e, ok := asError(err); ok {
+		return e.Code == ErrConflict
+	}
+	return false
+}
+
 // IsSizeLimitExceeded 检查是否超出大小限制
 func IsSizeLimitExceeded(err error) bool {
-	if e, ok := err.(*Error); ok {
+	if e, ok := asError(err); ok {
 		return e.Code == ErrSizeLimitExceeded
 	}
 	return false
@@ -74,12 +182,28 @@ func IsSizeLimitExceeded(err error) bool {
 
 // IsValidationError 检查是否是验证错误
 func IsValidationError(err error) bool {
-	if e, ok := err.(*Error); ok {
+	if e, ok := asError(err); ok {
 		return e.Code == ErrValidation
 	}
 	return false
 }
 
+// IsTimeout 检查是否是请求超时错误
+func IsTimeout(err error) bool {
+	if e, ok := asError(err); ok {
+		return e.Code == ErrRequestTimeout
+	}
+	return false
+}
+
+// IsCanceled 检查是否是上下文取消错误
+func IsCanceled(err error) bool {
+	if e, ok := asError(err); ok {
+		return e.Code == ErrContextCanceled
+	}
+	return false
+}
+
 // SizeLimits 定义各种大小限制
 var SizeLimits = struct {
 	MaxRichTextContent    int
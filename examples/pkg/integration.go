@@ -1,6 +1,8 @@
 package pkg
 
 import (
+	"context"
+
 	notion "github.com/kuekiko/NotionGO"
 	"github.com/kuekiko/NotionGO/examples/pkg/config"
 	"github.com/kuekiko/NotionGO/examples/pkg/logger"
@@ -12,11 +14,12 @@ func RunIntegrationExample(cfg *config.Config) {
 
 	// 创建客户端
 	client := notion.NewClient(cfg.APIKey)
+	ctx := context.Background()
 	logger.Debug("已创建 Notion 客户端")
 
 	// 1. 获取数据库
 	logger.Info("正在获取数据库...")
-	db, err := client.Database.Get(cfg.DatabaseID)
+	db, err := client.Database.Get(ctx, cfg.DatabaseID)
 	if err != nil {
 		logger.Error("获取数据库失败: %v", err)
 		return
@@ -33,7 +36,7 @@ func RunIntegrationExample(cfg *config.Config) {
 		PageSize: 10,
 	}
 
-	results, err := client.Database.Query(cfg.DatabaseID, queryParams)
+	results, err := client.Database.Query(ctx, cfg.DatabaseID, queryParams)
 	if err != nil {
 		logger.Error("查询数据库失败: %v", err)
 		return
@@ -48,25 +51,13 @@ func RunIntegrationExample(cfg *config.Config) {
 			Type:       "database_id",
 			DatabaseID: cfg.DatabaseID,
 		},
-		Properties: map[string]interface{}{
-			"Name": map[string]interface{}{
-				"title": []map[string]interface{}{
-					{
-						"text": map[string]interface{}{
-							"content": "测试页面",
-						},
-					},
-				},
-			},
-			"Status": map[string]interface{}{
-				"select": map[string]interface{}{
-					"name": "进行中",
-				},
-			},
+		Properties: notion.Properties{
+			"Name":   notion.NewTitleValue("测试页面"),
+			"Status": notion.NewSelectValue("进行中"),
 		},
 	}
 
-	page, err := client.Pages.Create(createParams)
+	page, err := client.Pages.Create(ctx, createParams)
 	if err != nil {
 		logger.Error("创建页面失败: %v", err)
 		return
@@ -78,25 +69,13 @@ func RunIntegrationExample(cfg *config.Config) {
 	// 4. 更新页面
 	logger.Info("正在更新页面...")
 	updatePage := &notion.Page{
-		Properties: map[string]interface{}{
-			"Name": map[string]interface{}{
-				"title": []map[string]interface{}{
-					{
-						"text": map[string]interface{}{
-							"content": "更新后的测试页面",
-						},
-					},
-				},
-			},
-			"Status": map[string]interface{}{
-				"select": map[string]interface{}{
-					"name": "已完成",
-				},
-			},
+		Properties: notion.Properties{
+			"Name":   notion.NewTitleValue("更新后的测试页面"),
+			"Status": notion.NewSelectValue("已完成"),
 		},
 	}
 
-	updatedPage, err := client.Pages.Update(page.ID, updatePage)
+	updatedPage, err := client.Pages.Update(ctx, page.ID, updatePage)
 	if err != nil {
 		logger.Error("更新页面失败: %v", err)
 		return
@@ -1,6 +1,8 @@
 package pkg
 
 import (
+	"context"
+
 	notion "github.com/kuekiko/NotionGO"
 	"github.com/kuekiko/NotionGO/examples/pkg/config"
 	"github.com/kuekiko/NotionGO/examples/pkg/logger"
@@ -12,6 +14,7 @@ func RunKnowledgeBase(cfg *config.Config) {
 
 	// 创建客户端
 	client := notion.NewClient(cfg.APIKey)
+	ctx := context.Background()
 	logger.Debug("已创建 Notion 客户端")
 
 	// 1. 创建文档页面
@@ -21,101 +24,23 @@ func RunKnowledgeBase(cfg *config.Config) {
 			Type:   "page_id",
 			PageID: cfg.PageID,
 		},
-		Properties: map[string]interface{}{
-			"title": []map[string]interface{}{
-				{
-					"text": map[string]interface{}{
-						"content": "Go 编程指南",
-					},
-				},
-			},
+		Properties: notion.Properties{
+			"title": notion.NewTitleValue("Go 编程指南"),
 		},
 		Children: []notion.Block{
-			{
-				Type: notion.TypeHeading1,
-				Heading1: &notion.HeadingBlock{
-					RichText: []notion.RichText{
-						{
-							Type: "text",
-							Text: &notion.Text{
-								Content: "Go 编程指南",
-							},
-						},
-					},
-				},
-			},
-			{
-				Type: notion.TypeParagraph,
-				Paragraph: &notion.ParagraphBlock{
-					RichText: []notion.RichText{
-						{
-							Type: "text",
-							Text: &notion.Text{
-								Content: "本指南介绍 Go 语言的基础知识和最佳实践。",
-							},
-						},
-					},
-				},
-			},
-			{
-				Type: notion.TypeHeading2,
-				Heading2: &notion.HeadingBlock{
-					RichText: []notion.RichText{
-						{
-							Type: "text",
-							Text: &notion.Text{
-								Content: "1. 安装",
-							},
-						},
-					},
-				},
-			},
-			{
-				Type: notion.TypeParagraph,
-				Paragraph: &notion.ParagraphBlock{
-					RichText: []notion.RichText{
-						{
-							Type: "text",
-							Text: &notion.Text{
-								Content: "访问 ",
-							},
-						},
-						{
-							Type: "text",
-							Text: &notion.Text{
-								Content: "Go 官网",
-								Link: &notion.Link{
-									URL: "https://golang.org",
-								},
-							},
-						},
-						{
-							Type: "text",
-							Text: &notion.Text{
-								Content: " 下载并安装 Go。",
-							},
-						},
-					},
-				},
-			},
-			{
-				Type: notion.TypeCode,
-				Code: &notion.CodeBlock{
-					Language: "bash",
-					RichText: []notion.RichText{
-						{
-							Type: "text",
-							Text: &notion.Text{
-								Content: "go version",
-							},
-						},
-					},
-				},
-			},
+			notion.Heading1("Go 编程指南"),
+			notion.Paragraph("本指南介绍 Go 语言的基础知识和最佳实践。"),
+			notion.Heading2("1. 安装"),
+			notion.Paragraph("访问 ",
+				notion.Plain("Go 官网").WithLink("https://golang.org"),
+				notion.Plain(" 下载并安装 Go。"),
+			),
+			notion.Code("bash", "go version"),
+			notion.Callout("💡", "安装完成后运行 ", notion.Plain("go env").Code(), notion.Plain(" 检查环境变量。")),
 		},
 	}
 
-	page, err := client.Pages.Create(createParams)
+	page, err := client.Pages.Create(ctx, createParams)
 	if err != nil {
 		logger.Error("创建页面失败: %v", err)
 		return
@@ -129,17 +54,10 @@ func RunKnowledgeBase(cfg *config.Config) {
 	commentParams := &notion.CreateCommentParams{
 		ParentID:   page.ID,
 		ParentType: "page_id",
-		RichText: []notion.RichText{
-			{
-				Type: "text",
-				Text: &notion.Text{
-					Content: "这是一个很好的入门指南！",
-				},
-			},
-		},
+		RichText:   notion.NewRichText().Text("这是一个很好的").Bold("入门指南").Text("！").Build(),
 	}
 
-	comment, err := client.Comments.Create(commentParams)
+	comment, err := client.Comments.Create(ctx, commentParams)
 	if err != nil {
 		logger.Error("添加评论失败: %v", err)
 		return
@@ -162,7 +80,7 @@ func RunKnowledgeBase(cfg *config.Config) {
 		PageSize: 10,
 	}
 
-	results, err := client.Search.Search(searchParams)
+	results, err := client.Search.Search(ctx, searchParams)
 	if err != nil {
 		logger.Error("搜索失败: %v", err)
 		return
@@ -171,16 +89,8 @@ func RunKnowledgeBase(cfg *config.Config) {
 	logger.Info("找到 %d 个相关文档", len(results.Results))
 	for _, result := range results.Results {
 		if page, ok := result.(*notion.Page); ok {
-			if title, ok := page.Properties["title"].(map[string]interface{}); ok {
-				if titleArr, ok := title["title"].([]interface{}); ok && len(titleArr) > 0 {
-					if titleObj, ok := titleArr[0].(map[string]interface{}); ok {
-						if textObj, ok := titleObj["text"].(map[string]interface{}); ok {
-							if content, ok := textObj["content"].(string); ok {
-								logger.Info("- %s", content)
-							}
-						}
-					}
-				}
+			if title, err := page.Properties.Title("title"); err == nil {
+				logger.Info("- %s", title)
 			}
 		}
 	}
@@ -1,9 +1,12 @@
 package pkg
 
 import (
+	"context"
+
 	notion "github.com/kuekiko/NotionGO"
 	"github.com/kuekiko/NotionGO/examples/pkg/config"
 	"github.com/kuekiko/NotionGO/examples/pkg/logger"
+	"github.com/kuekiko/NotionGO/filter"
 )
 
 // RunProjectManagement 运行项目管理示例
@@ -12,6 +15,7 @@ func RunProjectManagement(cfg *config.Config) {
 
 	// 创建客户端
 	client := notion.NewClient(cfg.APIKey)
+	ctx := context.Background()
 	logger.Debug("已创建 Notion 客户端")
 
 	// 1. 创建项目数据库
@@ -65,7 +69,7 @@ func RunProjectManagement(cfg *config.Config) {
 		},
 	}
 
-	db, err := client.Database.Create(createParams)
+	db, err := client.Database.Create(ctx, createParams)
 	if err != nil {
 		logger.Error("创建数据库失败: %v", err)
 		return
@@ -81,30 +85,14 @@ func RunProjectManagement(cfg *config.Config) {
 			Type:       "database_id",
 			DatabaseID: db.ID,
 		},
-		Properties: map[string]interface{}{
-			"名称": map[string]interface{}{
-				"title": []map[string]interface{}{
-					{
-						"text": map[string]interface{}{
-							"content": "示例项目",
-						},
-					},
-				},
-			},
-			"状态": map[string]interface{}{
-				"select": map[string]interface{}{
-					"name": "进行中",
-				},
-			},
-			"优先级": map[string]interface{}{
-				"select": map[string]interface{}{
-					"name": "高",
-				},
-			},
+		Properties: notion.Properties{
+			"名称":  notion.NewTitleValue("示例项目"),
+			"状态":  notion.NewSelectValue("进行中"),
+			"优先级": notion.NewSelectValue("高"),
 		},
 	}
 
-	page, err := client.Pages.Create(createPageParams)
+	page, err := client.Pages.Create(ctx, createPageParams)
 	if err != nil {
 		logger.Error("创建页面失败: %v", err)
 		return
@@ -116,12 +104,7 @@ func RunProjectManagement(cfg *config.Config) {
 	// 3. 查询进行中的项目
 	logger.Info("正在查询进行中的项目...")
 	queryParams := &notion.DatabaseQueryParams{
-		Filter: map[string]interface{}{
-			"property": "状态",
-			"select": map[string]interface{}{
-				"equals": "进行中",
-			},
-		},
+		Filter: filter.Select("状态").Equals("进行中"),
 		Sorts: []notion.Sort{
 			{
 				Property:  "优先级",
@@ -131,26 +114,16 @@ func RunProjectManagement(cfg *config.Config) {
 		PageSize: 10,
 	}
 
-	results, err := client.Database.Query(db.ID, queryParams)
+	results, err := client.Database.Query(ctx, db.ID, queryParams)
 	if err != nil {
 		logger.Error("查询数据库失败: %v", err)
 		return
 	}
 
 	logger.Info("找到 %d 个进行中的项目", len(results.Results))
-	for _, result := range results.Results {
-		if page, ok := result.(*notion.Page); ok {
-			if title, ok := page.Properties["名称"].(map[string]interface{}); ok {
-				if titleArr, ok := title["title"].([]interface{}); ok && len(titleArr) > 0 {
-					if titleObj, ok := titleArr[0].(map[string]interface{}); ok {
-						if textObj, ok := titleObj["text"].(map[string]interface{}); ok {
-							if content, ok := textObj["content"].(string); ok {
-								logger.Info("- %s", content)
-							}
-						}
-					}
-				}
-			}
+	for _, page := range results.Results {
+		if title, err := page.Properties.Title("名称"); err == nil {
+			logger.Info("- %s", title)
 		}
 	}
 
@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"context"
 	"encoding/json"
 
 	notion "github.com/kuekiko/NotionGO"
@@ -24,12 +25,13 @@ func RunDatabaseReader(cfg *config.Config) {
 
 	// 创建客户端
 	client := notion.NewClient(cfg.APIKey)
+	ctx := context.Background()
 	logger.Debug("已创建 Notion 客户端")
 
 	// 1. 获取数据库信息
 	logger.Info("正在获取数据库信息...")
 	logger.Debug("数据库 ID: %s", cfg.DatabaseID)
-	db, err := client.Database.Get(cfg.DatabaseID)
+	db, err := client.Database.Get(ctx, cfg.DatabaseID)
 	if err != nil {
 		logger.Error("获取数据库失败: %v", err)
 		return
@@ -67,7 +69,7 @@ func RunDatabaseReader(cfg *config.Config) {
 		Sorts:    nil, // 不使用排序
 	}
 
-	results, err := client.Database.Query(cfg.DatabaseID, queryParams)
+	results, err := client.Database.Query(ctx, cfg.DatabaseID, queryParams)
 	if err != nil {
 		logger.Error("查询数据库失败: %v", err)
 		return
@@ -75,20 +77,18 @@ func RunDatabaseReader(cfg *config.Config) {
 
 	// 打印所有记录
 	logger.Info("\n数据库记录 (共 %d 条):", len(results.Results))
-	for i, result := range results.Results {
-		if page, ok := result.(*notion.Page); ok {
-			logger.Info("\n记录 #%d:", i+1)
-			logger.Info("- ID: %s", page.ID)
-			logger.Info("- 创建时间: %s", page.CreatedTime)
-			logger.Info("- 最后编辑时间: %s", page.LastEditedTime)
-			logger.Info("- URL: %s", page.URL)
+	for i, page := range results.Results {
+		logger.Info("\n记录 #%d:", i+1)
+		logger.Info("- ID: %s", page.ID)
+		logger.Info("- 创建时间: %s", page.CreatedTime)
+		logger.Info("- 最后编辑时间: %s", page.LastEditedTime)
+		logger.Info("- URL: %s", page.URL)
 
-			// 打印所有属性
-			logger.Info("- 属性:")
-			for propName, propValue := range page.Properties {
-				propJSON, _ := json.MarshalIndent(propValue, "    ", "  ")
-				logger.Info("  %s: %s", propName, string(propJSON))
-			}
+		// 打印所有属性
+		logger.Info("- 属性:")
+		for propName, propValue := range page.Properties {
+			propJSON, _ := json.MarshalIndent(propValue, "    ", "  ")
+			logger.Info("  %s: %s", propName, string(propJSON))
 		}
 	}
 
@@ -107,11 +107,12 @@ func RunPageReader(cfg *config.Config) {
 
 	// 创建客户端
 	client := notion.NewClient(cfg.APIKey)
+	ctx := context.Background()
 	logger.Debug("已创建 Notion 客户端")
 
 	// 1. 获取页面信息
 	logger.Info("正在获取页面信息...")
-	page, err := client.Pages.Get(cfg.PageID)
+	page, err := client.Pages.Get(ctx, cfg.PageID)
 	if err != nil {
 		logger.Error("获取页面失败: %v", err)
 		return
@@ -134,7 +135,7 @@ func RunPageReader(cfg *config.Config) {
 
 	// 2. 获取页面内容（块）
 	logger.Info("\n正在获取页面内容...")
-	blocks, err := client.Blocks.ListChildren(page.ID, nil)
+	blocks, err := client.Blocks.ListChildren(ctx, page.ID, nil)
 	if err != nil {
 		logger.Error("获取页面内容失败: %v", err)
 		return
@@ -142,13 +143,7 @@ func RunPageReader(cfg *config.Config) {
 
 	// 打印所有块
 	logger.Info("\n页面内容 (共 %d 个块):", len(blocks.Results))
-	for i, result := range blocks.Results {
-		block, ok := result.(*notion.Block)
-		if !ok {
-			logger.Error("块类型转换失败")
-			continue
-		}
-
+	for i, block := range blocks.Results {
 		logger.Info("\n块 #%d:", i+1)
 		logger.Info("- 类型: %s", block.Type)
 
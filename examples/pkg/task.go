@@ -1,11 +1,13 @@
 package pkg
 
 import (
+	"context"
 	"time"
 
 	notion "github.com/kuekiko/NotionGO"
 	"github.com/kuekiko/NotionGO/examples/pkg/config"
 	"github.com/kuekiko/NotionGO/examples/pkg/logger"
+	"github.com/kuekiko/NotionGO/filter"
 )
 
 // RunTaskManagement 运行任务管理示例
@@ -14,6 +16,7 @@ func RunTaskManagement(cfg *config.Config) {
 
 	// 创建客户端
 	client := notion.NewClient(cfg.APIKey)
+	ctx := context.Background()
 	logger.Debug("已创建 Notion 客户端")
 
 	// 1. 创建任务数据库
@@ -72,7 +75,7 @@ func RunTaskManagement(cfg *config.Config) {
 		},
 	}
 
-	db, err := client.Database.Create(createParams)
+	db, err := client.Database.Create(ctx, createParams)
 	if err != nil {
 		logger.Error("创建数据库失败: %v", err)
 		return
@@ -89,38 +92,16 @@ func RunTaskManagement(cfg *config.Config) {
 			Type:       "database_id",
 			DatabaseID: db.ID,
 		},
-		Properties: map[string]interface{}{
-			"任务名称": map[string]interface{}{
-				"title": []map[string]interface{}{
-					{
-						"text": map[string]interface{}{
-							"content": "完成 SDK 文档",
-						},
-					},
-				},
-			},
-			"状态": map[string]interface{}{
-				"select": map[string]interface{}{
-					"name": "进行中",
-				},
-			},
-			"优先级": map[string]interface{}{
-				"select": map[string]interface{}{
-					"name": "高",
-				},
-			},
-			"截止日期": map[string]interface{}{
-				"date": map[string]interface{}{
-					"start": tomorrow,
-				},
-			},
-			"完成度": map[string]interface{}{
-				"number": 30,
-			},
+		Properties: notion.Properties{
+			"任务名称": notion.NewTitleValue("完成 SDK 文档"),
+			"状态":   notion.NewSelectValue("进行中"),
+			"优先级":  notion.NewSelectValue("高"),
+			"截止日期": notion.NewDateValue(tomorrow, ""),
+			"完成度":  notion.NewNumberValue(30),
 		},
 	}
 
-	page, err := client.Pages.Create(createPageParams)
+	page, err := client.Pages.Create(ctx, createPageParams)
 	if err != nil {
 		logger.Error("创建任务失败: %v", err)
 		return
@@ -132,22 +113,10 @@ func RunTaskManagement(cfg *config.Config) {
 	// 3. 查询待处理的任务
 	logger.Info("正在查询待处理的任务...")
 	queryParams := &notion.DatabaseQueryParams{
-		Filter: map[string]interface{}{
-			"and": []map[string]interface{}{
-				{
-					"property": "状态",
-					"select": map[string]interface{}{
-						"equals": "待处理",
-					},
-				},
-				{
-					"property": "截止日期",
-					"date": map[string]interface{}{
-						"on_or_before": tomorrow,
-					},
-				},
-			},
-		},
+		Filter: filter.And(
+			filter.Select("状态").Equals("待处理"),
+			filter.Date("截止日期").OnOrBefore(tomorrow),
+		),
 		Sorts: []notion.Sort{
 			{
 				Property:  "优先级",
@@ -161,26 +130,16 @@ func RunTaskManagement(cfg *config.Config) {
 		PageSize: 10,
 	}
 
-	results, err := client.Database.Query(db.ID, queryParams)
+	results, err := client.Database.Query(ctx, db.ID, queryParams)
 	if err != nil {
 		logger.Error("查询任务失败: %v", err)
 		return
 	}
 
 	logger.Info("找到 %d 个待处理的任务", len(results.Results))
-	for _, result := range results.Results {
-		if page, ok := result.(*notion.Page); ok {
-			if title, ok := page.Properties["任务名称"].(map[string]interface{}); ok {
-				if titleArr, ok := title["title"].([]interface{}); ok && len(titleArr) > 0 {
-					if titleObj, ok := titleArr[0].(map[string]interface{}); ok {
-						if textObj, ok := titleObj["text"].(map[string]interface{}); ok {
-							if content, ok := textObj["content"].(string); ok {
-								logger.Info("- %s", content)
-							}
-						}
-					}
-				}
-			}
+	for _, page := range results.Results {
+		if title, err := page.Properties.Title("任务名称"); err == nil {
+			logger.Info("- %s", title)
 		}
 	}
 
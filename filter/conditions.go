@@ -0,0 +1,257 @@
+package filter
+
+// TextCondition 构建文本类属性的过滤条件
+type TextCondition struct {
+	typ  string
+	wrap wrapFunc
+}
+
+// Title 返回标题属性的过滤条件构建器
+func Title(property string) *TextCondition {
+	return &TextCondition{typ: "title", wrap: propertyWrap(property)}
+}
+
+// RichText 返回文本属性的过滤条件构建器
+func RichText(property string) *TextCondition {
+	return &TextCondition{typ: "rich_text", wrap: propertyWrap(property)}
+}
+
+// URL 返回网址属性的过滤条件构建器
+func URL(property string) *TextCondition {
+	return &TextCondition{typ: "url", wrap: propertyWrap(property)}
+}
+
+// Email 返回邮箱属性的过滤条件构建器
+func Email(property string) *TextCondition {
+	return &TextCondition{typ: "email", wrap: propertyWrap(property)}
+}
+
+// PhoneNumber 返回电话属性的过滤条件构建器
+func PhoneNumber(property string) *TextCondition {
+	return &TextCondition{typ: "phone_number", wrap: propertyWrap(property)}
+}
+
+// Equals 匹配等于 value 的文本
+func (c *TextCondition) Equals(value string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"equals": value})
+}
+
+// DoesNotEqual 匹配不等于 value 的文本
+func (c *TextCondition) DoesNotEqual(value string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"does_not_equal": value})
+}
+
+// Contains 匹配包含 value 的文本
+func (c *TextCondition) Contains(value string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"contains": value})
+}
+
+// DoesNotContain 匹配不包含 value 的文本
+func (c *TextCondition) DoesNotContain(value string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"does_not_contain": value})
+}
+
+// StartsWith 匹配以 value 开头的文本
+func (c *TextCondition) StartsWith(value string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"starts_with": value})
+}
+
+// EndsWith 匹配以 value 结尾的文本
+func (c *TextCondition) EndsWith(value string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"ends_with": value})
+}
+
+// IsEmpty 匹配为空的文本
+func (c *TextCondition) IsEmpty() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"is_empty": true})
+}
+
+// IsNotEmpty 匹配不为空的文本
+func (c *TextCondition) IsNotEmpty() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"is_not_empty": true})
+}
+
+// NumberCondition 构建数字类属性的过滤条件
+type NumberCondition struct {
+	typ  string
+	wrap wrapFunc
+}
+
+// Number 返回数字属性的过滤条件构建器
+func Number(property string) *NumberCondition {
+	return &NumberCondition{typ: "number", wrap: propertyWrap(property)}
+}
+
+// UniqueID 返回唯一 ID 属性的过滤条件构建器，按 ID 的数字部分比较
+func UniqueID(property string) *NumberCondition {
+	return &NumberCondition{typ: "unique_id", wrap: propertyWrap(property)}
+}
+
+// Equals 匹配等于 value 的数字
+func (c *NumberCondition) Equals(value float64) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"equals": value})
+}
+
+// DoesNotEqual 匹配不等于 value 的数字
+func (c *NumberCondition) DoesNotEqual(value float64) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"does_not_equal": value})
+}
+
+// GreaterThan 匹配大于 value 的数字
+func (c *NumberCondition) GreaterThan(value float64) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"greater_than": value})
+}
+
+// LessThan 匹配小于 value 的数字
+func (c *NumberCondition) LessThan(value float64) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"less_than": value})
+}
+
+// GreaterThanOrEqualTo 匹配大于等于 value 的数字
+func (c *NumberCondition) GreaterThanOrEqualTo(value float64) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"greater_than_or_equal_to": value})
+}
+
+// LessThanOrEqualTo 匹配小于等于 value 的数字
+func (c *NumberCondition) LessThanOrEqualTo(value float64) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"less_than_or_equal_to": value})
+}
+
+// IsEmpty 匹配为空的数字，唯一 ID 属性不支持
+func (c *NumberCondition) IsEmpty() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"is_empty": true})
+}
+
+// IsNotEmpty 匹配不为空的数字，唯一 ID 属性不支持
+func (c *NumberCondition) IsNotEmpty() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"is_not_empty": true})
+}
+
+// CheckboxCondition 构建复选框属性的过滤条件
+type CheckboxCondition struct {
+	typ  string
+	wrap wrapFunc
+}
+
+// Checkbox 返回复选框属性的过滤条件构建器
+func Checkbox(property string) *CheckboxCondition {
+	return &CheckboxCondition{typ: "checkbox", wrap: propertyWrap(property)}
+}
+
+// Equals 匹配等于 value 的复选框
+func (c *CheckboxCondition) Equals(value bool) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"equals": value})
+}
+
+// DoesNotEqual 匹配不等于 value 的复选框
+func (c *CheckboxCondition) DoesNotEqual(value bool) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"does_not_equal": value})
+}
+
+// SelectCondition 构建单选类属性的过滤条件
+type SelectCondition struct {
+	typ  string
+	wrap wrapFunc
+}
+
+// Select 返回单选属性的过滤条件构建器
+func Select(property string) *SelectCondition {
+	return &SelectCondition{typ: "select", wrap: propertyWrap(property)}
+}
+
+// Status 返回状态属性的过滤条件构建器
+func Status(property string) *SelectCondition {
+	return &SelectCondition{typ: "status", wrap: propertyWrap(property)}
+}
+
+// Equals 匹配选项名称等于 value 的属性
+func (c *SelectCondition) Equals(value string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"equals": value})
+}
+
+// DoesNotEqual 匹配选项名称不等于 value 的属性
+func (c *SelectCondition) DoesNotEqual(value string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"does_not_equal": value})
+}
+
+// IsEmpty 匹配未选择的属性
+func (c *SelectCondition) IsEmpty() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"is_empty": true})
+}
+
+// IsNotEmpty 匹配已选择的属性
+func (c *SelectCondition) IsNotEmpty() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"is_not_empty": true})
+}
+
+// ContainsCondition 构建多值属性的过滤条件，用于多选、人员和关联
+type ContainsCondition struct {
+	typ  string
+	wrap wrapFunc
+}
+
+// MultiSelect 返回多选属性的过滤条件构建器，Contains 的参数为选项名称
+func MultiSelect(property string) *ContainsCondition {
+	return &ContainsCondition{typ: "multi_select", wrap: propertyWrap(property)}
+}
+
+// People 返回人员属性的过滤条件构建器，Contains 的参数为用户 ID
+func People(property string) *ContainsCondition {
+	return &ContainsCondition{typ: "people", wrap: propertyWrap(property)}
+}
+
+// CreatedBy 返回创建者属性的过滤条件构建器，Contains 的参数为用户 ID
+func CreatedBy(property string) *ContainsCondition {
+	return &ContainsCondition{typ: "created_by", wrap: propertyWrap(property)}
+}
+
+// LastEditedBy 返回最后编辑者属性的过滤条件构建器，Contains 的参数为用户 ID
+func LastEditedBy(property string) *ContainsCondition {
+	return &ContainsCondition{typ: "last_edited_by", wrap: propertyWrap(property)}
+}
+
+// Relation 返回关联属性的过滤条件构建器，Contains 的参数为页面 ID
+func Relation(property string) *ContainsCondition {
+	return &ContainsCondition{typ: "relation", wrap: propertyWrap(property)}
+}
+
+// Contains 匹配包含 value 的属性
+func (c *ContainsCondition) Contains(value string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"contains": value})
+}
+
+// DoesNotContain 匹配不包含 value 的属性
+func (c *ContainsCondition) DoesNotContain(value string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"does_not_contain": value})
+}
+
+// IsEmpty 匹配为空的属性
+func (c *ContainsCondition) IsEmpty() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"is_empty": true})
+}
+
+// IsNotEmpty 匹配不为空的属性
+func (c *ContainsCondition) IsNotEmpty() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"is_not_empty": true})
+}
+
+// FilesCondition 构建文件属性的过滤条件
+type FilesCondition struct {
+	typ  string
+	wrap wrapFunc
+}
+
+// Files 返回文件属性的过滤条件构建器
+func Files(property string) *FilesCondition {
+	return &FilesCondition{typ: "files", wrap: propertyWrap(property)}
+}
+
+// IsEmpty 匹配没有文件的属性
+func (c *FilesCondition) IsEmpty() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"is_empty": true})
+}
+
+// IsNotEmpty 匹配有文件的属性
+func (c *FilesCondition) IsNotEmpty() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"is_not_empty": true})
+}
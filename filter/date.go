@@ -0,0 +1,101 @@
+package filter
+
+import "time"
+
+// DateCondition 构建日期类属性和时间戳的过滤条件
+//
+// 日期参数为 ISO 8601 格式，例如 "2024-05-10" 或 "2024-05-10T12:00:00+08:00"。
+type DateCondition struct {
+	typ  string
+	wrap wrapFunc
+}
+
+// Date 返回日期属性的过滤条件构建器
+func Date(property string) *DateCondition {
+	return &DateCondition{typ: "date", wrap: propertyWrap(property)}
+}
+
+// CreatedTime 返回按页面创建时间过滤的构建器
+func CreatedTime() *DateCondition {
+	return &DateCondition{typ: "created_time", wrap: timestampWrap("created_time")}
+}
+
+// LastEditedTime 返回按页面最后编辑时间过滤的构建器
+func LastEditedTime() *DateCondition {
+	return &DateCondition{typ: "last_edited_time", wrap: timestampWrap("last_edited_time")}
+}
+
+// FormatDate 将 t 格式化为过滤条件使用的 ISO 8601 字符串
+func FormatDate(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+// Equals 匹配等于 date 的日期
+func (c *DateCondition) Equals(date string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"equals": date})
+}
+
+// Before 匹配早于 date 的日期
+func (c *DateCondition) Before(date string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"before": date})
+}
+
+// After 匹配晚于 date 的日期
+func (c *DateCondition) After(date string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"after": date})
+}
+
+// OnOrBefore 匹配不晚于 date 的日期
+func (c *DateCondition) OnOrBefore(date string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"on_or_before": date})
+}
+
+// OnOrAfter 匹配不早于 date 的日期
+func (c *DateCondition) OnOrAfter(date string) *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"on_or_after": date})
+}
+
+// IsEmpty 匹配为空的日期
+func (c *DateCondition) IsEmpty() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"is_empty": true})
+}
+
+// IsNotEmpty 匹配不为空的日期
+func (c *DateCondition) IsNotEmpty() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"is_not_empty": true})
+}
+
+// PastWeek 匹配过去一周内的日期
+func (c *DateCondition) PastWeek() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"past_week": emptyObject{}})
+}
+
+// PastMonth 匹配过去一个月内的日期
+func (c *DateCondition) PastMonth() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"past_month": emptyObject{}})
+}
+
+// PastYear 匹配过去一年内的日期
+func (c *DateCondition) PastYear() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"past_year": emptyObject{}})
+}
+
+// ThisWeek 匹配本周内的日期
+func (c *DateCondition) ThisWeek() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"this_week": emptyObject{}})
+}
+
+// NextWeek 匹配未来一周内的日期
+func (c *DateCondition) NextWeek() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"next_week": emptyObject{}})
+}
+
+// NextMonth 匹配未来一个月内的日期
+func (c *DateCondition) NextMonth() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"next_month": emptyObject{}})
+}
+
+// NextYear 匹配未来一年内的日期
+func (c *DateCondition) NextYear() *Condition {
+	return c.wrap(c.typ, map[string]interface{}{"next_year": emptyObject{}})
+}
@@ -0,0 +1,155 @@
+// Package filter 提供构建数据库查询过滤条件的类型安全 DSL
+//
+// 用法：
+//
+//	f := filter.And(
+//		filter.Select("状态").Equals("待处理"),
+//		filter.Date("截止日期").OnOrBefore("2024-12-31"),
+//		filter.Or(
+//			filter.Number("完成度").LessThan(0.5),
+//			filter.Checkbox("紧急").Equals(true),
+//		),
+//	)
+//	results, err := client.Database.Query(ctx, databaseID, &notion.DatabaseQueryParams{Filter: f})
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// MaxNestingDepth 是 Notion 允许的复合过滤条件最大嵌套层数
+//
+// 顶层的 and/or 中最多还可以再嵌套两层 and/or。
+const MaxNestingDepth = 2
+
+// Filter 表示过滤条件，序列化为 Notion 期望的 JSON
+type Filter interface {
+	json.Marshaler
+
+	// Validate 检查过滤条件是否合法
+	Validate() error
+
+	// depth 返回复合条件的层数，属性条件为 0
+	depth() int
+}
+
+// Condition 表示单个属性或时间戳的过滤条件
+type Condition struct {
+	property  string
+	timestamp string
+	body      map[string]interface{}
+}
+
+// MarshalJSON 实现 json.Marshaler
+func (c *Condition) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(c.body)+1)
+	for k, v := range c.body {
+		m[k] = v
+	}
+	if c.timestamp != "" {
+		m["timestamp"] = c.timestamp
+	} else {
+		m["property"] = c.property
+	}
+	return json.Marshal(m)
+}
+
+// Validate 实现 Filter
+func (c *Condition) Validate() error {
+	if c.timestamp == "" && c.property == "" {
+		return errors.NewError(errors.ErrInvalidInput, "过滤条件缺少属性名称", 0)
+	}
+	return nil
+}
+
+func (c *Condition) depth() int {
+	return 0
+}
+
+// Compound 表示 and/or 复合过滤条件
+type Compound struct {
+	op      string
+	filters []Filter
+}
+
+// And 返回所有条件都满足的复合条件
+func And(filters ...Filter) *Compound {
+	return &Compound{op: "and", filters: filters}
+}
+
+// Or 返回任一条件满足的复合条件
+func Or(filters ...Filter) *Compound {
+	return &Compound{op: "or", filters: filters}
+}
+
+// MarshalJSON 实现 json.Marshaler
+func (c *Compound) MarshalJSON() ([]byte, error) {
+	filters := c.filters
+	if filters == nil {
+		filters = []Filter{}
+	}
+	return json.Marshal(map[string]interface{}{c.op: filters})
+}
+
+// Validate 实现 Filter，检查嵌套层数和每个子条件
+func (c *Compound) Validate() error {
+	if len(c.filters) == 0 {
+		return errors.NewError(errors.ErrInvalidInput, fmt.Sprintf("%s 过滤条件不能为空", c.op), 0)
+	}
+	if nesting := c.depth() - 1; nesting > MaxNestingDepth {
+		return errors.NewError(errors.ErrInvalidInput,
+			fmt.Sprintf("复合过滤条件嵌套了 %d 层，超过 Notion 允许的 %d 层", nesting, MaxNestingDepth), 0)
+	}
+	for _, f := range c.filters {
+		if f == nil {
+			return errors.NewError(errors.ErrInvalidInput, fmt.Sprintf("%s 过滤条件包含 nil", c.op), 0)
+		}
+		if err := f.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Compound) depth() int {
+	max := 0
+	for _, f := range c.filters {
+		if f == nil {
+			continue
+		}
+		if d := f.depth(); d > max {
+			max = d
+		}
+	}
+	return max + 1
+}
+
+// wrapFunc 将条件类型和条件内容包装为完整的过滤条件
+type wrapFunc func(typ string, cond interface{}) *Condition
+
+// propertyWrap 返回直接作用于属性的包装函数
+func propertyWrap(property string) wrapFunc {
+	return func(typ string, cond interface{}) *Condition {
+		return &Condition{property: property, body: map[string]interface{}{typ: cond}}
+	}
+}
+
+// nestedWrap 返回将条件嵌套在 outer 之下的包装函数，用于公式和汇总
+func nestedWrap(wrap wrapFunc, outer string) wrapFunc {
+	return func(typ string, cond interface{}) *Condition {
+		return wrap(outer, map[string]interface{}{typ: cond})
+	}
+}
+
+// timestampWrap 返回作用于时间戳的包装函数
+func timestampWrap(timestamp string) wrapFunc {
+	return func(typ string, cond interface{}) *Condition {
+		return &Condition{timestamp: timestamp, body: map[string]interface{}{typ: cond}}
+	}
+}
+
+// emptyObject 序列化为 {}
+type emptyObject struct{}
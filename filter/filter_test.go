@@ -0,0 +1,117 @@
+package filter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// assertJSON 比较 f 序列化后的 JSON 与 want 是否语义相同
+func assertJSON(t *testing.T, f Filter, want string) {
+	t.Helper()
+	got, err := json.Marshal(f)
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("解码结果失败: %v", err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("解码期望值失败: %v", err)
+	}
+	gotJSON, _ := json.Marshal(gotValue)
+	wantJSON, _ := json.Marshal(wantValue)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("JSON = %s, 期望 %s", gotJSON, wantJSON)
+	}
+}
+
+func TestConditionJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{"title", Title("Name").Contains("任务"), `{"property":"Name","title":{"contains":"任务"}}`},
+		{"rich_text", RichText("描述").IsEmpty(), `{"property":"描述","rich_text":{"is_empty":true}}`},
+		{"url", URL("链接").StartsWith("https://"), `{"property":"链接","url":{"starts_with":"https://"}}`},
+		{"number", Number("完成度").GreaterThanOrEqualTo(0.5), `{"property":"完成度","number":{"greater_than_or_equal_to":0.5}}`},
+		{"checkbox", Checkbox("完成").Equals(false), `{"property":"完成","checkbox":{"equals":false}}`},
+		{"select", Select("状态").Equals("进行中"), `{"property":"状态","select":{"equals":"进行中"}}`},
+		{"status", Status("状态").DoesNotEqual("完成"), `{"property":"状态","status":{"does_not_equal":"完成"}}`},
+		{"multi_select", MultiSelect("标签").Contains("go"), `{"property":"标签","multi_select":{"contains":"go"}}`},
+		{"people", People("负责人").Contains("user-1"), `{"property":"负责人","people":{"contains":"user-1"}}`},
+		{"files", Files("附件").IsNotEmpty(), `{"property":"附件","files":{"is_not_empty":true}}`},
+		{"relation", Relation("项目").DoesNotContain("page-1"), `{"property":"项目","relation":{"does_not_contain":"page-1"}}`},
+		{"unique_id", UniqueID("编号").LessThan(100), `{"property":"编号","unique_id":{"less_than":100}}`},
+		{"date", Date("截止日期").OnOrBefore("2024-12-31"), `{"property":"截止日期","date":{"on_or_before":"2024-12-31"}}`},
+		{"date_relative", Date("截止日期").NextMonth(), `{"property":"截止日期","date":{"next_month":{}}}`},
+		{"created_time", CreatedTime().PastWeek(), `{"timestamp":"created_time","created_time":{"past_week":{}}}`},
+		{"last_edited_time", LastEditedTime().After("2024-01-01"), `{"timestamp":"last_edited_time","last_edited_time":{"after":"2024-01-01"}}`},
+		{"formula", Formula("剩余").Number().LessThan(3), `{"property":"剩余","formula":{"number":{"less_than":3}}}`},
+		{"formula_string", Formula("摘要").Text().Contains("a"), `{"property":"摘要","formula":{"string":{"contains":"a"}}}`},
+		{"rollup_any", Rollup("子任务").Any().RichText().Contains("bug"), `{"property":"子任务","rollup":{"any":{"rich_text":{"contains":"bug"}}}}`},
+		{"rollup_number", Rollup("总数").Number().GreaterThan(10), `{"property":"总数","rollup":{"number":{"greater_than":10}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertJSON(t, tt.filter, tt.want)
+			if err := tt.filter.Validate(); err != nil {
+				t.Errorf("Validate() = %v", err)
+			}
+		})
+	}
+}
+
+func TestCompoundJSON(t *testing.T) {
+	f := And(
+		Select("状态").Equals("待处理"),
+		Or(
+			Number("完成度").LessThan(0.5),
+			Checkbox("紧急").Equals(true),
+		),
+	)
+	assertJSON(t, f, `{"and":[
+		{"property":"状态","select":{"equals":"待处理"}},
+		{"or":[
+			{"property":"完成度","number":{"less_than":0.5}},
+			{"property":"紧急","checkbox":{"equals":true}}
+		]}
+	]}`)
+	if err := f.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestCompoundValidate(t *testing.T) {
+	leaf := Checkbox("完成").Equals(true)
+
+	if err := And(Or(And(leaf))).Validate(); err != nil {
+		t.Errorf("嵌套两层应该合法: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+	}{
+		{"too_deep", And(Or(And(Or(leaf))))},
+		{"empty", And()},
+		{"nested_empty", And(leaf, Or())},
+		{"nil", Or(leaf, nil)},
+		{"missing_property", And(Title("").Contains("a"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if err == nil {
+				t.Fatal("期望返回错误")
+			}
+			if e, ok := err.(*errors.Error); !ok || e.Code != errors.ErrInvalidInput {
+				t.Errorf("错误 = %v, 期望 %s", err, errors.ErrInvalidInput)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package filter
+
+// FormulaCondition 构建公式属性的过滤条件，按公式结果的类型选择比较方式
+type FormulaCondition struct {
+	wrap wrapFunc
+}
+
+// Formula 返回公式属性的过滤条件构建器
+func Formula(property string) *FormulaCondition {
+	return &FormulaCondition{wrap: nestedWrap(propertyWrap(property), "formula")}
+}
+
+// Text 按文本结果过滤，对应 Notion 的 string 公式条件
+func (c *FormulaCondition) Text() *TextCondition {
+	return &TextCondition{typ: "string", wrap: c.wrap}
+}
+
+// Number 按数字结果过滤
+func (c *FormulaCondition) Number() *NumberCondition {
+	return &NumberCondition{typ: "number", wrap: c.wrap}
+}
+
+// Checkbox 按布尔结果过滤
+func (c *FormulaCondition) Checkbox() *CheckboxCondition {
+	return &CheckboxCondition{typ: "checkbox", wrap: c.wrap}
+}
+
+// Date 按日期结果过滤
+func (c *FormulaCondition) Date() *DateCondition {
+	return &DateCondition{typ: "date", wrap: c.wrap}
+}
+
+// RollupCondition 构建汇总属性的过滤条件
+type RollupCondition struct {
+	wrap wrapFunc
+}
+
+// Rollup 返回汇总属性的过滤条件构建器
+//
+// 汇总结果为数组时使用 Any、Every 或 None 对每个元素应用条件，
+// 结果为数字或日期时使用 Number 或 Date。
+func Rollup(property string) *RollupCondition {
+	return &RollupCondition{wrap: nestedWrap(propertyWrap(property), "rollup")}
+}
+
+// Any 匹配至少一个元素满足条件的汇总
+func (c *RollupCondition) Any() *RollupItemCondition {
+	return &RollupItemCondition{wrap: nestedWrap(c.wrap, "any")}
+}
+
+// Every 匹配所有元素都满足条件的汇总
+func (c *RollupCondition) Every() *RollupItemCondition {
+	return &RollupItemCondition{wrap: nestedWrap(c.wrap, "every")}
+}
+
+// None 匹配没有元素满足条件的汇总
+func (c *RollupCondition) None() *RollupItemCondition {
+	return &RollupItemCondition{wrap: nestedWrap(c.wrap, "none")}
+}
+
+// Number 按数字结果过滤
+func (c *RollupCondition) Number() *NumberCondition {
+	return &NumberCondition{typ: "number", wrap: c.wrap}
+}
+
+// Date 按日期结果过滤
+func (c *RollupCondition) Date() *DateCondition {
+	return &DateCondition{typ: "date", wrap: c.wrap}
+}
+
+// RollupItemCondition 构建应用于汇总数组元素的条件，按元素的属性类型选择比较方式
+type RollupItemCondition struct {
+	wrap wrapFunc
+}
+
+// Title 按标题元素过滤
+func (c *RollupItemCondition) Title() *TextCondition {
+	return &TextCondition{typ: "title", wrap: c.wrap}
+}
+
+// RichText 按文本元素过滤
+func (c *RollupItemCondition) RichText() *TextCondition {
+	return &TextCondition{typ: "rich_text", wrap: c.wrap}
+}
+
+// Number 按数字元素过滤
+func (c *RollupItemCondition) Number() *NumberCondition {
+	return &NumberCondition{typ: "number", wrap: c.wrap}
+}
+
+// Checkbox 按复选框元素过滤
+func (c *RollupItemCondition) Checkbox() *CheckboxCondition {
+	return &CheckboxCondition{typ: "checkbox", wrap: c.wrap}
+}
+
+// Select 按单选元素过滤
+func (c *RollupItemCondition) Select() *SelectCondition {
+	return &SelectCondition{typ: "select", wrap: c.wrap}
+}
+
+// Status 按状态元素过滤
+func (c *RollupItemCondition) Status() *SelectCondition {
+	return &SelectCondition{typ: "status", wrap: c.wrap}
+}
+
+// MultiSelect 按多选元素过滤
+func (c *RollupItemCondition) MultiSelect() *ContainsCondition {
+	return &ContainsCondition{typ: "multi_select", wrap: c.wrap}
+}
+
+// People 按人员元素过滤
+func (c *RollupItemCondition) People() *ContainsCondition {
+	return &ContainsCondition{typ: "people", wrap: c.wrap}
+}
+
+// Relation 按关联元素过滤
+func (c *RollupItemCondition) Relation() *ContainsCondition {
+	return &ContainsCondition{typ: "relation", wrap: c.wrap}
+}
+
+// Files 按文件元素过滤
+func (c *RollupItemCondition) Files() *FilesCondition {
+	return &FilesCondition{typ: "files", wrap: c.wrap}
+}
+
+// Date 按日期元素过滤
+func (c *RollupItemCondition) Date() *DateCondition {
+	return &DateCondition{typ: "date", wrap: c.wrap}
+}
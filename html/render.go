@@ -0,0 +1,541 @@
+// Package html 将 Notion 块树转换为语义化的 HTML
+//
+// 所有文本都经过转义，链接只保留安全的协议，输出可以直接嵌入网页。
+// 颜色转换为 notion-<color> CSS 类，样式由使用者提供。
+package html
+
+import (
+	"context"
+	stdhtml "html"
+	"strings"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// Options 表示渲染 HTML 的选项
+type Options struct {
+	// PageURL 返回子页面和子数据库的链接地址，默认为 https://www.notion.so/<id>
+	PageURL func(id, title string) string
+
+	// FileURL 返回图片、视频、文件和 PDF 块的地址，默认使用块中的地址
+	//
+	// Notion 托管文件的签名地址会在 FileInfo.ExpiryTime 过期，
+	// 长期保存的页面可以在这里下载文件并返回自己的地址。
+	FileURL func(blockID string, f *notion.File) string
+
+	// Mentions 返回提及显示的文本，默认使用 Notion 返回的 plain_text
+	Mentions notion.MentionResolver
+}
+
+// Export 获取页面及其所有子块，转换为 HTML
+//
+// 子块通过 BlockService.GetTree 获取，同步块的副本使用原始块的内容。
+func Export(ctx context.Context, client *notion.Client, pageID string, opts *Options) (string, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	page, err := client.Pages.Get(ctx, pageID)
+	if err != nil {
+		return "", err
+	}
+	nodes, err := client.Blocks.GetTree(ctx, pageID, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`<article class="notion-page">` + "\n")
+	for _, v := range page.Properties {
+		if v.Type == notion.PropertyTypeTitle {
+			sb.WriteString(`<h1 class="notion-title">` + TextWith(v.Title, opts.Mentions) + "</h1>\n")
+			break
+		}
+	}
+	sb.WriteString(Render(nodes, opts))
+	sb.WriteString("</article>\n")
+	return sb.String(), nil
+}
+
+// Render 将块树转换为 HTML
+//
+// 连续的列表项合并为 <ul> 或 <ol>，分栏转换为带 notion-column-list 和 notion-column 类的 <div>。
+func Render(nodes []*notion.BlockNode, opts *Options) string {
+	if opts == nil {
+		opts = &Options{}
+	}
+	r := &renderer{opts: opts, headings: headings(nodes)}
+	r.blocks(nodes)
+	return r.sb.String()
+}
+
+// renderer 将块转换为 HTML
+type renderer struct {
+	opts     *Options
+	headings []*notion.BlockNode // 目录使用的标题
+	sb       strings.Builder
+}
+
+// headings 返回块树中的所有标题，子页面的内容不包含在内
+func headings(nodes []*notion.BlockNode) []*notion.BlockNode {
+	var out []*notion.BlockNode
+	for _, n := range nodes {
+		if n == nil || n.Block == nil {
+			continue
+		}
+		switch {
+		case n.Type == notion.TypeHeading1 && n.Heading1 != nil,
+			n.Type == notion.TypeHeading2 && n.Heading2 != nil,
+			n.Type == notion.TypeHeading3 && n.Heading3 != nil:
+			out = append(out, n)
+		}
+		out = append(out, headings(n.Children)...)
+	}
+	return out
+}
+
+// listTag 返回列表项所在列表的起始和结束标签，不是列表项时返回空字符串
+func listTag(t notion.BlockType) (open, close string) {
+	switch t {
+	case notion.TypeBulletedListItem:
+		return "<ul>", "</ul>"
+	case notion.TypeNumberedListItem:
+		return "<ol>", "</ol>"
+	case notion.TypeToDo:
+		return `<ul class="notion-to-do">`, "</ul>"
+	}
+	return "", ""
+}
+
+// isEmptyListItem 检查列表项块的内容是否为 nil，这样的块被跳过，不会打断所在的列表
+func isEmptyListItem(b *notion.Block) bool {
+	switch b.Type {
+	case notion.TypeBulletedListItem:
+		return b.BulletedListItem == nil
+	case notion.TypeNumberedListItem:
+		return b.NumberedListItem == nil
+	case notion.TypeToDo:
+		return b.ToDo == nil
+	}
+	return false
+}
+
+// blocks 转换同一层的块
+func (r *renderer) blocks(nodes []*notion.BlockNode) {
+	var list notion.BlockType
+	closeList := func() {
+		if _, end := listTag(list); end != "" {
+			r.sb.WriteString(end + "\n")
+		}
+		list = ""
+	}
+	for _, n := range nodes {
+		if n == nil || n.Block == nil || isEmptyListItem(n.Block) {
+			continue
+		}
+		if n.Type != list {
+			closeList()
+			if start, _ := listTag(n.Type); start != "" {
+				r.sb.WriteString(start + "\n")
+				list = n.Type
+			}
+		}
+		r.block(n)
+	}
+	closeList()
+}
+
+// block 转换单个块，类型对应的内容为 nil 时与不支持的块一样不输出
+func (r *renderer) block(n *notion.BlockNode) {
+	b := n.Block
+	switch b.Type {
+	case notion.TypeParagraph:
+		if b.Paragraph == nil {
+			break
+		}
+		r.sb.WriteString("<p" + classAttr("", b.Paragraph.Color) + ">" + r.text(b.Paragraph.RichText) + "</p>\n")
+		r.indent(n)
+
+	case notion.TypeHeading1:
+		if b.Heading1 == nil {
+			break
+		}
+		r.heading(n, "h1", b.Heading1)
+	case notion.TypeHeading2:
+		if b.Heading2 == nil {
+			break
+		}
+		r.heading(n, "h2", b.Heading2)
+	case notion.TypeHeading3:
+		if b.Heading3 == nil {
+			break
+		}
+		r.heading(n, "h3", b.Heading3)
+
+	case notion.TypeBulletedListItem:
+		if b.BulletedListItem == nil {
+			break
+		}
+		r.listItem(n, classAttr("", b.BulletedListItem.Color), r.text(b.BulletedListItem.RichText))
+	case notion.TypeNumberedListItem:
+		if b.NumberedListItem == nil {
+			break
+		}
+		r.listItem(n, classAttr("", b.NumberedListItem.Color), r.text(b.NumberedListItem.RichText))
+	case notion.TypeToDo:
+		if b.ToDo == nil {
+			break
+		}
+		box := `<input type="checkbox" disabled>`
+		if b.ToDo.Checked {
+			box = `<input type="checkbox" disabled checked>`
+		}
+		r.listItem(n, classAttr("", b.ToDo.Color), box+" "+r.text(b.ToDo.RichText))
+
+	case notion.TypeToggle:
+		if b.Toggle == nil {
+			break
+		}
+		r.sb.WriteString("<details" + classAttr("notion-toggle", b.Toggle.Color) + ">\n")
+		r.sb.WriteString("<summary>" + r.text(b.Toggle.RichText) + "</summary>\n")
+		r.blocks(n.Children)
+		r.sb.WriteString("</details>\n")
+
+	case notion.TypeQuote:
+		if b.Quote == nil {
+			break
+		}
+		r.sb.WriteString("<blockquote" + classAttr("", b.Quote.Color) + ">\n")
+		r.sb.WriteString("<p>" + r.text(b.Quote.RichText) + "</p>\n")
+		r.blocks(n.Children)
+		r.sb.WriteString("</blockquote>\n")
+
+	case notion.TypeCallout:
+		if b.Callout == nil {
+			break
+		}
+		r.sb.WriteString("<aside" + classAttr("notion-callout", b.Callout.Color) + ">\n")
+		if icon := r.icon(b.Callout.Icon); icon != "" {
+			r.sb.WriteString(`<span class="notion-callout-icon">` + icon + "</span>\n")
+		}
+		r.sb.WriteString(`<div class="notion-callout-content">` + "\n")
+		r.sb.WriteString("<p>" + r.text(b.Callout.RichText) + "</p>\n")
+		r.blocks(n.Children)
+		r.sb.WriteString("</div>\n</aside>\n")
+
+	case notion.TypeCode:
+		if b.Code == nil {
+			break
+		}
+		lang := ""
+		if b.Code.Language != "" && b.Code.Language != "plain text" {
+			lang = ` class="language-` + stdhtml.EscapeString(strings.ReplaceAll(b.Code.Language, " ", "-")) + `"`
+		}
+		code := "<pre><code" + lang + ">" + stdhtml.EscapeString(plain(b.Code.RichText)) + "</code></pre>"
+		r.figure("notion-code", code, b.Code.Caption)
+
+	case notion.TypeEquation:
+		if b.Equation == nil {
+			break
+		}
+		r.sb.WriteString(`<div class="notion-equation">` + stdhtml.EscapeString(b.Equation.Expression) + "</div>\n")
+
+	case notion.TypeDivider:
+		r.sb.WriteString("<hr>\n")
+
+	case notion.TypeImage:
+		if b.Image == nil {
+			break
+		}
+		alt := stdhtml.EscapeString(plain(b.Image.Caption))
+		r.figure("notion-image", `<img src="`+r.fileURL(b.ID, b.Image)+`" alt="`+alt+`">`, b.Image.Caption)
+	case notion.TypeVideo:
+		if b.Video == nil {
+			break
+		}
+		r.figure("notion-video", `<video src="`+r.fileURL(b.ID, b.Video)+`" controls></video>`, b.Video.Caption)
+	case notion.TypeFile:
+		if b.File == nil {
+			break
+		}
+		r.fileLink(b.ID, "notion-file", b.File)
+	case notion.TypePDF:
+		if b.PDF == nil {
+			break
+		}
+		r.fileLink(b.ID, "notion-pdf", b.PDF)
+
+	case notion.TypeBookmark:
+		if b.Bookmark == nil {
+			break
+		}
+		r.link("notion-bookmark", b.Bookmark.URL, r.text(b.Bookmark.Caption))
+	case notion.TypeEmbed:
+		if b.Embed == nil {
+			break
+		}
+		r.link("notion-embed", b.Embed.URL, "")
+	case notion.TypeLinkPreview:
+		if b.LinkPreview == nil {
+			break
+		}
+		r.link("notion-link-preview", b.LinkPreview.URL, "")
+
+	case notion.TypeTable:
+		if b.Table == nil {
+			break
+		}
+		r.table(b.Table, n.Children)
+
+	case notion.TypeChildPage:
+		if b.ChildPage == nil {
+			break
+		}
+		r.link("notion-page-link", r.pageURL(b.ID, b.ChildPage.Title), stdhtml.EscapeString(b.ChildPage.Title))
+	case notion.TypeChildDatabase:
+		if b.ChildDatabase == nil {
+			break
+		}
+		r.link("notion-database-link", r.pageURL(b.ID, b.ChildDatabase.Title), stdhtml.EscapeString(b.ChildDatabase.Title))
+
+	case notion.TypeColumnList:
+		r.sb.WriteString(`<div class="notion-column-list">` + "\n")
+		r.blocks(n.Children)
+		r.sb.WriteString("</div>\n")
+	case notion.TypeColumn:
+		r.sb.WriteString(`<div class="notion-column">` + "\n")
+		r.blocks(n.Children)
+		r.sb.WriteString("</div>\n")
+
+	case notion.TypeSyncedBlock:
+		r.sb.WriteString(`<div class="notion-synced-block">` + "\n")
+		r.blocks(n.Children)
+		r.sb.WriteString("</div>\n")
+
+	case notion.TypeTemplate:
+		if b.Template == nil {
+			break
+		}
+		r.sb.WriteString("<p>" + r.text(b.Template.RichText) + "</p>\n")
+		r.indent(n)
+
+	case notion.TypeTableOfContents:
+		r.tableOfContents()
+	}
+}
+
+// classAttr 返回包含基础类和颜色类的 class 属性，两者都为空时返回空字符串
+func classAttr(base string, color notion.Color) string {
+	classes := base
+	if c := colorClass(color); c != "" {
+		if classes != "" {
+			classes += " "
+		}
+		classes += c
+	}
+	if classes == "" {
+		return ""
+	}
+	return ` class="` + classes + `"`
+}
+
+// indent 将子块放在缩进的 <div> 中
+func (r *renderer) indent(n *notion.BlockNode) {
+	if len(n.Children) == 0 {
+		return
+	}
+	r.sb.WriteString(`<div class="notion-indent">` + "\n")
+	r.blocks(n.Children)
+	r.sb.WriteString("</div>\n")
+}
+
+// heading 转换标题，可折叠标题转换为 <details>
+func (r *renderer) heading(n *notion.BlockNode, tag string, h *notion.HeadingBlock) {
+	id := ""
+	if n.ID != "" {
+		id = ` id="` + anchor(n.ID) + `"`
+	}
+	out := "<" + tag + id + classAttr("", h.Color) + ">" + r.text(h.RichText) + "</" + tag + ">"
+	if !h.IsToggleable {
+		r.sb.WriteString(out + "\n")
+		return
+	}
+	r.sb.WriteString(`<details class="notion-toggle">` + "\n<summary>" + out + "</summary>\n")
+	r.blocks(n.Children)
+	r.sb.WriteString("</details>\n")
+}
+
+// anchor 返回标题的锚点
+func anchor(id string) string {
+	return "h-" + stdhtml.EscapeString(strings.ReplaceAll(id, "-", ""))
+}
+
+// listItem 转换列表项，子块作为嵌套内容
+func (r *renderer) listItem(n *notion.BlockNode, attr, text string) {
+	r.sb.WriteString("<li" + attr + ">" + text)
+	if len(n.Children) > 0 {
+		r.sb.WriteString("\n")
+		r.blocks(n.Children)
+	}
+	r.sb.WriteString("</li>\n")
+}
+
+// figure 输出带说明的 <figure>
+func (r *renderer) figure(class, content string, caption []notion.RichText) {
+	r.sb.WriteString(`<figure class="` + class + `">` + content)
+	if len(caption) > 0 {
+		r.sb.WriteString("<figcaption>" + r.text(caption) + "</figcaption>")
+	}
+	r.sb.WriteString("</figure>\n")
+}
+
+// link 输出独占一段的链接，label 为空时使用地址
+func (r *renderer) link(class, url, label string) {
+	href := safeURL(url)
+	if label == "" {
+		label = stdhtml.EscapeString(url)
+	}
+	if href == "" {
+		r.sb.WriteString(`<p class="` + class + `">` + label + "</p>\n")
+		return
+	}
+	r.sb.WriteString(`<p class="` + class + `"><a href="` + href + `">` + label + "</a></p>\n")
+}
+
+// fileLink 转换文件类的块为链接
+func (r *renderer) fileLink(blockID, class string, f *notion.File) {
+	label := r.text(f.Caption)
+	if label == "" {
+		label = stdhtml.EscapeString(f.Name)
+	}
+	href := r.fileURL(blockID, f)
+	if label == "" {
+		label = href
+	}
+	r.sb.WriteString(`<p class="` + class + `"><a href="` + href + `">` + label + "</a></p>\n")
+}
+
+// fileURL 返回转义后的文件地址，优先使用 Options.FileURL
+func (r *renderer) fileURL(blockID string, f *notion.File) string {
+	if f == nil {
+		return ""
+	}
+	if r.opts.FileURL != nil {
+		return safeURL(r.opts.FileURL(blockID, f))
+	}
+	switch {
+	case f.External != nil:
+		return safeURL(f.External.URL)
+	case f.File != nil:
+		return safeURL(f.File.URL)
+	}
+	return ""
+}
+
+// icon 返回图标的 HTML
+func (r *renderer) icon(icon *notion.Icon) string {
+	if icon == nil {
+		return ""
+	}
+	switch {
+	case icon.Emoji != "":
+		return stdhtml.EscapeString(icon.Emoji)
+	case icon.External != nil:
+		return `<img src="` + safeURL(icon.External.URL) + `" alt="">`
+	case icon.File != nil:
+		return `<img src="` + safeURL(icon.File.URL) + `" alt="">`
+	}
+	return ""
+}
+
+// table 转换表格，列标题放在 <thead> 中，行标题使用 <th>
+func (r *renderer) table(t *notion.TableBlock, rows []*notion.BlockNode) {
+	r.sb.WriteString(`<table class="notion-table">` + "\n")
+	body := false
+	for i, row := range rows {
+		if row == nil || row.TableRow == nil {
+			continue
+		}
+		header := i == 0 && t.HasColumnHeader
+		if header {
+			r.sb.WriteString("<thead>\n")
+		} else if !body {
+			r.sb.WriteString("<tbody>\n")
+			body = true
+		}
+		r.sb.WriteString("<tr>")
+		for j := 0; j < t.TableWidth; j++ {
+			var cell string
+			if j < len(row.TableRow.Cells) {
+				cell = r.text(row.TableRow.Cells[j])
+			}
+			tag := "td"
+			if header || j == 0 && t.HasRowHeader {
+				tag = "th"
+			}
+			r.sb.WriteString("<" + tag + ">" + cell + "</" + tag + ">")
+		}
+		r.sb.WriteString("</tr>\n")
+		if header {
+			r.sb.WriteString("</thead>\n")
+		}
+	}
+	if body {
+		r.sb.WriteString("</tbody>\n")
+	}
+	r.sb.WriteString("</table>\n")
+}
+
+// tableOfContents 输出链接到所有标题的目录
+func (r *renderer) tableOfContents() {
+	if len(r.headings) == 0 {
+		return
+	}
+	r.sb.WriteString(`<nav class="notion-table-of-contents">` + "\n<ul>\n")
+	for _, h := range r.headings {
+		var level string
+		var rt []notion.RichText
+		switch h.Type {
+		case notion.TypeHeading1:
+			level, rt = "1", h.Heading1.RichText
+		case notion.TypeHeading2:
+			level, rt = "2", h.Heading2.RichText
+		case notion.TypeHeading3:
+			level, rt = "3", h.Heading3.RichText
+		}
+		r.sb.WriteString(`<li class="notion-toc-` + level + `"><a href="#` + anchor(h.ID) + `">` + stdhtml.EscapeString(plain(rt)) + "</a></li>\n")
+	}
+	r.sb.WriteString("</ul>\n</nav>\n")
+}
+
+// text 转换块中的富文本，设置了 PageURL 时页面和数据库提及链接到 PageURL 返回的地址
+func (r *renderer) text(rt []notion.RichText) string {
+	if r.opts.PageURL != nil {
+		rt = append([]notion.RichText(nil), rt...)
+		for i, t := range rt {
+			if id := mentionedID(t.Mention); id != "" {
+				rt[i].Href = r.opts.PageURL(id, t.Content(r.opts.Mentions))
+			}
+		}
+	}
+	return TextWith(rt, r.opts.Mentions)
+}
+
+// mentionedID 返回页面或数据库提及的 ID，其他提及返回空字符串
+func mentionedID(m *notion.Mention) string {
+	switch {
+	case m == nil:
+	case m.Type == notion.MentionTypePage && m.Page != nil:
+		return m.Page.ID
+	case m.Type == notion.MentionTypeDatabase && m.Database != nil:
+		return m.Database.ID
+	}
+	return ""
+}
+
+// pageURL 返回子页面的链接地址
+func (r *renderer) pageURL(id, title string) string {
+	if r.opts.PageURL != nil {
+		return r.opts.PageURL(id, title)
+	}
+	return "https://www.notion.so/" + strings.ReplaceAll(id, "-", "")
+}
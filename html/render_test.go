@@ -0,0 +1,180 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// node 创建测试使用的块树节点
+func node(b notion.Block, children ...*notion.BlockNode) *notion.BlockNode {
+	return &notion.BlockNode{Block: &b, Children: children}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		rt   []notion.RichText
+		want string
+	}{
+		{[]notion.RichText{notion.Plain("<b>&")}, "&lt;b&gt;&amp;"},
+		{[]notion.RichText{notion.Plain("粗").Bold().Italic()}, "<em><strong>粗</strong></em>"},
+		{[]notion.RichText{notion.Plain("x").Code().Strikethrough().Underline()}, "<u><s><code>x</code></s></u>"},
+		{[]notion.RichText{notion.Plain("红").WithColor(notion.ColorRedBackground)}, `<span class="notion-red_background">红</span>`},
+		{[]notion.RichText{notion.Plain("链接").WithLink("https://go.dev/?a=1&b=2")}, `<a href="https://go.dev/?a=1&amp;b=2">链接</a>`},
+		{[]notion.RichText{notion.Plain("坏").WithLink("javascript:alert(1)")}, "坏"},
+		{[]notion.RichText{notion.Plain("a\nb")}, "a<br>b"},
+		{[]notion.RichText{notion.EquationText("a<b")}, `<span class="notion-equation">a&lt;b</span>`},
+		{[]notion.RichText{notion.MentionUser("u1").Bold()}, `<strong><span class="notion-mention notion-mention-user">@u1</span></strong>`},
+		{[]notion.RichText{{Type: "mention", Mention: &notion.Mention{Type: notion.MentionTypePage, Page: &notion.ObjectReference{ID: "p1"}}, PlainText: "设计", Href: "https://www.notion.so/p1"}},
+			`<a href="https://www.notion.so/p1"><span class="notion-mention notion-mention-page">设计</span></a>`},
+	}
+	for _, tt := range tests {
+		if got := Text(tt.rt); got != tt.want {
+			t.Errorf("Text(%q) = %q, 期望 %q", plain(tt.rt), got, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	image := notion.Block{Object: "block", ID: "img-1", Type: notion.TypeImage, Image: &notion.File{
+		Type: "file",
+		File: &notion.FileInfo{URL: "https://s3.example.com/a.png?sig=1", ExpiryTime: "2024-01-01T00:00:00.000Z"},
+	}}
+	heading := notion.Heading2("小节")
+	heading.ID = "ab-cd"
+
+	nodes := []*notion.BlockNode{
+		node(notion.TableOfContents()),
+		node(heading),
+		node(notion.Paragraph("<script>").WithColor(notion.ColorBlue)),
+		node(notion.BulletedListItem("a"), node(notion.BulletedListItem("b"))),
+		node(notion.NumberedListItem("一")),
+		node(notion.ToDo("完成", true)),
+		node(notion.ColumnList(), node(notion.Column(), node(notion.Paragraph("左"))), node(notion.Column(), node(notion.Paragraph("右")))),
+		node(notion.Code("go", "a < b")),
+		node(image),
+		node(notion.Table(nil).WithHeaders(true, false), node(notion.TableRow("名称")), node(notion.TableRow("苹果"))),
+	}
+	nodes[9].Table.TableWidth = 1
+
+	want := strings.Join([]string{
+		`<nav class="notion-table-of-contents">`,
+		`<ul>`,
+		`<li class="notion-toc-2"><a href="#h-abcd">小节</a></li>`,
+		`</ul>`,
+		`</nav>`,
+		`<h2 id="h-abcd">小节</h2>`,
+		`<p class="notion-blue">&lt;script&gt;</p>`,
+		`<ul>`,
+		`<li>a`,
+		`<ul>`,
+		`<li>b</li>`,
+		`</ul>`,
+		`</li>`,
+		`</ul>`,
+		`<ol>`,
+		`<li>一</li>`,
+		`</ol>`,
+		`<ul class="notion-to-do">`,
+		`<li><input type="checkbox" disabled checked> 完成</li>`,
+		`</ul>`,
+		`<div class="notion-column-list">`,
+		`<div class="notion-column">`,
+		`<p>左</p>`,
+		`</div>`,
+		`<div class="notion-column">`,
+		`<p>右</p>`,
+		`</div>`,
+		`</div>`,
+		`<figure class="notion-code"><pre><code class="language-go">a &lt; b</code></pre></figure>`,
+		`<figure class="notion-image"><img src="/files/img-1.png" alt=""></figure>`,
+		`<table class="notion-table">`,
+		`<thead>`,
+		`<tr><th>名称</th></tr>`,
+		`</thead>`,
+		`<tbody>`,
+		`<tr><td>苹果</td></tr>`,
+		`</tbody>`,
+		`</table>`,
+		``,
+	}, "\n")
+
+	got := Render(nodes, &Options{
+		FileURL: func(blockID string, f *notion.File) string { return "/files/" + blockID + ".png" },
+	})
+	if got != want {
+		t.Errorf("Render() =\n%s\n期望\n%s", got, want)
+	}
+}
+
+func TestRenderNilPayload(t *testing.T) {
+	types := []notion.BlockType{
+		notion.TypeParagraph, notion.TypeHeading1, notion.TypeHeading2, notion.TypeHeading3,
+		notion.TypeToggle, notion.TypeQuote, notion.TypeCallout, notion.TypeCode, notion.TypeEquation,
+		notion.TypeImage, notion.TypeVideo, notion.TypeFile, notion.TypePDF,
+		notion.TypeBookmark, notion.TypeEmbed, notion.TypeLinkPreview, notion.TypeTable,
+		notion.TypeChildPage, notion.TypeChildDatabase, notion.TypeTemplate,
+	}
+	nodes := []*notion.BlockNode{node(notion.TableOfContents())}
+	for _, typ := range types {
+		nodes = append(nodes, node(notion.Block{Object: "block", Type: typ}))
+	}
+	nodes = append(nodes,
+		node(notion.BulletedListItem("a")),
+		node(notion.Block{Object: "block", Type: notion.TypeBulletedListItem}),
+		node(notion.Block{Object: "block", Type: notion.TypeNumberedListItem}),
+		node(notion.Block{Object: "block", Type: notion.TypeToDo}),
+		node(notion.BulletedListItem("b")),
+	)
+
+	want := "<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n"
+	if got := Render(nodes, nil); got != want {
+		t.Errorf("Render = %q, 期望 %q", got, want)
+	}
+}
+
+func TestExportResolvesSyncedBlocks(t *testing.T) {
+	requests := make(map[string]int)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/pages/page-1":
+			fmt.Fprint(w, `{"object":"page","id":"page-1","properties":{
+				"Name":{"id":"title","type":"title","title":[{"type":"text","text":{"content":"文档"},"plain_text":"文档"}]}
+			}}`)
+		case "/blocks/page-1/children":
+			fmt.Fprint(w, `{"object":"list","results":[
+				{"object":"block","id":"s1","type":"synced_block","has_children":true,"synced_block":{"synced_from":{"block_id":"orig"}}},
+				{"object":"block","id":"s2","type":"synced_block","has_children":true,"synced_block":{"synced_from":{"block_id":"orig"}}}
+			],"has_more":false}`)
+		case "/blocks/orig/children":
+			fmt.Fprint(w, `{"object":"list","results":[
+				{"object":"block","id":"p1","type":"paragraph","paragraph":{"rich_text":[{"type":"text","text":{"content":"共享"},"plain_text":"共享"}]}}
+			],"has_more":false}`)
+		default:
+			t.Errorf("意外的请求: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := notion.NewClient("test-token", notion.WithBaseURL(ts.URL))
+	got, err := Export(context.Background(), client, "page-1", nil)
+	if err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+
+	block := "<div class=\"notion-synced-block\">\n<p>共享</p>\n</div>\n"
+	want := "<article class=\"notion-page\">\n<h1 class=\"notion-title\">文档</h1>\n" + block + block + "</article>\n"
+	if got != want {
+		t.Errorf("Export() =\n%s\n期望\n%s", got, want)
+	}
+	if n := requests["/blocks/orig/children"]; n != 1 {
+		t.Errorf("原始块请求次数 = %d, 期望 1", n)
+	}
+}
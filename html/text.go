@@ -0,0 +1,108 @@
+package html
+
+import (
+	stdhtml "html"
+	"net/url"
+	"strings"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// Text 将富文本转换为 HTML
+//
+// 注释转换为 <strong>、<em>、<code>、<s>、<u> 标签，颜色转换为 notion-<color> CSS 类，
+// 链接只保留 http、https、mailto 和相对地址。行内公式转换为带 notion-equation 类的 <span>，
+// 提及转换为带 notion-mention 和 notion-mention-<类型> 类的 <span>，文本使用 Notion 返回的 plain_text。
+func Text(rt []notion.RichText) string {
+	return TextWith(rt, nil)
+}
+
+// TextWith 与 Text 相同，提及显示的文本由 resolver 解析，resolver 为 nil 时与 Text 相同
+func TextWith(rt []notion.RichText, resolver notion.MentionResolver) string {
+	var sb strings.Builder
+	for _, t := range rt {
+		sb.WriteString(span(t, resolver))
+	}
+	return sb.String()
+}
+
+// span 转换单个文本片段
+func span(t notion.RichText, resolver notion.MentionResolver) string {
+	out := strings.ReplaceAll(stdhtml.EscapeString(t.Content(resolver)), "\n", "<br>")
+	if out == "" {
+		return ""
+	}
+	switch {
+	case t.Equation != nil:
+		out = `<span class="notion-equation">` + out + "</span>"
+	case t.Mention != nil:
+		out = `<span class="notion-mention notion-mention-` + className(t.Mention.Type) + `">` + out + "</span>"
+	}
+
+	if a := t.Annotations; a != nil {
+		if a.Code {
+			out = "<code>" + out + "</code>"
+		}
+		if a.Bold {
+			out = "<strong>" + out + "</strong>"
+		}
+		if a.Italic {
+			out = "<em>" + out + "</em>"
+		}
+		if a.Strikethrough {
+			out = "<s>" + out + "</s>"
+		}
+		if a.Underline {
+			out = "<u>" + out + "</u>"
+		}
+		if class := colorClass(a.Color); class != "" {
+			out = `<span class="` + class + `">` + out + "</span>"
+		}
+	}
+
+	if u := safeURL(t.LinkURL()); u != "" {
+		out = `<a href="` + u + `">` + out + "</a>"
+	}
+	return out
+}
+
+// colorClass 返回颜色对应的 CSS 类，默认颜色返回空字符串
+func colorClass(color notion.Color) string {
+	if color == "" || color == notion.ColorDefault {
+		return ""
+	}
+	return "notion-" + className(string(color))
+}
+
+// className 只保留 s 中合法的类名字符，颜色和提及类型来自 API 响应
+func className(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// safeURL 返回转义后可以放入属性的地址，不安全的协议（例如 javascript:）返回空字符串
+func safeURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return stdhtml.EscapeString(raw)
+	}
+	return ""
+}
+
+// plain 返回富文本的纯文本
+func plain(rt []notion.RichText) string {
+	return notion.PlainTextOf(rt, nil)
+}
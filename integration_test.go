@@ -1,6 +1,7 @@
 package notion
 
 import (
+	"context"
 	"testing"
 )
 
@@ -10,14 +11,15 @@ const (
 )
 
 func TestIntegrationDatabase(t *testing.T) {
-	if testing.Short() {
+	if testing.Short() || testAPIKey == "" {
 		t.Skip("跳过集成测试")
 	}
 
 	client := NewClient(testAPIKey)
+	ctx := context.Background()
 
 	// 获取数据库
-	db, err := client.Database.Get(testDatabaseID)
+	db, err := client.Database.Get(ctx, testDatabaseID)
 	if err != nil {
 		t.Fatalf("获取数据库失败: %v", err)
 	}
@@ -32,7 +34,7 @@ func TestIntegrationDatabase(t *testing.T) {
 		PageSize: 10,
 	}
 
-	results, err := client.Database.Query(testDatabaseID, queryParams)
+	results, err := client.Database.Query(ctx, testDatabaseID, queryParams)
 	if err != nil {
 		t.Fatalf("查询数据库失败: %v", err)
 	}
@@ -41,11 +43,12 @@ func TestIntegrationDatabase(t *testing.T) {
 }
 
 func TestIntegrationPage(t *testing.T) {
-	if testing.Short() {
+	if testing.Short() || testAPIKey == "" {
 		t.Skip("跳过集成测试")
 	}
 
 	client := NewClient(testAPIKey)
+	ctx := context.Background()
 
 	// 创建页面
 	createParams := &PageCreateParams{
@@ -53,20 +56,12 @@ func TestIntegrationPage(t *testing.T) {
 			Type:       "database_id",
 			DatabaseID: testDatabaseID,
 		},
-		Properties: map[string]interface{}{
-			"Name": map[string]interface{}{
-				"title": []map[string]interface{}{
-					{
-						"text": map[string]interface{}{
-							"content": "Test Page",
-						},
-					},
-				},
-			},
+		Properties: Properties{
+			"Name": NewTitleValue("Test Page"),
 		},
 	}
 
-	page, err := client.Pages.Create(createParams)
+	page, err := client.Pages.Create(ctx, createParams)
 	if err != nil {
 		t.Fatalf("创建页面失败: %v", err)
 	}
@@ -74,45 +69,25 @@ func TestIntegrationPage(t *testing.T) {
 	t.Logf("创建的页面 ID: %s", page.ID)
 
 	// 获取页面
-	retrievedPage, err := client.Pages.Get(page.ID)
+	retrievedPage, err := client.Pages.Get(ctx, page.ID)
 	if err != nil {
 		t.Fatalf("获取页面失败: %v", err)
 	}
 
 	// 安全地获取标题
-	if title, ok := retrievedPage.Properties["Name"].(map[string]interface{}); ok {
-		if titleArr, ok := title["title"].([]interface{}); ok && len(titleArr) > 0 {
-			if titleObj, ok := titleArr[0].(map[string]interface{}); ok {
-				if textObj, ok := titleObj["text"].(map[string]interface{}); ok {
-					if content, ok := textObj["content"].(string); ok {
-						t.Logf("页面标题: %s", content)
-					}
-				}
-			}
-		}
+	if title, err := retrievedPage.Properties.Title("Name"); err == nil {
+		t.Logf("页面标题: %s", title)
 	}
 
 	// 更新页面
 	updatePage := &Page{
-		Properties: map[string]interface{}{
-			"Name": map[string]interface{}{
-				"title": []map[string]interface{}{
-					{
-						"text": map[string]interface{}{
-							"content": "更新后的测试页面",
-						},
-					},
-				},
-			},
-			"Status": map[string]interface{}{
-				"select": map[string]interface{}{
-					"name": "已完成",
-				},
-			},
+		Properties: Properties{
+			"Name":   NewTitleValue("更新后的测试页面"),
+			"Status": NewSelectValue("已完成"),
 		},
 	}
 
-	updatedPage, err := client.Pages.Update(page.ID, updatePage)
+	updatedPage, err := client.Pages.Update(ctx, page.ID, updatePage)
 	if err != nil {
 		t.Fatalf("更新页面失败: %v", err)
 	}
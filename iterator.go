@@ -0,0 +1,170 @@
+package notion
+
+import (
+	"context"
+)
+
+// pageFetcher 获取从 cursor 开始的一页结果，返回结果、下一页游标和是否还有更多结果
+type pageFetcher[T any] func(ctx context.Context, cursor string) ([]T, string, bool, error)
+
+// fetchResult 表示预取的一页结果
+type fetchResult[T any] struct {
+	items   []T
+	cursor  string
+	hasMore bool
+	err     error
+}
+
+// Iterator 表示自动分页的迭代器，按 next_cursor 依次获取所有结果
+//
+// 用法：
+//
+//	it := client.Database.QueryIter(ctx, databaseID, nil)
+//	for it.Next() {
+//		page := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		// 处理错误
+//	}
+//
+// 提前结束迭代时应调用 Close，取消预取中的请求。
+type Iterator[T any] struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	fetch    pageFetcher[T]
+	prefetch bool
+
+	items   []T
+	cursor  string
+	hasMore bool
+	pending chan fetchResult[T]
+
+	value T
+	err   error
+}
+
+// newIterator 创建从 cursor 开始的迭代器
+func newIterator[T any](ctx context.Context, cursor string, fetch pageFetcher[T]) *Iterator[T] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &Iterator[T]{
+		ctx:     ctx,
+		cancel:  cancel,
+		fetch:   fetch,
+		cursor:  cursor,
+		hasMore: true,
+	}
+}
+
+// Prefetch 设置是否在消费当前页时并发获取下一页，需要在第一次调用 Next 之前设置
+func (it *Iterator[T]) Prefetch(enabled bool) *Iterator[T] {
+	it.prefetch = enabled
+	return it
+}
+
+// Next 前进到下一个结果，没有更多结果或出错时返回 false
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for len(it.items) == 0 {
+		if !it.hasMore {
+			it.cancel()
+			return false
+		}
+		var r fetchResult[T]
+		if it.pending != nil {
+			r = <-it.pending
+			it.pending = nil
+		} else {
+			r = it.fetchPage(it.cursor)
+		}
+		if r.err != nil {
+			it.err = r.err
+			it.cancel()
+			return false
+		}
+
+		it.items = r.items
+		it.cursor = r.cursor
+		it.hasMore = r.hasMore && r.cursor != ""
+
+		if it.prefetch && it.hasMore {
+			it.pending = make(chan fetchResult[T], 1)
+			go func(cursor string, pending chan<- fetchResult[T]) {
+				r := it.fetchPage(cursor)
+				select {
+				case pending <- r:
+				case <-it.ctx.Done():
+				}
+			}(it.cursor, it.pending)
+		}
+	}
+
+	it.value = it.items[0]
+	it.items = it.items[1:]
+	return true
+}
+
+// fetchPage 获取一页结果
+func (it *Iterator[T]) fetchPage(cursor string) fetchResult[T] {
+	items, next, hasMore, err := it.fetch(it.ctx, cursor)
+	return fetchResult[T]{items: items, cursor: next, hasMore: hasMore, err: err}
+}
+
+// Value 返回当前结果
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err 返回迭代过程中遇到的错误
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Cursor 返回下一页的游标，可用于之后继续迭代
+//
+// 游标以页为单位，当前页中尚未读取的结果不会出现在从该游标开始的迭代中。
+func (it *Iterator[T]) Cursor() string {
+	return it.cursor
+}
+
+// Close 停止迭代并取消预取中的请求，之后 Next 返回 false
+//
+// 当前页剩余的结果会被丢弃，Cursor 仍然返回下一页的游标。可以重复调用。
+func (it *Iterator[T]) Close() {
+	it.cancel()
+	it.items = nil
+	it.hasMore = false
+	it.pending = nil
+}
+
+// ForEach 对每个结果调用 fn，fn 返回错误时停止迭代并返回该错误
+func (it *Iterator[T]) ForEach(fn func(T) error) error {
+	for it.Next() {
+		if err := fn(it.Value()); err != nil {
+			it.Close()
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// All 收集所有结果，maxItems 大于 0 时最多返回 maxItems 个结果
+//
+// 达到 maxItems 时会丢弃当前页剩余的结果，之后 Cursor 返回的是下一页的游标，
+// 从该游标继续迭代会跳过这些结果，因此截断的 All 不能用 Cursor 恢复。
+func (it *Iterator[T]) All(maxItems int) ([]T, error) {
+	var all []T
+	for it.Next() {
+		all = append(all, it.Value())
+		if maxItems > 0 && len(all) >= maxItems {
+			it.Close()
+			break
+		}
+	}
+	return all, it.Err()
+}
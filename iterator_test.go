@@ -0,0 +1,176 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// pagedFetcher 返回共 total 个整数、每页 size 个的分页函数
+func pagedFetcher(total, size int, calls *int) pageFetcher[int] {
+	return func(ctx context.Context, cursor string) ([]int, string, bool, error) {
+		*calls++
+		start := 0
+		if cursor != "" {
+			start, _ = strconv.Atoi(cursor)
+		}
+		var items []int
+		for i := start; i < total && i < start+size; i++ {
+			items = append(items, i)
+		}
+		next := start + size
+		if next >= total {
+			return items, "", false, nil
+		}
+		return items, strconv.Itoa(next), true, nil
+	}
+}
+
+func TestIterator(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		t.Run(fmt.Sprintf("prefetch=%v", prefetch), func(t *testing.T) {
+			calls := 0
+			it := newIterator(context.Background(), "", pagedFetcher(25, 10, &calls)).Prefetch(prefetch)
+
+			var got []int
+			for it.Next() {
+				got = append(got, it.Value())
+			}
+			if err := it.Err(); err != nil {
+				t.Fatalf("迭代失败: %v", err)
+			}
+			if len(got) != 25 || got[24] != 24 {
+				t.Errorf("期望 25 个结果，得到 %v", got)
+			}
+			if calls != 3 {
+				t.Errorf("期望请求 3 页，实际 %d 页", calls)
+			}
+			if it.ctx.Err() == nil {
+				t.Error("期望迭代结束后释放上下文")
+			}
+		})
+	}
+}
+
+func TestIteratorAll(t *testing.T) {
+	calls := 0
+	all, err := newIterator(context.Background(), "", pagedFetcher(25, 10, &calls)).All(12)
+	if err != nil {
+		t.Fatalf("收集失败: %v", err)
+	}
+	if len(all) != 12 {
+		t.Errorf("期望 12 个结果，得到 %d 个", len(all))
+	}
+	if calls != 2 {
+		t.Errorf("期望只请求 2 页，实际 %d 页", calls)
+	}
+}
+
+func TestIteratorForEachStops(t *testing.T) {
+	calls := 0
+	stop := errors.New("stop")
+	seen := 0
+	err := newIterator(context.Background(), "", pagedFetcher(25, 10, &calls)).ForEach(func(int) error {
+		seen++
+		if seen == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || seen != 3 {
+		t.Errorf("期望在第 3 个结果停止，seen=%d err=%v", seen, err)
+	}
+}
+
+func TestIteratorCloseCancelsPrefetch(t *testing.T) {
+	stops := map[string]func(it *Iterator[int]) error{
+		"All": func(it *Iterator[int]) error {
+			_, err := it.All(2)
+			return err
+		},
+		"ForEach": func(it *Iterator[int]) error {
+			stop := errors.New("stop")
+			if err := it.ForEach(func(int) error { return stop }); err != stop {
+				return err
+			}
+			return nil
+		},
+	}
+	for name, run := range stops {
+		t.Run(name, func(t *testing.T) {
+			canceled := make(chan error, 1)
+			it := newIterator(context.Background(), "", func(ctx context.Context, cursor string) ([]int, string, bool, error) {
+				if cursor == "" {
+					return []int{1, 2, 3}, "next", true, nil
+				}
+				<-ctx.Done()
+				canceled <- ctx.Err()
+				return nil, "", false, ctx.Err()
+			}).Prefetch(true)
+
+			if err := run(it); err != nil {
+				t.Fatalf("迭代失败: %v", err)
+			}
+			if err := <-canceled; err != context.Canceled {
+				t.Errorf("期望预取的请求被取消，得到 %v", err)
+			}
+			if it.Next() {
+				t.Error("期望 Close 之后 Next 返回 false")
+			}
+		})
+	}
+}
+
+func TestIteratorError(t *testing.T) {
+	fail := errors.New("fail")
+	it := newIterator(context.Background(), "", func(ctx context.Context, cursor string) ([]int, string, bool, error) {
+		if cursor == "" {
+			return []int{1}, "next", true, nil
+		}
+		return nil, "", false, fail
+	})
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if count != 1 || it.Err() != fail {
+		t.Errorf("期望 1 个结果后出错，count=%d err=%v", count, it.Err())
+	}
+	if it.ctx.Err() == nil {
+		t.Error("期望出错后释放上下文")
+	}
+}
+
+func TestListChildrenIterFollowsCursor(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/blocks/root/children" {
+			t.Errorf("意外的路径: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("page_size") != "1" {
+			t.Errorf("期望 page_size=1，得到 %q", r.URL.RawQuery)
+		}
+		switch r.URL.Query().Get("start_cursor") {
+		case "":
+			w.Write([]byte(`{"object":"list","results":[{"object":"block","id":"a"}],"has_more":true,"next_cursor":"c2"}`))
+		case "c2":
+			w.Write([]byte(`{"object":"list","results":[{"object":"block","id":"b"}],"has_more":false,"next_cursor":null}`))
+		default:
+			t.Errorf("意外的游标: %s", r.URL.RawQuery)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient("test-token", WithBaseURL(ts.URL))
+	blocks, err := client.Blocks.ListChildrenIter(context.Background(), "root", &ListParams{PageSize: 1}).All(0)
+	if err != nil {
+		t.Fatalf("获取子块失败: %v", err)
+	}
+	if len(blocks) != 2 || blocks[0].ID != "a" || blocks[1].ID != "b" {
+		t.Errorf("期望子块 a、b，得到 %+v", blocks)
+	}
+}
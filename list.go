@@ -0,0 +1,147 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DecodeObject 根据 object 字段将 JSON 解码为对应的类型
+//
+// 返回 *Page、*Database、*Block、*User、*Comment 或 *PropertyItem，
+// 无法识别的对象解码为 map[string]interface{}。
+func DecodeObject(data []byte) (interface{}, error) {
+	var head struct {
+		Object string `json:"object"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	switch head.Object {
+	case "page":
+		v = new(Page)
+	case "database":
+		v = new(Database)
+	case "block":
+		v = new(Block)
+	case "user":
+		v = new(User)
+	case "comment":
+		v = new(Comment)
+	case "property_item":
+		v = new(PropertyItem)
+	default:
+		m := map[string]interface{}{}
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("解码 %s 对象失败: %v", head.Object, err)
+	}
+	return v, nil
+}
+
+// UnmarshalJSON 按 object 字段将每个结果解码为对应的类型
+func (r *ListResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Object     string            `json:"object"`
+		Type       string            `json:"type"`
+		Results    []json.RawMessage `json:"results"`
+		HasMore    bool              `json:"has_more"`
+		NextCursor string            `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	results := make([]interface{}, 0, len(raw.Results))
+	for _, item := range raw.Results {
+		v, err := DecodeObject(item)
+		if err != nil {
+			return err
+		}
+		results = append(results, v)
+	}
+
+	r.Object = raw.Object
+	r.Type = raw.Type
+	r.Results = results
+	r.HasMore = raw.HasMore
+	r.NextCursor = raw.NextCursor
+	return nil
+}
+
+// Pages 返回结果中的页面
+func (r *ListResponse) Pages() []*Page {
+	var pages []*Page
+	for _, result := range r.Results {
+		if page, ok := result.(*Page); ok {
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
+
+// Databases 返回结果中的数据库
+func (r *ListResponse) Databases() []*Database {
+	var databases []*Database
+	for _, result := range r.Results {
+		if database, ok := result.(*Database); ok {
+			databases = append(databases, database)
+		}
+	}
+	return databases
+}
+
+// PageList 表示页面列表
+type PageList struct {
+	Object     string  `json:"object"`
+	Results    []*Page `json:"results"`
+	HasMore    bool    `json:"has_more"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// DatabaseList 表示数据库列表
+type DatabaseList struct {
+	Object     string      `json:"object"`
+	Results    []*Database `json:"results"`
+	HasMore    bool        `json:"has_more"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// BlockList 表示块列表
+type BlockList struct {
+	Object     string   `json:"object"`
+	Results    []*Block `json:"results"`
+	HasMore    bool     `json:"has_more"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// UserList 表示用户列表
+type UserList struct {
+	Object     string  `json:"object"`
+	Results    []*User `json:"results"`
+	HasMore    bool    `json:"has_more"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// CommentList 表示评论列表
+type CommentList struct {
+	Object     string     `json:"object"`
+	Results    []*Comment `json:"results"`
+	HasMore    bool       `json:"has_more"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// PropertyItemList 表示分页返回的属性项列表
+type PropertyItemList struct {
+	Object       string          `json:"object"`
+	Results      []*PropertyItem `json:"results"`
+	PropertyItem *PropertyItem   `json:"property_item,omitempty"` // 属性本身的信息
+	HasMore      bool            `json:"has_more"`
+	NextCursor   string          `json:"next_cursor,omitempty"`
+}
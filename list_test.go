@@ -0,0 +1,69 @@
+package notion
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestListResponseDecodesObjects(t *testing.T) {
+	data := []byte(`{
+		"object": "list",
+		"type": "page_or_database",
+		"results": [
+			{"object": "page", "id": "page-1", "properties": {}},
+			{"object": "database", "id": "db-1", "title": []},
+			{"object": "block", "id": "block-1", "type": "paragraph"},
+			{"object": "user", "id": "user-1", "type": "person"},
+			{"object": "comment", "id": "comment-1"},
+			{"object": "property_item", "id": "title", "type": "title"},
+			{"object": "unknown", "id": "x"}
+		],
+		"has_more": true,
+		"next_cursor": "cursor-2"
+	}`)
+
+	var list ListResponse
+	if err := json.Unmarshal(data, &list); err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+
+	if !list.HasMore || list.NextCursor != "cursor-2" || list.Type != "page_or_database" {
+		t.Errorf("分页信息错误: %+v", list)
+	}
+	if page, ok := list.Results[0].(*Page); !ok || page.ID != "page-1" {
+		t.Errorf("期望 *Page，得到 %T", list.Results[0])
+	}
+	if _, ok := list.Results[1].(*Database); !ok {
+		t.Errorf("期望 *Database，得到 %T", list.Results[1])
+	}
+	if block, ok := list.Results[2].(*Block); !ok || block.Type != TypeParagraph {
+		t.Errorf("期望 *Block，得到 %T", list.Results[2])
+	}
+	if _, ok := list.Results[3].(*User); !ok {
+		t.Errorf("期望 *User，得到 %T", list.Results[3])
+	}
+	if _, ok := list.Results[4].(*Comment); !ok {
+		t.Errorf("期望 *Comment，得到 %T", list.Results[4])
+	}
+	if _, ok := list.Results[5].(*PropertyItem); !ok {
+		t.Errorf("期望 *PropertyItem，得到 %T", list.Results[5])
+	}
+	if _, ok := list.Results[6].(map[string]interface{}); !ok {
+		t.Errorf("期望 map[string]interface{}，得到 %T", list.Results[6])
+	}
+	if len(list.Pages()) != 1 || len(list.Databases()) != 1 {
+		t.Errorf("Pages/Databases 筛选错误")
+	}
+}
+
+func TestPageListDecoding(t *testing.T) {
+	data := []byte(`{"object": "list", "results": [{"object": "page", "id": "page-1"}], "next_cursor": null, "has_more": false}`)
+
+	var list PageList
+	if err := json.Unmarshal(data, &list); err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	if len(list.Results) != 1 || list.Results[0].ID != "page-1" {
+		t.Errorf("期望一个页面，得到 %+v", list.Results)
+	}
+}
@@ -0,0 +1,592 @@
+package markdown
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// Options 表示导出 Markdown 的选项
+type Options struct {
+	// PageURL 返回子页面和子数据库的链接地址，默认为 https://www.notion.so/<id>
+	PageURL func(id, title string) string
+
+	// NoFrontMatter 为 true 时 Export 不输出页面属性
+	NoFrontMatter bool
+
+	// Mentions 返回提及显示的文本，默认使用 Notion 返回的 plain_text
+	Mentions notion.MentionResolver
+}
+
+// Export 获取页面及其所有子块，转换为 Markdown
+//
+// 页面属性输出为 YAML front matter，子块通过 BlockService.GetTree 获取。
+func Export(ctx context.Context, client *notion.Client, pageID string, opts *Options) (string, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	page, err := client.Pages.Get(ctx, pageID)
+	if err != nil {
+		return "", err
+	}
+	nodes, err := client.Blocks.GetTree(ctx, pageID, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if !opts.NoFrontMatter {
+		sb.WriteString(FrontMatter(page))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(Render(nodes, opts))
+	return sb.String(), nil
+}
+
+// FrontMatter 将页面属性转换为 YAML front matter
+func FrontMatter(page *notion.Page) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+
+	title := ""
+	names := make([]string, 0, len(page.Properties))
+	for name, v := range page.Properties {
+		if v.Type == notion.PropertyTypeTitle {
+			title = v.PlainText()
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writeYAML(&sb, "title", title, "")
+	writeYAML(&sb, "id", page.ID, "")
+	if page.URL != "" {
+		writeYAML(&sb, "url", page.URL, "")
+	}
+	if page.CreatedTime != "" {
+		writeYAML(&sb, "created_time", page.CreatedTime, "")
+	}
+	if page.LastEditedTime != "" {
+		writeYAML(&sb, "last_edited_time", page.LastEditedTime, "")
+	}
+	if len(names) > 0 {
+		sb.WriteString("properties:\n")
+		for _, name := range names {
+			writeYAML(&sb, name, propertyValue(page.Properties[name]), "  ")
+		}
+	}
+
+	sb.WriteString("---\n")
+	return sb.String()
+}
+
+// propertyValue 返回属性值在 front matter 中的表示
+func propertyValue(v notion.PropertyValue) interface{} {
+	switch v.Type {
+	case notion.PropertyTypeNumber:
+		if v.Number != nil {
+			return *v.Number
+		}
+		return nil
+	case notion.PropertyTypeCheckbox:
+		return v.Checkbox
+	case notion.PropertyTypeMultiSelect:
+		names := make([]string, len(v.MultiSelect))
+		for i, o := range v.MultiSelect {
+			names[i] = o.Name
+		}
+		return names
+	case notion.PropertyTypePeople:
+		names := make([]string, len(v.People))
+		for i, u := range v.People {
+			names[i] = u.Name
+			if names[i] == "" {
+				names[i] = u.ID
+			}
+		}
+		return names
+	case notion.PropertyTypeRelation:
+		ids := make([]string, len(v.Relation))
+		for i, r := range v.Relation {
+			ids[i] = r.ID
+		}
+		return ids
+	case notion.PropertyTypeFiles:
+		urls := make([]string, len(v.Files))
+		for i, f := range v.Files {
+			urls[i] = fileURL(&f)
+		}
+		return urls
+	case notion.PropertyTypeDate:
+		if v.Date == nil {
+			return nil
+		}
+		if v.Date.End == nil {
+			return v.Date.Start
+		}
+		return map[string]string{"start": v.Date.Start, "end": *v.Date.End}
+	}
+	if s := v.PlainText(); s != "" {
+		return s
+	}
+	return nil
+}
+
+// writeYAML 写入一个 YAML 键值对，字符串使用 JSON 引号以保证合法
+func writeYAML(sb *strings.Builder, key string, value interface{}, indent string) {
+	sb.WriteString(indent)
+	sb.WriteString(yamlKey(key))
+	sb.WriteString(":")
+
+	switch v := value.(type) {
+	case []string:
+		if len(v) == 0 {
+			sb.WriteString(" []\n")
+			return
+		}
+		sb.WriteString("\n")
+		for _, item := range v {
+			sb.WriteString(indent + "  - " + yamlScalar(item) + "\n")
+		}
+	case map[string]string:
+		sb.WriteString("\n")
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sb.WriteString(indent + "  " + yamlKey(k) + ": " + yamlScalar(v[k]) + "\n")
+		}
+	default:
+		sb.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+// yamlKey 返回 YAML 键，只包含字母、数字、下划线和连字符的键不加引号
+func yamlKey(key string) string {
+	for _, r := range key {
+		if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return yamlScalar(key)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
+
+// yamlScalar 返回 YAML 标量，JSON 的字符串、数字和布尔值都是合法的 YAML
+func yamlScalar(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return `""`
+	}
+	return string(data)
+}
+
+// Render 将块树转换为 Markdown
+func Render(nodes []*notion.BlockNode, opts *Options) string {
+	if opts == nil {
+		opts = &Options{}
+	}
+	r := &renderer{opts: opts}
+	out := r.blocks(nodes)
+	if out == "" {
+		return ""
+	}
+	return out + "\n"
+}
+
+// renderer 将块转换为 Markdown
+type renderer struct {
+	opts *Options
+}
+
+// isListItem 判断块是否为列表项，连续的列表项之间不加空行
+func isListItem(t notion.BlockType) bool {
+	return t == notion.TypeBulletedListItem || t == notion.TypeNumberedListItem || t == notion.TypeToDo
+}
+
+// blocks 转换同一层的块
+func (r *renderer) blocks(nodes []*notion.BlockNode) string {
+	var sb strings.Builder
+	var prev notion.BlockType
+	number := 0
+	for _, n := range nodes {
+		if n == nil || n.Block == nil {
+			continue
+		}
+		if n.Type == notion.TypeNumberedListItem {
+			number++
+		} else {
+			number = 0
+		}
+		out := r.block(n, number)
+		if out == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			if isListItem(prev) && isListItem(n.Type) {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString("\n\n")
+			}
+		}
+		sb.WriteString(out)
+		prev = n.Type
+	}
+	return sb.String()
+}
+
+// block 转换单个块，number 是有序列表项的序号，类型对应的内容为 nil 时与不支持的块一样返回空字符串
+func (r *renderer) block(n *notion.BlockNode, number int) string {
+	b := n.Block
+	switch b.Type {
+	case notion.TypeParagraph:
+		if b.Paragraph == nil {
+			break
+		}
+		return r.withChildren(r.text(b.Paragraph.RichText), n)
+
+	case notion.TypeHeading1:
+		if b.Heading1 == nil {
+			break
+		}
+		return r.withChildren("# "+r.text(b.Heading1.RichText), n)
+	case notion.TypeHeading2:
+		if b.Heading2 == nil {
+			break
+		}
+		return r.withChildren("## "+r.text(b.Heading2.RichText), n)
+	case notion.TypeHeading3:
+		if b.Heading3 == nil {
+			break
+		}
+		return r.withChildren("### "+r.text(b.Heading3.RichText), n)
+
+	case notion.TypeBulletedListItem:
+		if b.BulletedListItem == nil {
+			break
+		}
+		return r.listItem("- ", r.text(b.BulletedListItem.RichText), n)
+	case notion.TypeNumberedListItem:
+		if b.NumberedListItem == nil {
+			break
+		}
+		return r.listItem(fmt.Sprintf("%d. ", number), r.text(b.NumberedListItem.RichText), n)
+	case notion.TypeToDo:
+		if b.ToDo == nil {
+			break
+		}
+		box := "[ ] "
+		if b.ToDo.Checked {
+			box = "[x] "
+		}
+		return r.listItem("- ", box+r.text(b.ToDo.RichText), n)
+
+	case notion.TypeToggle:
+		if b.Toggle == nil {
+			break
+		}
+		var sb strings.Builder
+		sb.WriteString("<details>\n<summary>" + r.text(b.Toggle.RichText) + "</summary>")
+		if children := r.blocks(n.Children); children != "" {
+			sb.WriteString("\n\n" + children)
+		}
+		sb.WriteString("\n\n</details>")
+		return sb.String()
+
+	case notion.TypeQuote:
+		if b.Quote == nil {
+			break
+		}
+		return prefixLines(r.withChildren(r.text(b.Quote.RichText), n), "> ")
+
+	case notion.TypeCallout:
+		if b.Callout == nil {
+			break
+		}
+		text := r.text(b.Callout.RichText)
+		if b.Callout.Icon != nil && b.Callout.Icon.Emoji != "" {
+			text = b.Callout.Icon.Emoji + " " + text
+		}
+		body := r.withChildren(text, n)
+		return prefixLines("[!"+admonition(b.Callout.Color)+"]\n"+body, "> ")
+
+	case notion.TypeCode:
+		if b.Code == nil {
+			break
+		}
+		return codeFence(b.Code)
+
+	case notion.TypeEquation:
+		if b.Equation == nil {
+			break
+		}
+		return "$$\n" + b.Equation.Expression + "\n$$"
+
+	case notion.TypeDivider:
+		return "---"
+
+	case notion.TypeImage:
+		if b.Image == nil {
+			break
+		}
+		return "![" + escape(plain(b.Image.Caption)) + "](" + fileURL(b.Image) + ")"
+	case notion.TypeVideo:
+		if b.Video == nil {
+			break
+		}
+		return fileLink(b.Video)
+	case notion.TypeFile:
+		if b.File == nil {
+			break
+		}
+		return fileLink(b.File)
+	case notion.TypePDF:
+		if b.PDF == nil {
+			break
+		}
+		return fileLink(b.PDF)
+
+	case notion.TypeBookmark:
+		if b.Bookmark == nil {
+			break
+		}
+		return link(r.text(b.Bookmark.Caption), b.Bookmark.URL)
+	case notion.TypeEmbed:
+		if b.Embed == nil {
+			break
+		}
+		return link("", b.Embed.URL)
+	case notion.TypeLinkPreview:
+		if b.LinkPreview == nil {
+			break
+		}
+		return link("", b.LinkPreview.URL)
+
+	case notion.TypeTable:
+		if b.Table == nil {
+			break
+		}
+		return r.table(b.Table, n.Children)
+
+	case notion.TypeChildPage:
+		if b.ChildPage == nil {
+			break
+		}
+		return link(escape(b.ChildPage.Title), r.pageURL(b.ID, b.ChildPage.Title))
+	case notion.TypeChildDatabase:
+		if b.ChildDatabase == nil {
+			break
+		}
+		return link(escape(b.ChildDatabase.Title), r.pageURL(b.ID, b.ChildDatabase.Title))
+
+	case notion.TypeColumnList, notion.TypeColumn, notion.TypeSyncedBlock:
+		return r.blocks(n.Children)
+
+	case notion.TypeTemplate:
+		if b.Template == nil {
+			break
+		}
+		return r.withChildren(r.text(b.Template.RichText), n)
+
+	case notion.TypeTableOfContents, notion.TypeBreadcrumb:
+		return "<!-- " + string(b.Type) + " -->"
+	}
+	return ""
+}
+
+// withChildren 在文本之后追加子块
+func (r *renderer) withChildren(text string, n *notion.BlockNode) string {
+	children := r.blocks(n.Children)
+	switch {
+	case children == "":
+		return text
+	case text == "":
+		return children
+	}
+	return text + "\n\n" + children
+}
+
+// listItem 转换列表项，子块按列表标记的宽度缩进
+func (r *renderer) listItem(marker, text string, n *notion.BlockNode) string {
+	out := marker + text
+	if children := r.blocks(n.Children); children != "" {
+		out += "\n" + indentLines(children, strings.Repeat(" ", len(marker)))
+	}
+	return out
+}
+
+// table 转换为 GFM 表格，没有列标题时使用空的标题行
+func (r *renderer) table(t *notion.TableBlock, rows []*notion.BlockNode) string {
+	width := t.TableWidth
+	var cells [][]string
+	for _, row := range rows {
+		if row.TableRow == nil {
+			continue
+		}
+		line := make([]string, width)
+		for i, cell := range row.TableRow.Cells {
+			if i < width {
+				line[i] = tableCell(r.text(cell))
+			}
+		}
+		cells = append(cells, line)
+	}
+	if width == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeRow := func(line []string) {
+		sb.WriteString("| " + strings.Join(line, " | ") + " |\n")
+	}
+	if t.HasColumnHeader && len(cells) > 0 {
+		writeRow(cells[0])
+		cells = cells[1:]
+	} else {
+		writeRow(make([]string, width))
+	}
+	sep := make([]string, width)
+	for i := range sep {
+		sep[i] = "---"
+	}
+	writeRow(sep)
+	for _, line := range cells {
+		writeRow(line)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// tableCell 转义单元格中的竖线和换行
+func tableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\\\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// text 转换块中的富文本，设置了 PageURL 时页面和数据库提及链接到 PageURL 返回的地址
+func (r *renderer) text(rt []notion.RichText) string {
+	if r.opts.PageURL != nil {
+		rt = append([]notion.RichText(nil), rt...)
+		for i, t := range rt {
+			if id := mentionedID(t.Mention); id != "" {
+				rt[i].Href = r.opts.PageURL(id, t.Content(r.opts.Mentions))
+			}
+		}
+	}
+	return TextWith(rt, r.opts.Mentions)
+}
+
+// mentionedID 返回页面或数据库提及的 ID，其他提及返回空字符串
+func mentionedID(m *notion.Mention) string {
+	switch {
+	case m == nil:
+	case m.Type == notion.MentionTypePage && m.Page != nil:
+		return m.Page.ID
+	case m.Type == notion.MentionTypeDatabase && m.Database != nil:
+		return m.Database.ID
+	}
+	return ""
+}
+
+// pageURL 返回子页面的链接地址
+func (r *renderer) pageURL(id, title string) string {
+	if r.opts.PageURL != nil {
+		return r.opts.PageURL(id, title)
+	}
+	return "https://www.notion.so/" + strings.ReplaceAll(id, "-", "")
+}
+
+// admonition 根据标注块的颜色选择提示类型
+func admonition(color notion.Color) string {
+	switch strings.TrimSuffix(string(color), "_background") {
+	case "red":
+		return "CAUTION"
+	case "orange", "yellow":
+		return "WARNING"
+	case "green":
+		return "TIP"
+	case "purple", "pink":
+		return "IMPORTANT"
+	}
+	return "NOTE"
+}
+
+// codeFence 转换代码块，围栏长度大于内容中最长的反引号序列
+func codeFence(c *notion.CodeBlock) string {
+	src := plain(c.RichText)
+	fence := "```"
+	for strings.Contains(src, fence) {
+		fence += "`"
+	}
+	lang := c.Language
+	if lang == "plain text" {
+		lang = ""
+	}
+	return fence + strings.ReplaceAll(lang, " ", "-") + "\n" + src + "\n" + fence
+}
+
+// fileURL 返回外部文件或 Notion 托管文件的地址
+func fileURL(f *notion.File) string {
+	switch {
+	case f == nil:
+		return ""
+	case f.External != nil:
+		return f.External.URL
+	case f.File != nil:
+		return f.File.URL
+	}
+	return ""
+}
+
+// fileLink 转换文件类的块为链接
+func fileLink(f *notion.File) string {
+	label := escape(plain(f.Caption))
+	if label == "" {
+		label = escape(f.Name)
+	}
+	return link(label, fileURL(f))
+}
+
+// link 返回 Markdown 链接，label 为空时使用地址
+func link(label, url string) string {
+	if label == "" {
+		label = escape(url)
+	}
+	return "[" + label + "](" + url + ")"
+}
+
+// prefixLines 为每一行添加前缀，空行只保留去掉尾部空格的前缀
+func prefixLines(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentLines 缩进非空行
+func indentLines(s, indent string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
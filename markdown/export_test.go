@@ -0,0 +1,234 @@
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// node 创建测试使用的块树节点
+func node(b notion.Block, children ...*notion.BlockNode) *notion.BlockNode {
+	return &notion.BlockNode{Block: &b, Children: children}
+}
+
+func TestRender(t *testing.T) {
+	nodes := []*notion.BlockNode{
+		node(notion.Heading1("标题")),
+		node(notion.Paragraph("普通 ", notion.Plain("粗体").Bold(), notion.Plain(" 和 "), notion.Plain("链接").WithLink("https://go.dev"))),
+		node(notion.BulletedListItem("a"), node(notion.BulletedListItem("b"))),
+		node(notion.NumberedListItem("一")),
+		node(notion.NumberedListItem("二")),
+		node(notion.ToDo("完成", true)),
+		node(notion.Toggle("更多"), node(notion.Paragraph("隐藏内容"))),
+		node(notion.Callout("💡", "提示").WithColor(notion.ColorYellowBackground)),
+		node(notion.Quote("引用")),
+		node(notion.Code("go", "fmt.Println(\"```\")")),
+		node(notion.Equation("E = mc^2")),
+		node(notion.Divider()),
+		node(notion.Table(nil).WithHeaders(true, false),
+			node(notion.TableRow("名称", "数量")),
+			node(notion.TableRow("苹果|梨", "3")),
+		),
+		node(notion.Image("https://example.com/a.png").WithCaption("图片")),
+		node(notion.Block{Object: "block", ID: "1234-abcd", Type: notion.TypeChildPage, ChildPage: &notion.ChildPageBlock{Title: "子页面"}}),
+	}
+	nodes[12].Table.TableWidth = 2
+
+	want := strings.Join([]string{
+		"# 标题",
+		"",
+		"普通 **粗体** 和 [链接](https://go.dev)",
+		"",
+		"- a",
+		"  - b",
+		"1. 一",
+		"2. 二",
+		"- [x] 完成",
+		"",
+		"<details>",
+		"<summary>更多</summary>",
+		"",
+		"隐藏内容",
+		"",
+		"</details>",
+		"",
+		"> [!WARNING]",
+		"> 💡 提示",
+		"",
+		"> 引用",
+		"",
+		"````go",
+		"fmt.Println(\"```\")",
+		"````",
+		"",
+		"$$",
+		"E = mc^2",
+		"$$",
+		"",
+		"---",
+		"",
+		"| 名称 | 数量 |",
+		"| --- | --- |",
+		"| 苹果\\|梨 | 3 |",
+		"",
+		"![图片](https://example.com/a.png)",
+		"",
+		"[子页面](https://www.notion.so/1234abcd)",
+		"",
+	}, "\n")
+
+	if got := Render(nodes, nil); got != want {
+		t.Errorf("Render() =\n%s\n期望\n%s", got, want)
+	}
+}
+
+func TestRenderNilPayload(t *testing.T) {
+	types := []notion.BlockType{
+		notion.TypeParagraph, notion.TypeHeading1, notion.TypeHeading2, notion.TypeHeading3,
+		notion.TypeBulletedListItem, notion.TypeNumberedListItem, notion.TypeToDo, notion.TypeToggle,
+		notion.TypeQuote, notion.TypeCallout, notion.TypeCode, notion.TypeEquation,
+		notion.TypeImage, notion.TypeVideo, notion.TypeFile, notion.TypePDF,
+		notion.TypeBookmark, notion.TypeEmbed, notion.TypeLinkPreview, notion.TypeTable,
+		notion.TypeChildPage, notion.TypeChildDatabase, notion.TypeTemplate,
+	}
+	var nodes []*notion.BlockNode
+	for _, typ := range types {
+		nodes = append(nodes, node(notion.Block{Object: "block", Type: typ}))
+	}
+	nodes = append(nodes, node(notion.Paragraph("之后")))
+
+	if got := Render(nodes, nil); got != "之后\n" {
+		t.Errorf("Render = %q, 期望跳过没有内容的块", got)
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		rt   []notion.RichText
+		want string
+	}{
+		{[]notion.RichText{notion.Plain("a*b_c")}, `a\*b\_c`},
+		{[]notion.RichText{notion.Plain(" 粗体 ").Bold()}, " **粗体** "},
+		{[]notion.RichText{notion.Plain("a").Bold(), notion.Plain("b").Bold()}, "**ab**"},
+		{[]notion.RichText{notion.Plain("x").Bold().Italic().Strikethrough()}, "***~~x~~***"},
+		{[]notion.RichText{notion.Plain("a`b").Code()}, "``a`b``"},
+		{[]notion.RichText{notion.Plain("u").Underline()}, "<u>u</u>"},
+		{[]notion.RichText{notion.Plain("第一行\n第二行")}, "第一行\\\n第二行"},
+		{[]notion.RichText{notion.Plain("面积 "), notion.EquationText("\\pi r^2")}, "面积 $\\pi r^2$"},
+		{[]notion.RichText{notion.Plain("问 "), notion.MentionUser("user-1")}, "问 @user-1"},
+		{[]notion.RichText{{Type: "mention", Mention: &notion.Mention{Type: notion.MentionTypePage, Page: &notion.ObjectReference{ID: "p1"}}, PlainText: "设计", Href: "https://www.notion.so/p1"}}, "[设计](https://www.notion.so/p1)"},
+	}
+	for _, tt := range tests {
+		if got := Text(tt.rt); got != tt.want {
+			t.Errorf("Text(%q) = %q, 期望 %q", plain(tt.rt), got, tt.want)
+		}
+	}
+}
+
+func TestRenderMentions(t *testing.T) {
+	p := notion.Paragraph("").WithRichText(notion.MentionPage("p1"), notion.Plain(" 由 "), notion.MentionUser("u1"))
+	opts := &Options{
+		PageURL: func(id, title string) string { return "/pages/" + id + ".md" },
+		Mentions: func(m *notion.Mention) string {
+			switch m.Type {
+			case notion.MentionTypePage:
+				return "设计文档"
+			case notion.MentionTypeUser:
+				return "@张三"
+			}
+			return ""
+		},
+	}
+	want := "[设计文档](/pages/p1.md) 由 @张三\n"
+	if got := Render([]*notion.BlockNode{{Block: &p}}, opts); got != want {
+		t.Errorf("Render = %q, 期望 %q", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	src := "# 标题\n\n段落 **粗体** *斜体* `代码` [链接](https://go.dev)\n\n- a\n  - b\n- [ ] 任务\n\n```go\nx := 1\n```\n"
+
+	var toNodes func(blocks []notion.Block) []*notion.BlockNode
+	toNodes = func(blocks []notion.Block) []*notion.BlockNode {
+		var nodes []*notion.BlockNode
+		for i := range blocks {
+			b := blocks[i]
+			n := &notion.BlockNode{Block: &b}
+			if b.BulletedListItem != nil {
+				n.Children = toNodes(b.BulletedListItem.Children)
+			}
+			nodes = append(nodes, n)
+		}
+		return nodes
+	}
+
+	got := Render(toNodes(ToBlocks([]byte(src))), nil)
+	if got != src {
+		t.Errorf("往返转换 =\n%s\n期望\n%s", got, src)
+	}
+}
+
+func TestExport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pages/page-1":
+			fmt.Fprint(w, `{"object":"page","id":"page-1","url":"https://www.notion.so/page-1","properties":{
+				"Name":{"id":"title","type":"title","title":[{"type":"text","text":{"content":"文档"},"plain_text":"文档"}]},
+				"标签":{"id":"a","type":"multi_select","multi_select":[{"name":"go"},{"name":"sdk"}]},
+				"完成":{"id":"b","type":"checkbox","checkbox":true}
+			}}`)
+		case "/blocks/page-1/children":
+			fmt.Fprint(w, `{"object":"list","results":[
+				{"object":"block","id":"t1","type":"toggle","has_children":true,"toggle":{"rich_text":[{"type":"text","text":{"content":"展开"},"plain_text":"展开"}]}},
+				{"object":"block","id":"c1","type":"child_page","has_children":true,"child_page":{"title":"子页面"}}
+			],"has_more":false}`)
+		case "/blocks/t1/children":
+			fmt.Fprint(w, `{"object":"list","results":[
+				{"object":"block","id":"p1","type":"paragraph","paragraph":{"rich_text":[{"type":"text","text":{"content":"内容"},"plain_text":"内容"}]}}
+			],"has_more":false}`)
+		default:
+			t.Errorf("意外的请求: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := notion.NewClient("test-token", notion.WithBaseURL(ts.URL))
+	got, err := Export(context.Background(), client, "page-1", &Options{
+		PageURL: func(id, title string) string { return "/docs/" + id },
+	})
+	if err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"---",
+		`title: "文档"`,
+		`id: "page-1"`,
+		`url: "https://www.notion.so/page-1"`,
+		"properties:",
+		`  "完成": true`,
+		`  "标签":`,
+		`    - "go"`,
+		`    - "sdk"`,
+		"---",
+		"",
+		"<details>",
+		"<summary>展开</summary>",
+		"",
+		"内容",
+		"",
+		"</details>",
+		"",
+		"[子页面](/docs/c1)",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("Export() =\n%s\n期望\n%s", got, want)
+	}
+}
@@ -0,0 +1,202 @@
+// Package markdown 在 Markdown 和 Notion 块之间转换
+//
+// ToBlocks 支持 CommonMark 和 GFM 语法：标题、多级列表、任务列表、带语言的代码块、
+// 引用、表格、图片、链接、行内粗体/斜体/代码/删除线、分割线以及 $$ 公式块。
+package markdown
+
+import (
+	"context"
+	"strings"
+
+	notion "github.com/kuekiko/NotionGO"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// md 是解析 Markdown 使用的 goldmark 实例
+var md = goldmark.New(goldmark.WithExtensions(extension.GFM, mathExtension{}))
+
+// ToBlocks 将 Markdown 转换为 Notion 块
+//
+// 超过 errors.SizeLimits.MaxRichTextContent 的文本会被拆分为多个片段。
+// 返回的块可以直接传给 BlockService.AppendChildren，它会按 Notion 的限制分批追加。
+func ToBlocks(source []byte) []notion.Block {
+	doc := md.Parser().Parse(text.NewReader(source))
+	c := &converter{source: source}
+	return c.blocks(doc)
+}
+
+// Import 将 Markdown 转换为块并追加到 blockID 下
+func Import(ctx context.Context, client *notion.Client, blockID string, source []byte) (*notion.BlockList, error) {
+	return client.Blocks.AppendChildren(ctx, blockID, ToBlocks(source))
+}
+
+// converter 将 goldmark 语法树转换为块
+type converter struct {
+	source []byte
+}
+
+// blocks 转换 parent 的所有子节点
+func (c *converter) blocks(parent ast.Node) []notion.Block {
+	var blocks []notion.Block
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		blocks = append(blocks, c.block(n)...)
+	}
+	return blocks
+}
+
+// block 转换单个块级节点，段落中的图片会拆分为独立的图片块
+func (c *converter) block(n ast.Node) []notion.Block {
+	switch n := n.(type) {
+	case *ast.Heading:
+		rt, _ := c.inline(n)
+		switch n.Level {
+		case 1:
+			return []notion.Block{notion.Heading1("").WithRichText(rt...)}
+		case 2:
+			return []notion.Block{notion.Heading2("").WithRichText(rt...)}
+		default:
+			return []notion.Block{notion.Heading3("").WithRichText(rt...)}
+		}
+
+	case *ast.Paragraph, *ast.TextBlock:
+		rt, images := c.inline(n)
+		var blocks []notion.Block
+		if len(rt) > 0 {
+			blocks = append(blocks, notion.Paragraph("").WithRichText(rt...))
+		}
+		return append(blocks, images...)
+
+	case *ast.ThematicBreak:
+		return []notion.Block{notion.Divider()}
+
+	case *ast.FencedCodeBlock:
+		language := ""
+		if n.Info != nil {
+			language = string(n.Language(c.source))
+		}
+		return []notion.Block{notion.Code(codeLanguage(language), c.lines(n))}
+
+	case *ast.CodeBlock:
+		return []notion.Block{notion.Code("", c.lines(n))}
+
+	case *ast.Blockquote:
+		return []notion.Block{c.container(n, notion.Quote(""))}
+
+	case *ast.List:
+		var blocks []notion.Block
+		for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+			blocks = append(blocks, c.listItem(n, item))
+		}
+		return blocks
+
+	case *east.Table:
+		return []notion.Block{c.table(n)}
+
+	case *mathBlock:
+		return []notion.Block{notion.Equation(n.expression.String())}
+
+	case *ast.HTMLBlock:
+		return []notion.Block{notion.Paragraph(strings.TrimRight(c.lines(n), "\n"))}
+	}
+	return nil
+}
+
+// container 将第一个段落作为 b 的文本，其余节点作为子块
+func (c *converter) container(n ast.Node, b notion.Block) notion.Block {
+	first := n.FirstChild()
+	var children []notion.Block
+	if p, ok := first.(*ast.Paragraph); ok {
+		rt, images := c.inline(p)
+		b = b.WithRichText(rt...)
+		children = append(children, images...)
+		first = first.NextSibling()
+	} else if p, ok := first.(*ast.TextBlock); ok {
+		rt, images := c.inline(p)
+		b = b.WithRichText(rt...)
+		children = append(children, images...)
+		first = first.NextSibling()
+	}
+	for ; first != nil; first = first.NextSibling() {
+		children = append(children, c.block(first)...)
+	}
+	if len(children) > 0 {
+		b = b.WithChildren(children...)
+	}
+	return b
+}
+
+// listItem 转换列表项，带复选框的项转换为待办事项
+func (c *converter) listItem(list *ast.List, item ast.Node) notion.Block {
+	var b notion.Block
+	switch {
+	case taskCheckBox(item) != nil:
+		b = notion.ToDo("", taskCheckBox(item).IsChecked)
+	case list.IsOrdered():
+		b = notion.NumberedListItem("")
+	default:
+		b = notion.BulletedListItem("")
+	}
+	return c.container(item, b)
+}
+
+// taskCheckBox 返回列表项开头的任务复选框
+func taskCheckBox(item ast.Node) *east.TaskCheckBox {
+	first := item.FirstChild()
+	if first == nil {
+		return nil
+	}
+	box, _ := first.FirstChild().(*east.TaskCheckBox)
+	return box
+}
+
+// table 转换 GFM 表格，第一行作为列标题
+func (c *converter) table(n *east.Table) notion.Block {
+	var rows []notion.Block
+	width := 0
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells [][]notion.RichText
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			rt, _ := c.inline(cell)
+			cells = append(cells, rt)
+		}
+		if len(cells) > width {
+			width = len(cells)
+		}
+		rows = append(rows, notion.Block{
+			Object:   "block",
+			Type:     notion.TypeTableRow,
+			TableRow: &notion.TableRowBlock{Cells: cells},
+		})
+	}
+	for _, row := range rows {
+		for len(row.TableRow.Cells) < width {
+			row.TableRow.Cells = append(row.TableRow.Cells, []notion.RichText{})
+		}
+	}
+
+	_, hasHeader := n.FirstChild().(*east.TableHeader)
+	return notion.Block{
+		Object: "block",
+		Type:   notion.TypeTable,
+		Table: &notion.TableBlock{
+			TableWidth:      width,
+			HasColumnHeader: hasHeader,
+			Children:        rows,
+		},
+	}
+}
+
+// lines 返回代码块等原始节点的内容，去掉末尾的换行
+func (c *converter) lines(n ast.Node) string {
+	var sb strings.Builder
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		sb.Write(segment.Value(c.source))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,217 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	notion "github.com/kuekiko/NotionGO"
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// plainText 返回富文本的纯文本
+func plainText(rt []notion.RichText) string {
+	var sb strings.Builder
+	for _, t := range rt {
+		sb.WriteString(t.PlainText)
+	}
+	return sb.String()
+}
+
+func TestToBlocksStructure(t *testing.T) {
+	src := strings.Join([]string{
+		"# 标题",
+		"",
+		"段落",
+		"",
+		"- a",
+		"  - b",
+		"- [x] 完成",
+		"- [ ] 未完成",
+		"",
+		"1. 一",
+		"2. 二",
+		"",
+		"```golang",
+		"fmt.Println()",
+		"```",
+		"",
+		"> 引用",
+		">",
+		"> 第二段",
+		"",
+		"| 名称 | 数量 |",
+		"| --- | --- |",
+		"| 苹果 | 3 |",
+		"| 梨 |",
+		"",
+		"![图片](https://example.com/a.png)",
+		"",
+		"---",
+		"",
+		"$$",
+		"E = mc^2",
+		"$$",
+		"",
+		"#### 四级标题",
+	}, "\n")
+
+	blocks := ToBlocks([]byte(src))
+	wantTypes := []notion.BlockType{
+		notion.TypeHeading1,
+		notion.TypeParagraph,
+		notion.TypeBulletedListItem,
+		notion.TypeToDo,
+		notion.TypeToDo,
+		notion.TypeNumberedListItem,
+		notion.TypeNumberedListItem,
+		notion.TypeCode,
+		notion.TypeQuote,
+		notion.TypeTable,
+		notion.TypeImage,
+		notion.TypeDivider,
+		notion.TypeEquation,
+		notion.TypeHeading3,
+	}
+	if len(blocks) != len(wantTypes) {
+		for _, b := range blocks {
+			t.Logf("%s", b.Type)
+		}
+		t.Fatalf("块数 = %d, 期望 %d", len(blocks), len(wantTypes))
+	}
+	for i, want := range wantTypes {
+		if blocks[i].Type != want {
+			t.Errorf("第 %d 个块的类型 = %s, 期望 %s", i, blocks[i].Type, want)
+		}
+	}
+
+	if item := blocks[2].BulletedListItem; len(item.Children) != 1 || plainText(item.Children[0].BulletedListItem.RichText) != "b" {
+		t.Errorf("嵌套列表 = %+v", item.Children)
+	}
+	if !blocks[3].ToDo.Checked || blocks[4].ToDo.Checked || plainText(blocks[3].ToDo.RichText) != "完成" {
+		t.Errorf("任务列表 = %+v, %+v", blocks[3].ToDo, blocks[4].ToDo)
+	}
+	if code := blocks[7].Code; code.Language != "go" || plainText(code.RichText) != "fmt.Println()" {
+		t.Errorf("代码块 = %+v", code)
+	}
+	if quote := blocks[8].Quote; plainText(quote.RichText) != "引用" || len(quote.Children) != 1 {
+		t.Errorf("引用 = %+v", quote)
+	}
+	table := blocks[9].Table
+	if table.TableWidth != 2 || !table.HasColumnHeader || len(table.Children) != 3 {
+		t.Fatalf("表格 = %+v", table)
+	}
+	if cells := table.Children[2].TableRow.Cells; len(cells) != 2 || plainText(cells[0]) != "梨" {
+		t.Errorf("短行 = %+v", cells)
+	}
+	if image := blocks[10].Image; image.External.URL != "https://example.com/a.png" || plainText(image.Caption) != "图片" {
+		t.Errorf("图片 = %+v", image)
+	}
+	if expr := blocks[12].Equation.Expression; expr != "E = mc^2" {
+		t.Errorf("公式 = %q", expr)
+	}
+}
+
+func TestToBlocksInline(t *testing.T) {
+	blocks := ToBlocks([]byte("普通 **粗体** *斜体* `代码` ~~删除~~ [链接](https://go.dev) [相对](./a.md)\n下一行"))
+	if len(blocks) != 1 {
+		t.Fatalf("块数 = %d, 期望 1", len(blocks))
+	}
+	rt := blocks[0].Paragraph.RichText
+	if got := plainText(rt); got != "普通 粗体 斜体 代码 删除 链接 相对 下一行" {
+		t.Errorf("纯文本 = %q", got)
+	}
+
+	find := func(content string) notion.RichText {
+		for _, span := range rt {
+			if span.PlainText == content {
+				return span
+			}
+		}
+		t.Fatalf("找不到片段 %q", content)
+		return notion.RichText{}
+	}
+	if a := find("粗体").Annotations; a == nil || !a.Bold {
+		t.Error("粗体缺少注释")
+	}
+	if a := find("斜体").Annotations; a == nil || !a.Italic {
+		t.Error("斜体缺少注释")
+	}
+	if a := find("代码").Annotations; a == nil || !a.Code {
+		t.Error("代码缺少注释")
+	}
+	if a := find("删除").Annotations; a == nil || !a.Strikethrough {
+		t.Error("删除线缺少注释")
+	}
+	if link := find("链接").Text.Link; link == nil || link.URL != "https://go.dev" {
+		t.Errorf("链接 = %+v", link)
+	}
+	for _, span := range rt {
+		if strings.Contains(span.PlainText, "相对") && span.Text.Link != nil {
+			t.Error("相对链接不应保留为 Notion 链接")
+		}
+	}
+}
+
+func TestToBlocksSplitsLongText(t *testing.T) {
+	limit := errors.SizeLimits.MaxRichTextContent
+	long := strings.Repeat("字", limit+10)
+
+	blocks := ToBlocks([]byte("**" + long + "**"))
+	rt := blocks[0].Paragraph.RichText
+	if len(rt) != 2 {
+		t.Fatalf("片段数 = %d, 期望 2", len(rt))
+	}
+	for _, span := range rt {
+		if utf8.RuneCountInString(span.Text.Content) > limit {
+			t.Errorf("片段超过 %d 个字符", limit)
+		}
+		if span.Annotations == nil || !span.Annotations.Bold {
+			t.Error("拆分后的片段应保留样式")
+		}
+	}
+}
+
+func TestMathBlock(t *testing.T) {
+	tests := []struct {
+		src    string
+		want   string
+		blocks int
+	}{
+		{"$$\na\n\nb\n$$", "a\nb", 1},
+		{"$$\na+b\n$$", "a+b", 1},
+		{"$$\na+b\n$$\n", "a+b", 1},
+		{"$$ x^2 $$", "x^2", 1},
+		{"$$\n\\frac{1}{2}\n$$\n后文", "\\frac{1}{2}", 2},
+		{"$$\n未闭合", "未闭合", 1},
+	}
+	for _, tt := range tests {
+		blocks := ToBlocks([]byte(tt.src))
+		if len(blocks) != tt.blocks {
+			t.Errorf("%q: 期望 %d 个块，得到 %d 个", tt.src, tt.blocks, len(blocks))
+		}
+		if len(blocks) == 0 || blocks[0].Type != notion.TypeEquation {
+			t.Errorf("%q: 期望公式块，得到 %+v", tt.src, blocks)
+			continue
+		}
+		if got := blocks[0].Equation.Expression; got != tt.want {
+			t.Errorf("%q: 公式 = %q, 期望 %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestCodeLanguage(t *testing.T) {
+	tests := map[string]string{
+		"":       "plain text",
+		"golang": "go",
+		"Go":     "go",
+		"sh":     "shell",
+		"rust":   "rust",
+		"brainf": "plain text",
+	}
+	for info, want := range tests {
+		if got := codeLanguage(info); got != want {
+			t.Errorf("codeLanguage(%q) = %q, 期望 %q", info, got, want)
+		}
+	}
+}
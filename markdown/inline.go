@@ -0,0 +1,242 @@
+package markdown
+
+import (
+	"strings"
+
+	notion "github.com/kuekiko/NotionGO"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+)
+
+// style 表示行内文本的样式
+type style struct {
+	bold          bool
+	italic        bool
+	strikethrough bool
+	code          bool
+	link          string
+}
+
+// span 表示一段样式相同的文本
+type span struct {
+	content string
+	style   style
+}
+
+// inlineWriter 收集行内文本片段和图片
+type inlineWriter struct {
+	spans  []span
+	images []notion.Block
+}
+
+// add 追加文本，与上一段样式相同时合并
+func (w *inlineWriter) add(content string, st style) {
+	if content == "" {
+		return
+	}
+	if n := len(w.spans); n > 0 && w.spans[n-1].style == st {
+		w.spans[n-1].content += content
+		return
+	}
+	w.spans = append(w.spans, span{content: content, style: st})
+}
+
+// richText 将收集的片段转换为富文本
+func (w *inlineWriter) richText() []notion.RichText {
+	spans := make([]notion.RichText, 0, len(w.spans))
+	for _, s := range w.spans {
+		rt := notion.Plain(s.content)
+		if s.style.bold {
+			rt = rt.Bold()
+		}
+		if s.style.italic {
+			rt = rt.Italic()
+		}
+		if s.style.strikethrough {
+			rt = rt.Strikethrough()
+		}
+		if s.style.code {
+			rt = rt.Code()
+		}
+		if s.style.link != "" {
+			rt = rt.WithLink(s.style.link)
+		}
+		spans = append(spans, rt)
+	}
+	return notion.NewRichText().Append(spans...).Build()
+}
+
+// inline 转换 n 的行内内容，返回富文本和其中的图片块
+func (c *converter) inline(n ast.Node) ([]notion.RichText, []notion.Block) {
+	w := &inlineWriter{}
+	c.walkInline(n, style{}, w)
+	return w.richText(), w.images
+}
+
+// walkInline 按样式遍历 n 的行内子节点
+func (c *converter) walkInline(n ast.Node, st style, w *inlineWriter) {
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		switch child := child.(type) {
+		case *ast.Text:
+			w.add(string(child.Segment.Value(c.source)), st)
+			if child.HardLineBreak() {
+				w.add("\n", st)
+			} else if child.SoftLineBreak() {
+				w.add(" ", st)
+			}
+
+		case *ast.String:
+			w.add(string(child.Value), st)
+
+		case *ast.Emphasis:
+			s := st
+			if child.Level >= 2 {
+				s.bold = true
+			} else {
+				s.italic = true
+			}
+			c.walkInline(child, s, w)
+
+		case *east.Strikethrough:
+			s := st
+			s.strikethrough = true
+			c.walkInline(child, s, w)
+
+		case *ast.CodeSpan:
+			s := st
+			s.code = true
+			w.add(c.plain(child), s)
+
+		case *ast.Link:
+			s := st
+			if dest := string(child.Destination); isLinkable(dest) {
+				s.link = dest
+			}
+			c.walkInline(child, s, w)
+
+		case *ast.AutoLink:
+			s := st
+			if url := string(child.URL(c.source)); isLinkable(url) {
+				s.link = url
+			}
+			w.add(string(child.Label(c.source)), s)
+
+		case *ast.Image:
+			dest := string(child.Destination)
+			alt := c.plain(child)
+			if isHTTP(dest) {
+				image := notion.Image(dest)
+				if alt != "" {
+					image = image.WithCaption(alt)
+				}
+				w.images = append(w.images, image)
+			} else {
+				w.add(alt, st)
+			}
+
+		case *ast.RawHTML:
+			for i := 0; i < child.Segments.Len(); i++ {
+				segment := child.Segments.At(i)
+				w.add(string(segment.Value(c.source)), st)
+			}
+
+		case *east.TaskCheckBox:
+			// 复选框由 listItem 转换为待办事项
+
+		default:
+			c.walkInline(child, st, w)
+		}
+	}
+}
+
+// plain 返回 n 中所有文本的纯文本
+func (c *converter) plain(n ast.Node) string {
+	var sb strings.Builder
+	_ = ast.Walk(n, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := node.(type) {
+		case *ast.Text:
+			sb.Write(node.Segment.Value(c.source))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(node.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return sb.String()
+}
+
+// isHTTP 判断 url 是否为 http 或 https 地址，Notion 只接受这两种外部文件
+func isHTTP(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
+
+// isLinkable 判断 url 是否可以作为 Notion 文本链接，相对链接会被忽略
+func isLinkable(url string) bool {
+	return isHTTP(url) || strings.HasPrefix(url, "mailto:")
+}
+
+// languages 是 Notion 代码块支持的语言
+var languages = map[string]bool{
+	"abap": true, "arduino": true, "bash": true, "basic": true, "c": true, "clojure": true,
+	"coffeescript": true, "c++": true, "c#": true, "css": true, "dart": true, "diff": true,
+	"docker": true, "elixir": true, "elm": true, "erlang": true, "flow": true, "fortran": true,
+	"f#": true, "gherkin": true, "glsl": true, "go": true, "graphql": true, "groovy": true,
+	"haskell": true, "html": true, "java": true, "javascript": true, "json": true, "julia": true,
+	"kotlin": true, "latex": true, "less": true, "lisp": true, "livescript": true, "lua": true,
+	"makefile": true, "markdown": true, "markup": true, "matlab": true, "mermaid": true,
+	"nix": true, "objective-c": true, "ocaml": true, "pascal": true, "perl": true, "php": true,
+	"plain text": true, "powershell": true, "prolog": true, "protobuf": true, "python": true,
+	"r": true, "reason": true, "ruby": true, "rust": true, "sass": true, "scala": true,
+	"scheme": true, "scss": true, "shell": true, "sql": true, "swift": true, "typescript": true,
+	"vb.net": true, "verilog": true, "vhdl": true, "visual basic": true, "webassembly": true,
+	"xml": true, "yaml": true, "java/c/c++/c#": true,
+}
+
+// languageAliases 将常见的代码块语言标记映射为 Notion 的语言名称
+var languageAliases = map[string]string{
+	"golang":     "go",
+	"sh":         "shell",
+	"zsh":        "shell",
+	"console":    "shell",
+	"js":         "javascript",
+	"jsx":        "javascript",
+	"ts":         "typescript",
+	"tsx":        "typescript",
+	"py":         "python",
+	"rb":         "ruby",
+	"rs":         "rust",
+	"yml":        "yaml",
+	"md":         "markdown",
+	"cpp":        "c++",
+	"cc":         "c++",
+	"cs":         "c#",
+	"csharp":     "c#",
+	"fsharp":     "f#",
+	"dockerfile": "docker",
+	"make":       "makefile",
+	"tex":        "latex",
+	"objc":       "objective-c",
+	"kt":         "kotlin",
+	"ps1":        "powershell",
+	"proto":      "protobuf",
+	"text":       "plain text",
+	"txt":        "plain text",
+	"plaintext":  "plain text",
+}
+
+// codeLanguage 将 Markdown 代码块的语言标记转换为 Notion 支持的语言，无法识别时返回 "plain text"
+func codeLanguage(info string) string {
+	lang := strings.ToLower(strings.TrimSpace(info))
+	if alias, ok := languageAliases[lang]; ok {
+		return alias
+	}
+	if languages[lang] {
+		return lang
+	}
+	return "plain text"
+}
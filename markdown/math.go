@@ -0,0 +1,129 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// kindMathBlock 是 $$ 公式块的节点类型
+var kindMathBlock = ast.NewNodeKind("MathBlock")
+
+// mathBlock 表示 $$ 包围的公式块
+type mathBlock struct {
+	ast.BaseBlock
+	expression bytes.Buffer
+	closed     bool
+}
+
+// Kind 实现 ast.Node
+func (n *mathBlock) Kind() ast.NodeKind {
+	return kindMathBlock
+}
+
+// IsRaw 实现 ast.Node
+func (n *mathBlock) IsRaw() bool {
+	return true
+}
+
+// Dump 实现 ast.Node
+func (n *mathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Expression": n.expression.String()}, nil)
+}
+
+// mathBlockParser 解析以 $$ 开始和结束的公式块
+type mathBlockParser struct{}
+
+// Trigger 实现 parser.BlockParser
+func (p mathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Open 实现 parser.BlockParser
+func (p mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], []byte("$$")) {
+		return nil, parser.NoChildren
+	}
+
+	node := &mathBlock{}
+	rest := bytes.TrimSpace(line[pos+2:])
+	if i := bytes.Index(rest, []byte("$$")); i >= 0 {
+		// 单行公式：$$ E = mc^2 $$
+		if !util.IsBlank(rest[i+2:]) {
+			return nil, parser.NoChildren
+		}
+		node.expression.Write(bytes.TrimSpace(rest[:i]))
+		node.closed = true
+	} else {
+		node.expression.Write(rest)
+	}
+	reader.Advance(lineLen(line, segment))
+	return node, parser.NoChildren
+}
+
+// Continue 实现 parser.BlockParser
+func (p mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	n := node.(*mathBlock)
+	if n.closed {
+		return parser.Close
+	}
+	line, segment := reader.PeekLine()
+	if line == nil {
+		return parser.Close
+	}
+
+	trimmed := bytes.TrimSpace(line)
+	end := bytes.HasSuffix(trimmed, []byte("$$"))
+	if end {
+		trimmed = bytes.TrimSpace(trimmed[:len(trimmed)-2])
+	}
+	if len(trimmed) > 0 {
+		if n.expression.Len() > 0 {
+			n.expression.WriteByte('\n')
+		}
+		n.expression.Write(trimmed)
+	}
+	reader.Advance(lineLen(line, segment))
+	if end {
+		n.closed = true
+		return parser.Close
+	}
+	return parser.Continue | parser.NoChildren
+}
+
+// lineLen 返回行中换行符之前的长度，输入的最后一行可能没有换行符
+func lineLen(line []byte, segment text.Segment) int {
+	if bytes.HasSuffix(line, []byte("\n")) {
+		return segment.Len() - 1
+	}
+	return segment.Len()
+}
+
+// Close 实现 parser.BlockParser
+func (p mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+// CanInterruptParagraph 实现 parser.BlockParser
+func (p mathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+// CanAcceptIndentedLine 实现 parser.BlockParser
+func (p mathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// mathExtension 为 goldmark 添加 $$ 公式块
+type mathExtension struct{}
+
+// Extend 实现 goldmark.Extender
+func (e mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithBlockParsers(
+		util.Prioritized(mathBlockParser{}, 150),
+	))
+}
@@ -0,0 +1,135 @@
+package markdown
+
+import (
+	"strings"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// markdownEscaper 转义 Markdown 行内语法使用的字符
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`~`, `\~`,
+)
+
+// escape 转义文本中的 Markdown 语法字符
+func escape(s string) string {
+	s = markdownEscaper.Replace(s)
+	if strings.HasPrefix(s, "#") || strings.HasPrefix(s, "- ") || strings.HasPrefix(s, "+ ") {
+		s = `\` + s
+	}
+	return s
+}
+
+// plain 返回富文本的纯文本
+func plain(rt []notion.RichText) string {
+	return notion.PlainTextOf(rt, nil)
+}
+
+// annotations 返回文本片段的注释，没有注释时返回零值
+func annotations(t notion.RichText) notion.Annotation {
+	if t.Annotations == nil {
+		return notion.Annotation{}
+	}
+	a := *t.Annotations
+	a.Color = "" // Markdown 不支持颜色
+	return a
+}
+
+// Text 将富文本转换为 Markdown 行内文本
+//
+// 粗体、斜体、删除线和行内代码使用 Markdown 语法，下划线使用 <u> 标签，颜色被忽略。
+// 行内公式转换为 $公式$，提及使用 Notion 返回的 plain_text，页面提及链接到页面。
+func Text(rt []notion.RichText) string {
+	return TextWith(rt, nil)
+}
+
+// TextWith 与 Text 相同，提及显示的文本由 resolver 解析，resolver 为 nil 时与 Text 相同
+func TextWith(rt []notion.RichText, resolver notion.MentionResolver) string {
+	var sb strings.Builder
+	for i := 0; i < len(rt); {
+		if rt[i].Equation != nil {
+			sb.WriteString(inlineMath(rt[i].Equation.Expression))
+			i++
+			continue
+		}
+
+		// 合并样式和链接都相同的相邻片段
+		a, url := annotations(rt[i]), rt[i].LinkURL()
+		var text strings.Builder
+		j := i
+		for ; j < len(rt) && rt[j].Equation == nil && annotations(rt[j]) == a && rt[j].LinkURL() == url; j++ {
+			text.WriteString(rt[j].Content(resolver))
+		}
+		sb.WriteString(styled(text.String(), a, url))
+		i = j
+	}
+	return sb.String()
+}
+
+// styled 转换一段样式相同的文本
+func styled(s string, a notion.Annotation, url string) string {
+	if s == "" {
+		return ""
+	}
+
+	// 强调标记不能紧挨空白，把首尾空白移到标记外
+	core := strings.TrimSpace(s)
+	if core == "" {
+		return strings.ReplaceAll(s, "\n", "\\\n")
+	}
+	start := strings.Index(s, core)
+	lead, trail := s[:start], s[start+len(core):]
+
+	var out string
+	if a.Code {
+		out = codeSpan(core)
+	} else {
+		out = strings.ReplaceAll(escape(core), "\n", "\\\n")
+	}
+	if a.Strikethrough {
+		out = "~~" + out + "~~"
+	}
+	if a.Italic {
+		out = "*" + out + "*"
+	}
+	if a.Bold {
+		out = "**" + out + "**"
+	}
+	if a.Underline {
+		out = "<u>" + out + "</u>"
+	}
+	if url != "" {
+		out = "[" + out + "](" + url + ")"
+	}
+	return lead + out + trail
+}
+
+// codeSpan 返回行内代码，反引号数量多于内容中最长的反引号序列
+func codeSpan(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
+
+// inlineMath 返回行内公式，公式中的换行替换为空格
+func inlineMath(expression string) string {
+	expression = strings.TrimSpace(strings.ReplaceAll(expression, "\n", " "))
+	if expression == "" {
+		return ""
+	}
+	return "$" + expression + "$"
+}
@@ -0,0 +1,670 @@
+package notion
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// PropertyMarshaler 由可以转换为属性值的类型实现，Marshal 优先使用它
+type PropertyMarshaler interface {
+	MarshalProperty() (PropertyValue, error)
+}
+
+// PropertyUnmarshaler 由可以从属性值解析的类型实现，Unmarshal 优先使用它
+type PropertyUnmarshaler interface {
+	UnmarshalProperty(v PropertyValue) error
+}
+
+// structField 表示结构体字段与属性的对应关系
+type structField struct {
+	index     []int
+	name      string
+	typ       PropertyType // 为空时 Marshal 根据 Go 类型推断，Unmarshal 使用页面中的类型
+	id        bool         // 字段保存页面 ID
+	omitempty bool
+}
+
+// structFields 缓存每个结构体类型的字段，键为 reflect.Type
+var structFields sync.Map
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	propertyValueType = reflect.TypeOf(PropertyValue{})
+	richTextsType     = reflect.TypeOf([]RichText(nil))
+	filesType         = reflect.TypeOf([]File(nil))
+	dateValueType     = reflect.TypeOf(DateValue{})
+)
+
+// fieldsOf 解析结构体的 notion 标签
+//
+// 标签格式为 `notion:"属性名,类型,omitempty"`，类型和 omitempty 可以省略；
+// `notion:",id"` 表示字段保存页面 ID；没有标签的字段被忽略，匿名结构体字段展开处理。
+func fieldsOf(t reflect.Type) ([]structField, error) {
+	if cached, ok := structFields.Load(t); ok {
+		return cached.([]structField), nil
+	}
+
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("notion")
+		if !ok {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				nested, err := fieldsOf(sf.Type)
+				if err != nil {
+					return nil, err
+				}
+				for _, f := range nested {
+					f.index = append([]int{i}, f.index...)
+					fields = append(fields, f)
+				}
+			}
+			continue
+		}
+		if tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		f := structField{index: []int{i}, name: parts[0]}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		for _, opt := range parts[1:] {
+			switch opt {
+			case "":
+			case "omitempty":
+				f.omitempty = true
+			case "id":
+				f.id = true
+			default:
+				if !knownPropertyType(PropertyType(opt)) {
+					return nil, errors.NewError(errors.ErrInvalidInput,
+						fmt.Sprintf("字段 %s.%s 的属性类型 %q 无效", t.Name(), sf.Name, opt), 0)
+				}
+				f.typ = PropertyType(opt)
+			}
+		}
+		if f.id && sf.Type.Kind() != reflect.String {
+			return nil, errors.NewError(errors.ErrInvalidInput,
+				fmt.Sprintf("字段 %s.%s 保存页面 ID，类型必须是 string", t.Name(), sf.Name), 0)
+		}
+		fields = append(fields, f)
+	}
+
+	structFields.Store(t, fields)
+	return fields, nil
+}
+
+// knownPropertyType 判断是否是 Notion 的属性类型
+func knownPropertyType(typ PropertyType) bool {
+	switch typ {
+	case PropertyTypeTitle, PropertyTypeRichText, PropertyTypeNumber, PropertyTypeSelect,
+		PropertyTypeMultiSelect, PropertyTypeStatus, PropertyTypeDate, PropertyTypePeople,
+		PropertyTypeFiles, PropertyTypeCheckbox, PropertyTypeURL, PropertyTypeEmail,
+		PropertyTypePhoneNumber, PropertyTypeFormula, PropertyTypeRelation, PropertyTypeRollup,
+		PropertyTypeCreatedTime, PropertyTypeCreatedBy, PropertyTypeLastEditedTime,
+		PropertyTypeLastEditedBy, PropertyTypeUniqueID, PropertyTypeVerification:
+		return true
+	}
+	return false
+}
+
+// readOnlyProperty 判断属性是否由 Notion 计算，不能写入
+func readOnlyProperty(typ PropertyType) bool {
+	switch typ {
+	case PropertyTypeFormula, PropertyTypeRollup, PropertyTypeCreatedTime, PropertyTypeCreatedBy,
+		PropertyTypeLastEditedTime, PropertyTypeLastEditedBy, PropertyTypeUniqueID, PropertyTypeVerification:
+		return true
+	}
+	return false
+}
+
+// structValue 返回 v 指向的结构体
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return rv, errors.NewError(errors.ErrInvalidInput, "参数不能是 nil 指针", 0)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return rv, errors.NewError(errors.ErrInvalidInput, fmt.Sprintf("参数必须是结构体或结构体指针，不是 %T", v), 0)
+	}
+	return rv, nil
+}
+
+// RowID 返回 row 中 `notion:",id"` 字段保存的页面 ID，字段不存在或为空时返回 ErrInvalidInput
+func RowID(row interface{}) (string, error) {
+	rv, err := structValue(row)
+	if err != nil {
+		return "", err
+	}
+	fields, err := fieldsOf(rv.Type())
+	if err != nil {
+		return "", err
+	}
+	for _, f := range fields {
+		if f.id {
+			if id := rv.FieldByIndex(f.index).String(); id != "" {
+				return id, nil
+			}
+			return "", errors.NewError(errors.ErrInvalidInput, "行的页面 ID 为空", 0)
+		}
+	}
+	return "", errors.NewError(errors.ErrInvalidInput,
+		fmt.Sprintf("%s 没有 `notion:\",id\"` 字段", reflect.TypeOf(row)), 0)
+}
+
+// Marshal 将带 notion 标签的结构体转换为页面属性，用于创建和更新页面
+//
+//	type Task struct {
+//		ID    string    `notion:",id"`
+//		Name  string    `notion:"名称,title"`
+//		Done  bool      `notion:"完成"`
+//		Tags  []string  `notion:"标签,multi_select"`
+//		Due   time.Time `notion:"截止日期,date,omitempty"`
+//	}
+//
+// 没有声明类型时根据 Go 类型推断：字符串为 rich_text，数字为 number，bool 为 checkbox，
+// time.Time 为 date，[]string 为 multi_select。字符串类字段也可以用于 select、status、url、email 和
+// phone_number，[]string 可以用于 people 和 relation（用户或页面 ID）。nil 指针和零值 time.Time 清空属性，
+// omitempty 跳过零值字段；页面 ID 和只读属性（公式、汇总、创建时间等）不会写入。
+func Marshal(v interface{}) (Properties, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := fieldsOf(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	props := Properties{}
+	for _, f := range fields {
+		if f.id || readOnlyProperty(f.typ) {
+			continue
+		}
+		fv := rv.FieldByIndex(f.index)
+		if f.omitempty && fv.IsZero() {
+			continue
+		}
+		pv, err := marshalValue(fv, f.typ)
+		if err != nil {
+			return nil, errors.NewError(errors.ErrInvalidInput, fmt.Sprintf("属性 %q: %v", f.name, err), 0)
+		}
+		if readOnlyProperty(pv.Type) {
+			continue
+		}
+		props[f.name] = pv
+	}
+	return props, nil
+}
+
+// marshalValue 将字段值转换为属性值
+func marshalValue(fv reflect.Value, typ PropertyType) (PropertyValue, error) {
+	// nil 指针清空属性，不调用 MarshalProperty，值接收者的方法无法通过 nil 指针调用
+	if fv.Kind() == reflect.Ptr && fv.IsNil() {
+		if typ == "" {
+			typ = inferType(fv.Type().Elem())
+		}
+		if typ == "" {
+			return PropertyValue{}, fmt.Errorf("无法根据 %s 推断属性类型", fv.Type())
+		}
+		return PropertyValue{Type: typ}, nil
+	}
+	m, ok := fv.Interface().(PropertyMarshaler)
+	if !ok && fv.CanAddr() {
+		m, ok = fv.Addr().Interface().(PropertyMarshaler)
+	}
+	if ok {
+		pv, err := m.MarshalProperty()
+		if pv.Type == "" {
+			pv.Type = typ
+		}
+		return pv, err
+	}
+	if fv.Type() == propertyValueType {
+		return fv.Interface().(PropertyValue), nil
+	}
+	if fv.Kind() == reflect.Ptr {
+		return marshalValue(fv.Elem(), typ)
+	}
+	if typ == "" {
+		typ = inferType(fv.Type())
+	}
+
+	switch typ {
+	case PropertyTypeTitle, PropertyTypeRichText:
+		var rt []RichText
+		if fv.Type() == richTextsType {
+			rt = fv.Interface().([]RichText)
+		} else if s, ok := stringOf(fv); ok && s != "" {
+			rt = textRichText(s)
+		} else if !ok {
+			break
+		}
+		if typ == PropertyTypeTitle {
+			return PropertyValue{Type: typ, Title: nonNilRichText(rt)}, nil
+		}
+		return PropertyValue{Type: typ, RichText: nonNilRichText(rt)}, nil
+	case PropertyTypeNumber:
+		if n, ok := numberOf(fv); ok {
+			return NewNumberValue(n), nil
+		}
+	case PropertyTypeCheckbox:
+		if fv.Kind() == reflect.Bool {
+			return NewCheckboxValue(fv.Bool()), nil
+		}
+	case PropertyTypeSelect, PropertyTypeStatus:
+		if s, ok := stringOf(fv); ok {
+			if s == "" {
+				return PropertyValue{Type: typ}, nil
+			}
+			if typ == PropertyTypeStatus {
+				return NewStatusValue(s), nil
+			}
+			return NewSelectValue(s), nil
+		}
+	case PropertyTypeMultiSelect, PropertyTypePeople, PropertyTypeRelation:
+		if names, ok := stringsOf(fv); ok {
+			switch typ {
+			case PropertyTypePeople:
+				return NewPeopleValue(names...), nil
+			case PropertyTypeRelation:
+				return NewRelationValue(names...), nil
+			}
+			return NewMultiSelectValue(names...), nil
+		}
+	case PropertyTypeDate:
+		switch {
+		case fv.Type() == timeType:
+			t := fv.Interface().(time.Time)
+			if t.IsZero() {
+				return PropertyValue{Type: typ}, nil
+			}
+			return NewDateTimeValue(t), nil
+		case fv.Type() == dateValueType:
+			date := fv.Interface().(DateValue)
+			return PropertyValue{Type: typ, Date: &date}, nil
+		}
+		if s, ok := stringOf(fv); ok {
+			if s == "" {
+				return PropertyValue{Type: typ}, nil
+			}
+			return NewDateValue(s, ""), nil
+		}
+	case PropertyTypeURL, PropertyTypeEmail, PropertyTypePhoneNumber:
+		if s, ok := stringOf(fv); ok {
+			return PropertyValue{Type: typ}.withString(s), nil
+		}
+	case PropertyTypeFiles:
+		if fv.Type() == filesType {
+			return NewFilesValue(fv.Interface().([]File)...), nil
+		}
+	case PropertyTypeFormula, PropertyTypeRollup, PropertyTypeCreatedTime, PropertyTypeCreatedBy,
+		PropertyTypeLastEditedTime, PropertyTypeLastEditedBy, PropertyTypeUniqueID, PropertyTypeVerification:
+		// 只读属性由 Marshal 跳过
+		return PropertyValue{Type: typ}, nil
+	case "":
+		return PropertyValue{}, fmt.Errorf("无法根据 %s 推断属性类型", fv.Type())
+	}
+	return PropertyValue{}, fmt.Errorf("%s 不能转换为 %s 属性", fv.Type(), typ)
+}
+
+// withString 设置 URL、邮箱或电话属性的值，空字符串清空属性
+func (v PropertyValue) withString(s string) PropertyValue {
+	switch v.Type {
+	case PropertyTypeURL:
+		v.URL = optionalString(s)
+	case PropertyTypeEmail:
+		v.Email = optionalString(s)
+	case PropertyTypePhoneNumber:
+		v.PhoneNumber = optionalString(s)
+	}
+	return v
+}
+
+// inferType 根据 Go 类型推断属性类型
+func inferType(t reflect.Type) PropertyType {
+	switch {
+	case t == timeType || t == dateValueType:
+		return PropertyTypeDate
+	case t == richTextsType:
+		return PropertyTypeRichText
+	case t == filesType:
+		return PropertyTypeFiles
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return PropertyTypeRichText
+	case reflect.Bool:
+		return PropertyTypeCheckbox
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return PropertyTypeNumber
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String {
+			return PropertyTypeMultiSelect
+		}
+	case reflect.Ptr:
+		return inferType(t.Elem())
+	}
+	return ""
+}
+
+// stringOf 返回字符串类字段的值
+func stringOf(fv reflect.Value) (string, bool) {
+	if fv.Kind() != reflect.String {
+		return "", false
+	}
+	return fv.String(), true
+}
+
+// stringsOf 返回字符串切片字段的值
+func stringsOf(fv reflect.Value) ([]string, bool) {
+	if fv.Kind() != reflect.Slice || fv.Type().Elem().Kind() != reflect.String {
+		return nil, false
+	}
+	values := make([]string, fv.Len())
+	for i := range values {
+		values[i] = fv.Index(i).String()
+	}
+	return values, true
+}
+
+// numberOf 返回数字字段的值
+func numberOf(fv reflect.Value) (float64, bool) {
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(fv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(fv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return fv.Float(), true
+	}
+	return 0, false
+}
+
+// Unmarshal 将页面属性解析到带 notion 标签的结构体，v 必须是结构体指针
+//
+// 标签规则见 Marshal。页面中不存在的属性保持字段不变；标签声明的类型与页面中的类型不同时返回
+// ErrPropertyTypeMismatch。除可写属性外，字段还可以读取只读属性：创建和编辑时间可以解析为 time.Time，
+// 创建者和编辑者为用户 ID，唯一 ID 可以解析为数字或带前缀的字符串，公式按结果类型解析。
+func Unmarshal(page *Page, v interface{}) error {
+	if page == nil {
+		return errors.NewError(errors.ErrInvalidInput, "页面不能为 nil", 0)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.NewError(errors.ErrInvalidInput, fmt.Sprintf("参数必须是非 nil 的结构体指针，不是 %T", v), 0)
+	}
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	fields, err := fieldsOf(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		fv := rv.FieldByIndex(f.index)
+		if f.id {
+			fv.SetString(page.ID)
+			continue
+		}
+		pv, ok := page.Properties[f.name]
+		if !ok {
+			continue
+		}
+		if f.typ != "" && pv.Type != f.typ {
+			return errors.NewError(errors.ErrPropertyTypeMismatch,
+				fmt.Sprintf("属性 %q 的类型是 %s，不是 %s", f.name, pv.Type, f.typ), 0)
+		}
+		if err := unmarshalValue(pv, fv); err != nil {
+			return errors.NewError(errors.ErrPropertyTypeMismatch, fmt.Sprintf("属性 %q: %v", f.name, err), 0)
+		}
+	}
+	return nil
+}
+
+// unmarshalValue 将属性值写入字段
+func unmarshalValue(pv PropertyValue, fv reflect.Value) error {
+	if u, ok := fv.Addr().Interface().(PropertyUnmarshaler); ok {
+		return u.UnmarshalProperty(pv)
+	}
+	switch fv.Type() {
+	case propertyValueType:
+		fv.Set(reflect.ValueOf(pv))
+		return nil
+	case richTextsType:
+		rt := pv.Title
+		if pv.Type == PropertyTypeRichText {
+			rt = pv.RichText
+		}
+		fv.Set(reflect.ValueOf(rt))
+		return nil
+	case filesType:
+		fv.Set(reflect.ValueOf(pv.Files))
+		return nil
+	case dateValueType:
+		if date := pv.date(); date != nil {
+			fv.Set(reflect.ValueOf(*date))
+		} else {
+			fv.Set(reflect.Zero(fv.Type()))
+		}
+		return nil
+	case timeType:
+		t, err := pv.time()
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Ptr:
+		if pv.isEmpty() {
+			fv.Set(reflect.Zero(fv.Type()))
+			return nil
+		}
+		ptr := reflect.New(fv.Type().Elem())
+		if err := unmarshalValue(pv, ptr.Elem()); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+		return nil
+	case reflect.String:
+		fv.SetString(pv.text())
+		return nil
+	case reflect.Bool:
+		switch {
+		case pv.Type == PropertyTypeCheckbox:
+			fv.SetBool(pv.Checkbox)
+			return nil
+		case pv.Type == PropertyTypeFormula && pv.Formula != nil:
+			fv.SetBool(pv.Formula.Boolean != nil && *pv.Formula.Boolean)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := pv.number(); ok {
+			fv.SetInt(int64(n))
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := pv.number(); ok {
+			fv.SetUint(uint64(n))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, ok := pv.number(); ok {
+			fv.SetFloat(n)
+			return nil
+		}
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() == reflect.String {
+			values := pv.list()
+			if values == nil && !pv.isList() {
+				break
+			}
+			out := reflect.MakeSlice(fv.Type(), len(values), len(values))
+			for i, s := range values {
+				out.Index(i).SetString(s)
+			}
+			fv.Set(out)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s 属性不能解析为 %s", pv.Type, fv.Type())
+}
+
+// text 返回属性值的文本，人员类属性返回用户 ID，公式返回结果的文本
+func (v PropertyValue) text() string {
+	switch v.Type {
+	case PropertyTypeCreatedBy:
+		if v.CreatedBy != nil {
+			return v.CreatedBy.ID
+		}
+	case PropertyTypeLastEditedBy:
+		if v.LastEditedBy != nil {
+			return v.LastEditedBy.ID
+		}
+	case PropertyTypeFormula:
+		if v.Formula == nil {
+			return ""
+		}
+		switch {
+		case v.Formula.String != nil:
+			return *v.Formula.String
+		case v.Formula.Number != nil:
+			return formatNumber(*v.Formula.Number)
+		case v.Formula.Boolean != nil:
+			return fmt.Sprint(*v.Formula.Boolean)
+		case v.Formula.Date != nil:
+			return v.Formula.Date.Start
+		}
+	}
+	return v.PlainText()
+}
+
+// number 返回数字、唯一 ID、公式和汇总的数值，空值为 0
+func (v PropertyValue) number() (float64, bool) {
+	switch v.Type {
+	case PropertyTypeNumber:
+		if v.Number != nil {
+			return *v.Number, true
+		}
+		return 0, true
+	case PropertyTypeUniqueID:
+		if v.UniqueID != nil {
+			return float64(v.UniqueID.Number), true
+		}
+		return 0, true
+	case PropertyTypeFormula:
+		if v.Formula != nil && v.Formula.Number != nil {
+			return *v.Formula.Number, true
+		}
+		return 0, v.Formula == nil || v.Formula.Type == "number"
+	case PropertyTypeRollup:
+		if v.Rollup != nil && v.Rollup.Number != nil {
+			return *v.Rollup.Number, true
+		}
+		return 0, v.Rollup == nil || v.Rollup.Type == "number"
+	}
+	return 0, false
+}
+
+// date 返回日期、公式和汇总中的日期
+func (v PropertyValue) date() *DateValue {
+	switch v.Type {
+	case PropertyTypeDate:
+		return v.Date
+	case PropertyTypeFormula:
+		if v.Formula != nil {
+			return v.Formula.Date
+		}
+	case PropertyTypeRollup:
+		if v.Rollup != nil {
+			return v.Rollup.Date
+		}
+	}
+	return nil
+}
+
+// time 返回日期类属性的开始时间，空值为零值
+func (v PropertyValue) time() (time.Time, error) {
+	s := ""
+	switch v.Type {
+	case PropertyTypeCreatedTime:
+		s = v.CreatedTime
+	case PropertyTypeLastEditedTime:
+		s = v.LastEditedTime
+	default:
+		if date := v.date(); date != nil {
+			s = date.Start
+		} else if v.Type != PropertyTypeDate && v.Type != PropertyTypeFormula && v.Type != PropertyTypeRollup {
+			return time.Time{}, fmt.Errorf("%s 属性不能解析为 time.Time", v.Type)
+		}
+	}
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return parseNotionTime(s)
+}
+
+// isList 判断属性值是否是列表
+func (v PropertyValue) isList() bool {
+	switch v.Type {
+	case PropertyTypeMultiSelect, PropertyTypePeople, PropertyTypeRelation:
+		return true
+	}
+	return false
+}
+
+// list 返回多选的选项名称，人员和关联的 ID
+func (v PropertyValue) list() []string {
+	var values []string
+	switch v.Type {
+	case PropertyTypeMultiSelect:
+		for _, o := range v.MultiSelect {
+			values = append(values, o.Name)
+		}
+	case PropertyTypePeople:
+		for _, u := range v.People {
+			values = append(values, u.ID)
+		}
+	case PropertyTypeRelation:
+		for _, r := range v.Relation {
+			values = append(values, r.ID)
+		}
+	}
+	return values
+}
+
+// isEmpty 判断属性值是否为空，用于指针字段
+func (v PropertyValue) isEmpty() bool {
+	switch v.Type {
+	case PropertyTypeCheckbox:
+		return false
+	case PropertyTypeNumber:
+		return v.Number == nil
+	case PropertyTypeFormula:
+		return v.Formula == nil || v.text() == ""
+	case PropertyTypeRollup:
+		return v.Rollup == nil
+	case PropertyTypeMultiSelect, PropertyTypePeople, PropertyTypeRelation:
+		return len(v.list()) == 0
+	case PropertyTypeFiles:
+		return len(v.Files) == 0
+	}
+	return v.text() == ""
+}
@@ -0,0 +1,234 @@
+package notion
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// priority 是自定义的属性类型，保存为 select 选项
+type priority int
+
+func (p priority) MarshalProperty() (PropertyValue, error) {
+	return NewSelectValue("P" + strings.Repeat("!", int(p))), nil
+}
+
+func (p *priority) UnmarshalProperty(v PropertyValue) error {
+	if v.Select == nil {
+		*p = 0
+		return nil
+	}
+	*p = priority(strings.Count(v.Select.Name, "!"))
+	return nil
+}
+
+type testTask struct {
+	ID       string    `notion:",id"`
+	Name     string    `notion:"Name,title"`
+	Progress float64   `notion:"完成度"`
+	Status   string    `notion:"状态,status"`
+	Tags     []string  `notion:"标签,multi_select"`
+	Due      time.Time `notion:"截止日期,date"`
+	Done     bool      `notion:"完成"`
+	Link     *string   `notion:"链接,url"`
+	Related  []string  `notion:"关联,relation"`
+	Formula  int       `notion:"公式,formula"`
+	Number   string    `notion:"编号,unique_id"`
+	Created  time.Time `notion:"创建时间,created_time"`
+	Creator  string    `notion:"创建者,created_by"`
+	Missing  string    `notion:"不存在"`
+	Ignored  string    `notion:"-"`
+	internal string
+}
+
+func TestUnmarshal(t *testing.T) {
+	var page Page
+	if err := json.Unmarshal([]byte(testPageJSON), &page); err != nil {
+		t.Fatalf("解码页面失败: %v", err)
+	}
+	task := testTask{Missing: "保留", Ignored: "保留"}
+	if err := Unmarshal(&page, &task); err != nil {
+		t.Fatalf("Unmarshal 失败: %v", err)
+	}
+
+	link := "https://example.com"
+	want := testTask{
+		ID:       "page-1",
+		Name:     "任务",
+		Progress: 0.3,
+		Status:   "进行中",
+		Tags:     []string{"go", "sdk"},
+		Due:      time.Date(2024, 12, 8, 0, 0, 0, 0, time.UTC),
+		Done:     true,
+		Link:     &link,
+		Related:  []string{"page-2"},
+		Formula:  42,
+		Number:   "TASK-12",
+		Created:  time.Date(2024, 12, 8, 10, 0, 0, 0, time.UTC),
+		Creator:  "user-1",
+		Missing:  "保留",
+		Ignored:  "保留",
+	}
+	if !reflect.DeepEqual(task, want) {
+		t.Errorf("Unmarshal 结果 = %+v，期望 %+v", task, want)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var page Page
+	if err := json.Unmarshal([]byte(testPageJSON), &page); err != nil {
+		t.Fatalf("解码页面失败: %v", err)
+	}
+
+	var wrongType struct {
+		Name string `notion:"Name,rich_text"`
+	}
+	if err := Unmarshal(&page, &wrongType); !isCode(err, errors.ErrPropertyTypeMismatch) {
+		t.Errorf("类型不匹配时应返回 ErrPropertyTypeMismatch，实际为 %v", err)
+	}
+
+	var wrongField struct {
+		Done []string `notion:"完成"`
+	}
+	if err := Unmarshal(&page, &wrongField); !isCode(err, errors.ErrPropertyTypeMismatch) {
+		t.Errorf("字段类型不支持时应返回 ErrPropertyTypeMismatch，实际为 %v", err)
+	}
+
+	var task testTask
+	if err := Unmarshal(&page, task); !isCode(err, errors.ErrInvalidInput) {
+		t.Errorf("非指针参数应返回 ErrInvalidInput，实际为 %v", err)
+	}
+
+	var badTag struct {
+		Name string `notion:"Name,text"`
+	}
+	if err := Unmarshal(&page, &badTag); !isCode(err, errors.ErrInvalidInput) {
+		t.Errorf("无效的属性类型应返回 ErrInvalidInput，实际为 %v", err)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	type row struct {
+		ID       string    `notion:",id"`
+		Name     string    `notion:"名称,title"`
+		Count    int       `notion:"数量"`
+		Done     bool      `notion:"完成"`
+		Tags     []string  `notion:"标签"`
+		Owners   []string  `notion:"负责人,people"`
+		Due      time.Time `notion:"截止日期"`
+		Day      string    `notion:"日期,date"`
+		Email    string    `notion:"邮箱,email"`
+		Note     *string   `notion:"备注"`
+		Level    priority  `notion:"优先级,select"`
+		Skipped  string    `notion:"跳过,omitempty"`
+		Created  time.Time `notion:"创建时间,created_time"`
+		Computed PropertyValue
+	}
+	due := time.Date(2024, 12, 8, 9, 30, 0, 0, time.UTC)
+	props, err := Marshal(&row{
+		ID:     "page-1",
+		Name:   "任务",
+		Count:  3,
+		Done:   true,
+		Tags:   []string{"go"},
+		Owners: []string{"user-1"},
+		Due:    due,
+		Day:    "2024-12-09",
+		Level:  2,
+	})
+	if err != nil {
+		t.Fatalf("Marshal 失败: %v", err)
+	}
+
+	want := Properties{
+		"名称":   NewTitleValue("任务"),
+		"数量":   NewNumberValue(3),
+		"完成":   NewCheckboxValue(true),
+		"标签":   NewMultiSelectValue("go"),
+		"负责人":  NewPeopleValue("user-1"),
+		"截止日期": NewDateTimeValue(due),
+		"日期":   NewDateValue("2024-12-09", ""),
+		"邮箱":   {Type: PropertyTypeEmail},
+		"备注":   {Type: PropertyTypeRichText, RichText: []RichText{}},
+		"优先级":  NewSelectValue("P!!"),
+	}
+	if len(props) != len(want) {
+		t.Errorf("Marshal 返回 %d 个属性，期望 %d 个", len(props), len(want))
+	}
+	for name, value := range want {
+		got, _ := json.Marshal(props[name])
+		expected, _ := json.Marshal(value)
+		if string(got) != string(expected) {
+			t.Errorf("属性 %s = %s，期望 %s", name, got, expected)
+		}
+	}
+	for _, name := range []string{"跳过", "创建时间", "Computed", "ID"} {
+		if _, ok := props[name]; ok {
+			t.Errorf("属性 %s 不应写入", name)
+		}
+	}
+}
+
+func TestMarshalNilMarshalerPointer(t *testing.T) {
+	// priority 的 MarshalProperty 是值接收者，nil 指针不能调用它
+	type row struct {
+		Level *priority `notion:"优先级,select"`
+	}
+	props, err := Marshal(&row{})
+	if err != nil {
+		t.Fatalf("Marshal 失败: %v", err)
+	}
+	got, _ := json.Marshal(props["优先级"])
+	want, _ := json.Marshal(PropertyValue{Type: PropertyTypeSelect})
+	if string(got) != string(want) {
+		t.Errorf("nil 指针应清空属性，实际为 %s", got)
+	}
+
+	level := priority(1)
+	props, err = Marshal(&row{Level: &level})
+	if err != nil || props["优先级"].Select == nil || props["优先级"].Select.Name != "P!" {
+		t.Errorf("非 nil 指针应调用 MarshalProperty，实际为 %+v, %v", props["优先级"], err)
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	type row struct {
+		Name  string   `notion:"名称,title"`
+		Level priority `notion:"优先级,select"`
+		Score *float64 `notion:"分数"`
+	}
+	score := 9.5
+	props, err := Marshal(row{Name: "任务", Level: 1, Score: &score})
+	if err != nil {
+		t.Fatalf("Marshal 失败: %v", err)
+	}
+	var got row
+	if err := Unmarshal(&Page{Properties: props}, &got); err != nil {
+		t.Fatalf("Unmarshal 失败: %v", err)
+	}
+	if got.Name != "任务" || got.Level != 1 || got.Score == nil || *got.Score != score {
+		t.Errorf("往返结果 = %+v", got)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	if _, err := Marshal("任务"); !isCode(err, errors.ErrInvalidInput) {
+		t.Errorf("非结构体参数应返回 ErrInvalidInput，实际为 %v", err)
+	}
+	var badField struct {
+		Done bool `notion:"完成,number"`
+	}
+	if _, err := Marshal(badField); !isCode(err, errors.ErrInvalidInput) {
+		t.Errorf("字段类型与属性类型不符时应返回 ErrInvalidInput，实际为 %v", err)
+	}
+	var noType struct {
+		Data map[string]string `notion:"数据"`
+	}
+	if _, err := Marshal(noType); !isCode(err, errors.ErrInvalidInput) {
+		t.Errorf("无法推断属性类型时应返回 ErrInvalidInput，实际为 %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package notion
 
 import (
+	"context"
+
 	"github.com/kuekiko/NotionGO/client"
 )
 
@@ -18,9 +20,14 @@ type Client struct {
 }
 
 // NewClient 创建 Notion API 客户端
-func NewClient(token string) *Client {
+func NewClient(token string, opts ...ClientOption) *Client {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	c := &Client{
-		client: client.NewClient(token),
+		client: client.NewClient(token, o.clientOptions...),
 	}
 
 	c.Blocks = NewBlockService(c)
@@ -33,22 +40,47 @@ func NewClient(token string) *Client {
 	return c
 }
 
+// SetRetryPolicy 设置所有服务共用的重试策略，传入 nil 时恢复默认策略
+func (c *Client) SetRetryPolicy(policy client.RetryPolicy) {
+	c.client.SetRetryPolicy(policy)
+}
+
+// ForgetToken 丢弃通过 ContextWithToken 使用过的 token 的速率限制器，用于令牌被撤销之后
+func (c *Client) ForgetToken(token string) {
+	c.client.ForgetToken(token)
+}
+
+// ContextWithToken 返回使用 token 调用 API 的 ctx，用于一个 Client 访问多个工作区
+//
+// 每个令牌使用独立的速率限制器和缓存键，连接池和服务仍然共用：
+//
+//	ctx := notion.ContextWithToken(ctx, workspaceToken)
+//	page, err := c.Pages.Get(ctx, pageID)
+func ContextWithToken(ctx context.Context, token string) context.Context {
+	return client.ContextWithToken(ctx, token)
+}
+
+// ContextWithAPIVersion 返回使用 version 作为 Notion-Version 请求头的 ctx
+func ContextWithAPIVersion(ctx context.Context, version string) context.Context {
+	return client.ContextWithAPIVersion(ctx, version)
+}
+
 // get 发送 GET 请求
-func (c *Client) get(path string, params interface{}, v interface{}) error {
-	return c.client.Get(path, params, v)
+func (c *Client) get(ctx context.Context, path string, params interface{}, v interface{}) error {
+	return c.client.Get(ctx, path, params, v)
 }
 
 // post 发送 POST 请求
-func (c *Client) post(path string, body interface{}, v interface{}) error {
-	return c.client.Post(path, body, v)
+func (c *Client) post(ctx context.Context, path string, body interface{}, v interface{}) error {
+	return c.client.Post(ctx, path, body, v)
 }
 
 // patch 发送 PATCH 请求
-func (c *Client) patch(path string, body interface{}, v interface{}) error {
-	return c.client.Patch(path, body, v)
+func (c *Client) patch(ctx context.Context, path string, body interface{}, v interface{}) error {
+	return c.client.Patch(ctx, path, body, v)
 }
 
 // delete 发送 DELETE 请求
-func (c *Client) delete(path string) error {
-	return c.client.Delete(path)
+func (c *Client) delete(ctx context.Context, path string) error {
+	return c.client.Delete(ctx, path)
 }
@@ -0,0 +1,296 @@
+package notiontest
+
+import (
+	"net/url"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// maxChildren 是一次请求中每个数组最多包含的块数
+const maxChildren = 100
+
+// getBlock 处理 GET /blocks/{id}
+func (s *Server) getBlock(id string) (interface{}, error) {
+	b, ok := s.blocks[key(id)]
+	if !ok {
+		return nil, notFound("block", id)
+	}
+	return s.blockJSON(b), nil
+}
+
+// updateBlock 处理 PATCH /blocks/{id}，合并块类型对应的字段
+func (s *Server) updateBlock(id string, req map[string]interface{}) (interface{}, error) {
+	b, ok := s.blocks[key(id)]
+	if !ok {
+		return nil, notFound("block", id)
+	}
+	typ, _ := b["type"].(string)
+	for k, v := range req {
+		switch k {
+		case "archived", "in_trash":
+			if archived, ok := v.(bool); ok {
+				s.setArchived(b["id"].(string), archived)
+			}
+		case typ:
+			update, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, invalid("body.%s should be an object.", typ)
+			}
+			content, _ := b[typ].(map[string]interface{})
+			if content == nil {
+				content = map[string]interface{}{}
+				b[typ] = content
+			}
+			for field, value := range update {
+				if field != "children" {
+					content[field] = value
+				}
+			}
+		case "type":
+		default:
+			if _, ok := v.(map[string]interface{}); ok {
+				return nil, invalid("Block type %s does not match existing block type %s.", k, typ)
+			}
+		}
+	}
+	s.touchBlock(b)
+	return s.blockJSON(b), nil
+}
+
+// archiveBlock 处理 DELETE /blocks/{id}
+func (s *Server) archiveBlock(id string) (interface{}, error) {
+	b, ok := s.blocks[key(id)]
+	if !ok {
+		return nil, notFound("block", id)
+	}
+	s.setArchived(b["id"].(string), true)
+	s.touchBlock(b)
+	return s.blockJSON(b), nil
+}
+
+// listChildren 处理 GET /blocks/{id}/children，不包含已归档的子块
+func (s *Server) listChildren(id string, query url.Values) (interface{}, error) {
+	if _, ok := s.blocks[key(id)]; !ok {
+		return nil, notFound("block", id)
+	}
+	var results []interface{}
+	for _, child := range s.visibleChildren(key(id)) {
+		results = append(results, s.blockJSON(child))
+	}
+	cursor, size := queryPage(query)
+	resp := paginate(results, cursor, size)
+	resp["type"] = "block"
+	resp["block"] = map[string]interface{}{}
+	return resp, nil
+}
+
+// appendChildren 处理 PATCH /blocks/{id}/children，返回新建的顶层块
+func (s *Server) appendChildren(id string, req map[string]interface{}) (interface{}, error) {
+	parent, ok := s.blocks[key(id)]
+	if !ok {
+		return nil, notFound("block", id)
+	}
+	children, ok := req["children"].([]interface{})
+	if !ok {
+		return nil, invalid("body.children should be defined, instead was `undefined`.")
+	}
+	after, _ := req["after"].(string)
+	if after != "" && !s.hasChild(key(id), after) {
+		return nil, invalid("body.after should be a child of the parent block.")
+	}
+	if err := checkBlocks(children, 1); err != nil {
+		return nil, err
+	}
+
+	created := s.insertBlocks(parent["id"].(string), children, after, false)
+	s.touchBlock(parent)
+	results := make([]interface{}, len(created))
+	for i, b := range created {
+		results[i] = s.blockJSON(b)
+	}
+	resp := paginate(results, "", len(results))
+	resp["type"] = "block"
+	resp["block"] = map[string]interface{}{}
+	return resp, nil
+}
+
+// checkBlocks 检查要创建的块：每个数组最多 100 个，嵌套最多两层，分栏和表格的子块不计入层数
+func checkBlocks(blocks []interface{}, level int) error {
+	if len(blocks) > maxChildren {
+		return invalid("body.children.length should be ≤ `%d`, instead was `%d`.", maxChildren, len(blocks))
+	}
+	if level > 2 && len(blocks) > 0 {
+		return invalid("body.children exceeds the maximum of two levels of nesting.")
+	}
+	for _, item := range blocks {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return invalid("body.children should be an array of block objects.")
+		}
+		typ := blockType(m)
+		if typ == "" {
+			return invalid("body.children contains a block without a type.")
+		}
+		content, _ := m[typ].(map[string]interface{})
+		nested, _ := content["children"].([]interface{})
+		next := level + 1
+		if typ == string(notion.TypeColumnList) || typ == string(notion.TypeColumn) || typ == string(notion.TypeTable) {
+			next = level
+		}
+		if err := checkBlocks(nested, next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockType 返回块的类型，缺少 type 字段时使用唯一的对象字段
+func blockType(m map[string]interface{}) string {
+	if typ, ok := m["type"].(string); ok && typ != "" {
+		return typ
+	}
+	for k, v := range m {
+		if _, ok := v.(map[string]interface{}); ok && k != "parent" && k != "created_by" && k != "last_edited_by" {
+			return k
+		}
+	}
+	return ""
+}
+
+// insertBlocks 在 parentID 的子块中 after 之后插入块及其嵌套的子块，after 为空时追加到末尾
+//
+// keepIDs 为 true 时使用块中未被占用的 ID，用于预置数据。
+func (s *Server) insertBlocks(parentID string, blocks []interface{}, after string, keepIDs bool) []map[string]interface{} {
+	created := make([]map[string]interface{}, 0, len(blocks))
+	ids := make([]string, 0, len(blocks))
+	for _, item := range blocks {
+		m := item.(map[string]interface{})
+		typ := blockType(m)
+		content, _ := m[typ].(map[string]interface{})
+		if content == nil {
+			content = map[string]interface{}{}
+		}
+		nested, _ := content["children"].([]interface{})
+		delete(content, "children")
+
+		id, _ := m["id"].(string)
+		if _, taken := s.blocks[key(id)]; !keepIDs || id == "" || taken {
+			id = s.newID()
+		}
+		b := s.newBlock(id, s.parentOf(parentID), typ, content)
+		s.blocks[key(id)] = b
+		created = append(created, b)
+		ids = append(ids, id)
+		if len(nested) > 0 {
+			s.insertBlocks(id, nested, "", keepIDs)
+		}
+	}
+	s.addChildren(parentID, ids, after)
+	return created
+}
+
+// newBlock 创建块对象
+func (s *Server) newBlock(id string, parent notion.Parent, typ string, content map[string]interface{}) map[string]interface{} {
+	now := s.now()
+	return map[string]interface{}{
+		"object":           "block",
+		"id":               id,
+		"parent":           parent,
+		"type":             typ,
+		typ:                content,
+		"created_time":     now,
+		"last_edited_time": now,
+		"created_by":       s.userRef(),
+		"last_edited_by":   s.userRef(),
+		"has_children":     false,
+		"archived":         false,
+		"in_trash":         false,
+	}
+}
+
+// parentOf 返回以 id 为父对象的 Parent
+func (s *Server) parentOf(id string) notion.Parent {
+	if _, ok := s.pages[key(id)]; ok {
+		return notion.Parent{Type: "page_id", PageID: id}
+	}
+	return notion.Parent{Type: "block_id", BlockID: id}
+}
+
+// addChildren 将子块 ID 插入父对象的子块列表
+func (s *Server) addChildren(parentID string, ids []string, after string) {
+	k := key(parentID)
+	list := s.children[k]
+	at := len(list)
+	for i, child := range list {
+		if after != "" && key(child) == key(after) {
+			at = i + 1
+			break
+		}
+	}
+	merged := make([]string, 0, len(list)+len(ids))
+	merged = append(merged, list[:at]...)
+	merged = append(merged, ids...)
+	merged = append(merged, list[at:]...)
+	s.children[k] = merged
+}
+
+// hasChild 判断 id 是否是父对象的子块
+func (s *Server) hasChild(parentKey, id string) bool {
+	for _, child := range s.children[parentKey] {
+		if key(child) == key(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleChildren 返回父对象未归档的子块
+func (s *Server) visibleChildren(parentKey string) []map[string]interface{} {
+	var children []map[string]interface{}
+	for _, id := range s.children[parentKey] {
+		if b, ok := s.blocks[key(id)]; ok && b["archived"] != true {
+			children = append(children, b)
+		}
+	}
+	return children
+}
+
+// blockJSON 返回块的响应，has_children 根据当前的子块计算
+func (s *Server) blockJSON(b map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(b))
+	for k, v := range b {
+		out[k] = v
+	}
+	out["has_children"] = len(s.visibleChildren(key(b["id"].(string)))) > 0
+	return out
+}
+
+// touchBlock 更新块的最后编辑时间
+func (s *Server) touchBlock(b map[string]interface{}) {
+	b["last_edited_time"] = s.now()
+	b["last_edited_by"] = s.userRef()
+	if p, ok := s.pages[key(b["id"].(string))]; ok {
+		p.LastEditedTime = b["last_edited_time"].(string)
+		s.refreshComputed(p)
+	}
+}
+
+// setArchived 归档或恢复块，页面和子页面块、数据库和子数据库块的状态保持一致
+func (s *Server) setArchived(id string, archived bool) {
+	k := key(id)
+	if b, ok := s.blocks[k]; ok {
+		b["archived"] = archived
+		b["in_trash"] = archived
+	}
+	if p, ok := s.pages[k]; ok {
+		p.Archived = archived
+	}
+	if db, ok := s.databases[k]; ok {
+		db.Archived = archived
+	}
+}
+
+// userRef 返回响应中引用当前集成的部分用户对象
+func (s *Server) userRef() notion.User {
+	return notion.User{Object: "user", ID: s.bot.ID}
+}
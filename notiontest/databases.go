@@ -0,0 +1,352 @@
+package notiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// databaseParams 是创建和更新数据库时读取的字段
+type databaseParams struct {
+	Parent      notion.Parent     `json:"parent"`
+	Title       []notion.RichText `json:"title"`
+	Description []notion.RichText `json:"description"`
+	Icon        *notion.Icon      `json:"icon"`
+	Cover       *notion.File      `json:"cover"`
+	IsInline    bool              `json:"is_inline"`
+	Archived    *bool             `json:"archived"`
+	InTrash     *bool             `json:"in_trash"`
+}
+
+// createDatabase 处理 POST /databases
+func (s *Server) createDatabase(req map[string]interface{}) (interface{}, error) {
+	var params databaseParams
+	if err := decode(req, &params); err != nil {
+		return nil, err
+	}
+	props, _ := req["properties"].(map[string]interface{})
+	schema := make(map[string]notion.Property, len(props))
+	for name, v := range props {
+		prop, err := decodeProperty(name, v)
+		if err != nil {
+			return nil, err
+		}
+		schema[name] = prop
+	}
+
+	db := &notion.Database{
+		Parent:      params.Parent,
+		Title:       params.Title,
+		Description: params.Description,
+		Icon:        params.Icon,
+		Cover:       params.Cover,
+		Properties:  schema,
+		IsInline:    params.IsInline,
+	}
+	if err := s.insertDatabase(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// getDatabase 处理 GET /databases/{id}
+func (s *Server) getDatabase(id string) (interface{}, error) {
+	db, ok := s.databases[key(id)]
+	if !ok {
+		return nil, notFound("database", id)
+	}
+	return db, nil
+}
+
+// listDatabases 处理 GET /databases，返回所有未归档的数据库
+func (s *Server) listDatabases(query url.Values) (interface{}, error) {
+	var results []interface{}
+	for _, k := range s.order {
+		if db, ok := s.databases[k]; ok && !db.Archived {
+			results = append(results, db)
+		}
+	}
+	cursor, size := queryPage(query)
+	return paginate(results, cursor, size), nil
+}
+
+// updateDatabase 处理 PATCH /databases/{id}
+//
+// 属性值为 null 时删除属性，包含 name 时重命名属性，数据库中的页面同步修改。
+func (s *Server) updateDatabase(id string, req map[string]interface{}) (interface{}, error) {
+	db, ok := s.databases[key(id)]
+	if !ok {
+		return nil, notFound("database", id)
+	}
+	var params databaseParams
+	if err := decode(req, &params); err != nil {
+		return nil, err
+	}
+
+	props, _ := req["properties"].(map[string]interface{})
+	schema := make(map[string]notion.Property, len(db.Properties))
+	for name, prop := range db.Properties {
+		schema[name] = prop
+	}
+	renamed := map[string]string{}
+	for nameOrID, v := range props {
+		name, old, exists := schemaProperty(&notion.Database{Properties: schema}, nameOrID)
+		if v == nil {
+			if !exists {
+				return nil, invalid("%s is not a property that exists.", nameOrID)
+			}
+			delete(schema, name)
+			renamed[name] = ""
+			continue
+		}
+		if !exists {
+			name = nameOrID
+		}
+		prop, err := decodeProperty(name, v)
+		if err != nil && !exists {
+			return nil, err
+		}
+		if exists {
+			if prop.Type == "" || err != nil {
+				// 只修改名称时保留原来的配置
+				prop = old
+			}
+			prop.ID = old.ID
+			if m, ok := v.(map[string]interface{}); ok {
+				if newName, ok := m["name"].(string); ok && newName != name {
+					delete(schema, name)
+					renamed[name] = newName
+					name = newName
+				}
+			}
+		}
+		prop.Name = name
+		schema[name] = prop
+	}
+	if err := checkSchema(schema); err != nil {
+		return nil, err
+	}
+
+	db.Properties = s.assignPropertyIDs(schema)
+	if params.Title != nil {
+		db.Title = params.Title
+	}
+	if params.Description != nil {
+		db.Description = params.Description
+	}
+	if v, ok := req["icon"]; ok {
+		db.Icon = params.Icon
+		if v == nil {
+			db.Icon = nil
+		}
+	}
+	if v, ok := req["cover"]; ok {
+		db.Cover = params.Cover
+		if v == nil {
+			db.Cover = nil
+		}
+	}
+	if params.Archived != nil {
+		s.setArchived(db.ID, *params.Archived)
+	}
+	if params.InTrash != nil {
+		s.setArchived(db.ID, *params.InTrash)
+	}
+	db.LastEditedTime = s.now()
+	db.LastEditedBy = s.userRef()
+	if b, ok := s.blocks[key(db.ID)]; ok {
+		b["last_edited_time"] = db.LastEditedTime
+		b[string(notion.TypeChildDatabase)] = map[string]interface{}{"title": plainText(db.Title)}
+	}
+
+	// 同步数据库中页面的属性
+	for _, p := range s.rows(db.ID) {
+		for oldName, newName := range renamed {
+			if v, ok := p.Properties[oldName]; ok {
+				delete(p.Properties, oldName)
+				if newName != "" {
+					p.Properties[newName] = v
+				}
+			}
+		}
+		s.fillProperties(p)
+	}
+	return db, nil
+}
+
+// queryDatabase 处理 POST /databases/{id}/query，支持过滤、排序和分页，不返回已归档的页面
+//
+// 没有排序条件时按创建顺序返回。
+func (s *Server) queryDatabase(id string, req map[string]interface{}) (interface{}, error) {
+	db, ok := s.databases[key(id)]
+	if !ok {
+		return nil, notFound("database", id)
+	}
+	var params struct {
+		Sorts []notion.Sort `json:"sorts"`
+	}
+	if err := decode(req, &params); err != nil {
+		return nil, err
+	}
+
+	var pages []*notion.Page
+	for _, p := range s.rows(db.ID) {
+		if p.Archived {
+			continue
+		}
+		if f, ok := req["filter"].(map[string]interface{}); ok {
+			matched, err := s.match(f, p, 0)
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				continue
+			}
+		}
+		pages = append(pages, p)
+	}
+	if err := sortPages(db, pages, params.Sorts); err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, len(pages))
+	for i, p := range pages {
+		results[i] = p
+	}
+	cursor, size := bodyPage(req)
+	resp := paginate(results, cursor, size)
+	resp["type"] = "page_or_database"
+	resp["page_or_database"] = map[string]interface{}{}
+	return resp, nil
+}
+
+// insertDatabase 校验并保存数据库，未设置的 ID 和时间自动生成，数据库同时作为子数据库块加入父页面的子块
+func (s *Server) insertDatabase(db *notion.Database) error {
+	parentID, err := s.checkParent(&db.Parent)
+	if err != nil {
+		return err
+	}
+	if db.Parent.Type == "database_id" {
+		return invalid("body.parent.page_id should be defined, instead was `undefined`.")
+	}
+	for name, prop := range db.Properties {
+		prop.Name = name
+		if prop.Type == "" {
+			prop.Type = configType(prop)
+		}
+		db.Properties[name] = prop
+	}
+	if err := checkSchema(db.Properties); err != nil {
+		return err
+	}
+
+	now := s.now()
+	db.Object = "database"
+	if db.ID == "" {
+		db.ID = s.newID()
+	}
+	if db.CreatedTime == "" {
+		db.CreatedTime = now
+	}
+	if db.LastEditedTime == "" {
+		db.LastEditedTime = now
+	}
+	if db.CreatedBy.ID == "" {
+		db.CreatedBy = s.userRef()
+	}
+	if db.LastEditedBy.ID == "" {
+		db.LastEditedBy = s.userRef()
+	}
+	if db.Title == nil {
+		db.Title = []notion.RichText{}
+	}
+	if db.Description == nil {
+		db.Description = []notion.RichText{}
+	}
+	db.URL = "https://www.notion.so/" + key(db.ID)
+	db.Properties = s.assignPropertyIDs(db.Properties)
+	s.databases[key(db.ID)] = db
+	s.order = append(s.order, key(db.ID))
+
+	b := s.newBlock(db.ID, db.Parent, string(notion.TypeChildDatabase), map[string]interface{}{"title": plainText(db.Title)})
+	b["created_time"] = db.CreatedTime
+	b["last_edited_time"] = db.LastEditedTime
+	b["archived"] = db.Archived
+	b["in_trash"] = db.Archived
+	s.blocks[key(db.ID)] = b
+	if parentID != "" {
+		s.addChildren(parentID, []string{db.ID}, "")
+	}
+	return nil
+}
+
+// decodeProperty 解码请求中的属性定义，缺少 type 时根据配置字段推断
+func decodeProperty(name string, v interface{}) (notion.Property, error) {
+	var prop notion.Property
+	if err := decode(v, &prop); err != nil {
+		return prop, err
+	}
+	prop.Name = name
+	if prop.Type == "" {
+		prop.Type = configType(prop)
+	}
+	if prop.Type == "" {
+		return prop, invalid("body.properties.%s should have a property type.", name)
+	}
+	return prop, nil
+}
+
+// configType 返回属性定义中唯一设置的配置字段对应的类型
+func configType(prop notion.Property) notion.PropertyType {
+	data, _ := json.Marshal(prop)
+	var fields map[string]json.RawMessage
+	json.Unmarshal(data, &fields)
+	for k := range fields {
+		if k != "id" && k != "name" && k != "type" && k != "description" {
+			return notion.PropertyType(k)
+		}
+	}
+	return ""
+}
+
+// checkSchema 检查数据库有且只有一个标题属性
+func checkSchema(schema map[string]notion.Property) error {
+	titles := 0
+	for _, prop := range schema {
+		if prop.Type == notion.PropertyTypeTitle {
+			titles++
+		}
+	}
+	if titles != 1 {
+		return invalid("Databases must have exactly one title property, found %d.", titles)
+	}
+	return nil
+}
+
+// assignPropertyIDs 为没有 ID 的属性生成 ID，标题属性的 ID 总是 "title"
+func (s *Server) assignPropertyIDs(schema map[string]notion.Property) map[string]notion.Property {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prop := schema[name]
+		if prop.Type == notion.PropertyTypeTitle {
+			prop.ID = "title"
+		} else if prop.ID == "" {
+			s.seq++
+			prop.ID = fmt.Sprintf("p%d", s.seq)
+		}
+		schema[name] = prop
+	}
+	return schema
+}
+
+// plainText 拼接富文本的纯文本
+func plainText(rt []notion.RichText) string {
+	return notion.PropertyValue{Type: notion.PropertyTypeRichText, RichText: rt}.PlainText()
+}
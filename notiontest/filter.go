@@ -0,0 +1,546 @@
+package notiontest
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// maxFilterDepth 是复合过滤条件允许的最大嵌套层数
+const maxFilterDepth = 2
+
+// match 判断页面是否满足过滤条件，depth 是当前复合条件的嵌套层数
+//
+// 支持 and/or、时间戳条件，以及除汇总外所有属性类型的条件。文本的 contains、starts_with 和 ends_with
+// 不区分大小写，与 Notion 一致。
+func (s *Server) match(f map[string]interface{}, p *notion.Page, depth int) (bool, error) {
+	for _, op := range []string{"and", "or"} {
+		list, ok := f[op]
+		if !ok {
+			continue
+		}
+		if depth > maxFilterDepth {
+			return false, invalid("body.filter exceeds the maximum nesting depth of %d.", maxFilterDepth)
+		}
+		filters, ok := list.([]interface{})
+		if !ok {
+			return false, invalid("body.filter.%s should be an array.", op)
+		}
+		for _, item := range filters {
+			sub, ok := item.(map[string]interface{})
+			if !ok {
+				return false, invalid("body.filter.%s should be an array of filter objects.", op)
+			}
+			matched, err := s.match(sub, p, depth+1)
+			if err != nil {
+				return false, err
+			}
+			if op == "and" && !matched {
+				return false, nil
+			}
+			if op == "or" && matched {
+				return true, nil
+			}
+		}
+		return op == "and", nil
+	}
+
+	if ts, ok := f["timestamp"].(string); ok {
+		cond, _ := f[ts].(map[string]interface{})
+		switch ts {
+		case "created_time":
+			return s.matchDate(p.CreatedTime, cond)
+		case "last_edited_time":
+			return s.matchDate(p.LastEditedTime, cond)
+		}
+		return false, invalid("body.filter.timestamp should be `created_time` or `last_edited_time`, instead was `%s`.", ts)
+	}
+
+	name, _ := f["property"].(string)
+	db := s.databases[key(p.Parent.DatabaseID)]
+	if db == nil {
+		return false, invalid("body.filter is only supported for database queries.")
+	}
+	realName, prop, ok := schemaProperty(db, name)
+	if !ok {
+		return false, invalid("Could not find property with name or id: %s", name)
+	}
+	for typ, c := range f {
+		if typ == "property" {
+			continue
+		}
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			return false, invalid("body.filter.%s should be an object.", typ)
+		}
+		if !filterApplies(typ, prop.Type) {
+			return false, invalid("body.filter.%s does not match the type of property %s (%s).", typ, name, prop.Type)
+		}
+		return s.matchValue(p.Properties[realName], typ, cond)
+	}
+	return false, invalid("body.filter should define a condition for property %s.", name)
+}
+
+// filterApplies 判断条件类型能否用于属性类型
+func filterApplies(cond string, typ notion.PropertyType) bool {
+	switch notion.PropertyType(cond) {
+	case typ:
+		return true
+	case notion.PropertyTypeRichText:
+		// rich_text 条件也可以用于标题、URL、邮箱和电话属性
+		return typ == notion.PropertyTypeTitle || typ == notion.PropertyTypeURL ||
+			typ == notion.PropertyTypeEmail || typ == notion.PropertyTypePhoneNumber
+	}
+	return false
+}
+
+// matchValue 按条件类型比较属性值
+func (s *Server) matchValue(v notion.PropertyValue, typ string, cond map[string]interface{}) (bool, error) {
+	switch notion.PropertyType(typ) {
+	case notion.PropertyTypeTitle, notion.PropertyTypeRichText, notion.PropertyTypeURL,
+		notion.PropertyTypeEmail, notion.PropertyTypePhoneNumber:
+		return matchText(v.PlainText(), cond)
+	case notion.PropertyTypeNumber:
+		return matchNumber(v.Number, cond)
+	case notion.PropertyTypeUniqueID:
+		if v.UniqueID == nil {
+			return matchNumber(nil, cond)
+		}
+		n := float64(v.UniqueID.Number)
+		return matchNumber(&n, cond)
+	case notion.PropertyTypeCheckbox:
+		return matchCheckbox(v.Checkbox, cond)
+	case notion.PropertyTypeSelect, notion.PropertyTypeStatus:
+		return matchSelect(v.PlainText(), cond)
+	case notion.PropertyTypeMultiSelect:
+		names := make([]string, len(v.MultiSelect))
+		for i, o := range v.MultiSelect {
+			names[i] = o.Name
+		}
+		return matchContains(names, cond, false)
+	case notion.PropertyTypePeople:
+		ids := make([]string, len(v.People))
+		for i, u := range v.People {
+			ids[i] = u.ID
+		}
+		return matchContains(ids, cond, true)
+	case notion.PropertyTypeRelation:
+		ids := make([]string, len(v.Relation))
+		for i, r := range v.Relation {
+			ids[i] = r.ID
+		}
+		return matchContains(ids, cond, true)
+	case notion.PropertyTypeCreatedBy:
+		return matchContains(userIDs(v.CreatedBy), cond, true)
+	case notion.PropertyTypeLastEditedBy:
+		return matchContains(userIDs(v.LastEditedBy), cond, true)
+	case notion.PropertyTypeFiles:
+		return matchContains(make([]string, len(v.Files)), cond, false)
+	case notion.PropertyTypeDate:
+		start := ""
+		if v.Date != nil {
+			start = v.Date.Start
+		}
+		return s.matchDate(start, cond)
+	case notion.PropertyTypeCreatedTime:
+		return s.matchDate(v.CreatedTime, cond)
+	case notion.PropertyTypeLastEditedTime:
+		return s.matchDate(v.LastEditedTime, cond)
+	case notion.PropertyTypeFormula:
+		return s.matchFormula(v.Formula, cond)
+	}
+	return false, invalid("notiontest: 不支持 %s 过滤条件", typ)
+}
+
+// matchFormula 按公式结果的类型比较
+func (s *Server) matchFormula(f *notion.FormulaValue, cond map[string]interface{}) (bool, error) {
+	if f == nil {
+		f = &notion.FormulaValue{}
+	}
+	for typ, c := range cond {
+		sub, ok := c.(map[string]interface{})
+		if !ok {
+			return false, invalid("body.filter.formula.%s should be an object.", typ)
+		}
+		switch typ {
+		case "string":
+			text := ""
+			if f.String != nil {
+				text = *f.String
+			}
+			return matchText(text, sub)
+		case "number":
+			return matchNumber(f.Number, sub)
+		case "checkbox":
+			return matchCheckbox(f.Boolean != nil && *f.Boolean, sub)
+		case "date":
+			start := ""
+			if f.Date != nil {
+				start = f.Date.Start
+			}
+			return s.matchDate(start, sub)
+		}
+		return false, invalid("body.filter.formula.%s is not a valid formula condition.", typ)
+	}
+	return false, invalid("body.filter.formula should define a condition.")
+}
+
+// matchText 比较文本，所有操作都满足时返回 true
+func matchText(value string, cond map[string]interface{}) (bool, error) {
+	lower := strings.ToLower(value)
+	for op, arg := range cond {
+		s, _ := arg.(string)
+		var ok bool
+		switch op {
+		case "equals":
+			ok = value == s
+		case "does_not_equal":
+			ok = value != s
+		case "contains":
+			ok = strings.Contains(lower, strings.ToLower(s))
+		case "does_not_contain":
+			ok = !strings.Contains(lower, strings.ToLower(s))
+		case "starts_with":
+			ok = strings.HasPrefix(lower, strings.ToLower(s))
+		case "ends_with":
+			ok = strings.HasSuffix(lower, strings.ToLower(s))
+		case "is_empty":
+			ok = value == ""
+		case "is_not_empty":
+			ok = value != ""
+		default:
+			return false, invalid("%s is not a valid text filter condition.", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchNumber 比较数字，空值只满足 is_empty 和 does_not_equal
+func matchNumber(value *float64, cond map[string]interface{}) (bool, error) {
+	for op, arg := range cond {
+		n, _ := arg.(float64)
+		var ok bool
+		switch op {
+		case "is_empty":
+			ok = value == nil
+		case "is_not_empty":
+			ok = value != nil
+		case "does_not_equal":
+			ok = value == nil || *value != n
+		case "equals":
+			ok = value != nil && *value == n
+		case "greater_than":
+			ok = value != nil && *value > n
+		case "less_than":
+			ok = value != nil && *value < n
+		case "greater_than_or_equal_to":
+			ok = value != nil && *value >= n
+		case "less_than_or_equal_to":
+			ok = value != nil && *value <= n
+		default:
+			return false, invalid("%s is not a valid number filter condition.", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchCheckbox 比较复选框
+func matchCheckbox(value bool, cond map[string]interface{}) (bool, error) {
+	for op, arg := range cond {
+		b, _ := arg.(bool)
+		var ok bool
+		switch op {
+		case "equals":
+			ok = value == b
+		case "does_not_equal":
+			ok = value != b
+		default:
+			return false, invalid("%s is not a valid checkbox filter condition.", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchSelect 比较单选或状态的选项名称
+func matchSelect(value string, cond map[string]interface{}) (bool, error) {
+	for op, arg := range cond {
+		s, _ := arg.(string)
+		var ok bool
+		switch op {
+		case "equals":
+			ok = value == s
+		case "does_not_equal":
+			ok = value != s
+		case "is_empty":
+			ok = value == ""
+		case "is_not_empty":
+			ok = value != ""
+		default:
+			return false, invalid("%s is not a valid select filter condition.", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchContains 比较多选、人员、关联和文件等列表值，byID 为 true 时忽略 ID 中的连字符
+func matchContains(values []string, cond map[string]interface{}, byID bool) (bool, error) {
+	contains := func(s string) bool {
+		for _, v := range values {
+			if v == s || byID && key(v) == key(s) {
+				return true
+			}
+		}
+		return false
+	}
+	for op, arg := range cond {
+		s, _ := arg.(string)
+		var ok bool
+		switch op {
+		case "contains":
+			ok = contains(s)
+		case "does_not_contain":
+			ok = !contains(s)
+		case "is_empty":
+			ok = len(values) == 0
+		case "is_not_empty":
+			ok = len(values) > 0
+		default:
+			return false, invalid("%s is not a valid filter condition.", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchDate 比较日期，条件中的日期不含时间时按天比较，相对日期以服务器的 Now 为准
+func (s *Server) matchDate(value string, cond map[string]interface{}) (bool, error) {
+	now := s.Now()
+	for op, arg := range cond {
+		date, _ := arg.(string)
+		var ok bool
+		switch op {
+		case "is_empty":
+			ok = value == ""
+		case "is_not_empty":
+			ok = value != ""
+		case "equals", "before", "after", "on_or_before", "on_or_after":
+			if date == "" {
+				return false, invalid("body.filter.date.%s should be a valid ISO 8601 date string.", op)
+			}
+			if value == "" {
+				return false, nil
+			}
+			c, err := compareDates(value, date)
+			if err != nil {
+				return false, err
+			}
+			ok = op == "equals" && c == 0 || op == "before" && c < 0 || op == "after" && c > 0 ||
+				op == "on_or_before" && c <= 0 || op == "on_or_after" && c >= 0
+		case "past_week", "past_month", "past_year", "this_week", "next_week", "next_month", "next_year":
+			if value == "" {
+				return false, nil
+			}
+			t, err := parseDate(value)
+			if err != nil {
+				return false, err
+			}
+			from, to := relativeRange(op, now)
+			ok = !t.Before(from) && !t.After(to)
+		default:
+			return false, invalid("%s is not a valid date filter condition.", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// relativeRange 返回相对日期条件对应的时间范围，本周从周日开始
+func relativeRange(op string, now time.Time) (time.Time, time.Time) {
+	switch op {
+	case "past_week":
+		return now.AddDate(0, 0, -7), now
+	case "past_month":
+		return now.AddDate(0, -1, 0), now
+	case "past_year":
+		return now.AddDate(-1, 0, 0), now
+	case "this_week":
+		y, m, d := now.Date()
+		start := time.Date(y, m, d-int(now.Weekday()), 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 0, 7).Add(-time.Nanosecond)
+	case "next_week":
+		return now, now.AddDate(0, 0, 7)
+	case "next_month":
+		return now, now.AddDate(0, 1, 0)
+	}
+	return now, now.AddDate(1, 0, 0)
+}
+
+// compareDates 比较两个日期，任一方不含时间时只比较日期部分
+func compareDates(a, b string) (int, error) {
+	if len(a) == len("2006-01-02") || len(b) == len("2006-01-02") {
+		return strings.Compare(a[:min(len(a), 10)], b[:min(len(b), 10)]), nil
+	}
+	ta, err := parseDate(a)
+	if err != nil {
+		return 0, err
+	}
+	tb, err := parseDate(b)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case ta.Before(tb):
+		return -1, nil
+	case ta.After(tb):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// parseDate 解析日期或日期时间
+func parseDate(s string) (time.Time, error) {
+	layout := time.RFC3339
+	if len(s) == len("2006-01-02") {
+		layout = "2006-01-02"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return t, invalid("%s is not a valid ISO 8601 date string.", s)
+	}
+	return t, nil
+}
+
+// min 返回较小的整数
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// userIDs 返回用户的 ID 列表，用户为空时返回空列表
+func userIDs(u *notion.User) []string {
+	if u == nil || u.ID == "" {
+		return nil
+	}
+	return []string{u.ID}
+}
+
+// sortPages 按排序条件排序页面，空值总是排在最后
+func sortPages(db *notion.Database, pages []*notion.Page, sorts []notion.Sort) error {
+	for _, so := range sorts {
+		if so.Direction != "ascending" && so.Direction != "descending" {
+			return invalid("body.sorts.direction should be `ascending` or `descending`, instead was `%s`.", so.Direction)
+		}
+		switch {
+		case so.Timestamp != "":
+			if so.Timestamp != "created_time" && so.Timestamp != "last_edited_time" {
+				return invalid("body.sorts.timestamp should be `created_time` or `last_edited_time`, instead was `%s`.", so.Timestamp)
+			}
+		case so.Property != "":
+			if _, _, ok := schemaProperty(db, so.Property); !ok {
+				return invalid("Could not find sort property with name or id: %s", so.Property)
+			}
+		default:
+			return invalid("body.sorts should define a property or timestamp.")
+		}
+	}
+
+	sort.SliceStable(pages, func(i, j int) bool {
+		for _, so := range sorts {
+			if c := compareSort(db, pages[i], pages[j], so); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+// compareSort 按一个排序条件比较两个页面
+func compareSort(db *notion.Database, a, b *notion.Page, so notion.Sort) int {
+	var ka, kb interface{}
+	if so.Timestamp == "created_time" {
+		ka, kb = a.CreatedTime, b.CreatedTime
+	} else if so.Timestamp == "last_edited_time" {
+		ka, kb = a.LastEditedTime, b.LastEditedTime
+	} else {
+		name, _, _ := schemaProperty(db, so.Property)
+		ka, kb = sortKey(a.Properties[name]), sortKey(b.Properties[name])
+	}
+
+	switch {
+	case ka == nil && kb == nil:
+		return 0
+	case ka == nil:
+		return 1
+	case kb == nil:
+		return -1
+	}
+	c := 0
+	if na, ok := ka.(float64); ok {
+		nb := kb.(float64)
+		if na < nb {
+			c = -1
+		} else if na > nb {
+			c = 1
+		}
+	} else {
+		c = strings.Compare(ka.(string), kb.(string))
+	}
+	if so.Direction == "descending" {
+		c = -c
+	}
+	return c
+}
+
+// sortKey 返回属性值的排序键，数字类返回 float64，其他返回字符串，空值返回 nil
+func sortKey(v notion.PropertyValue) interface{} {
+	switch v.Type {
+	case notion.PropertyTypeNumber:
+		if v.Number != nil {
+			return *v.Number
+		}
+		return nil
+	case notion.PropertyTypeUniqueID:
+		if v.UniqueID != nil {
+			return float64(v.UniqueID.Number)
+		}
+		return nil
+	case notion.PropertyTypeCheckbox:
+		if v.Checkbox {
+			return float64(1)
+		}
+		return float64(0)
+	case notion.PropertyTypeFormula:
+		if v.Formula != nil && v.Formula.Number != nil {
+			return *v.Formula.Number
+		}
+		if v.Formula != nil && v.Formula.String != nil && *v.Formula.String != "" {
+			return strings.ToLower(*v.Formula.String)
+		}
+		return nil
+	}
+	if text := v.PlainText(); text != "" {
+		return strings.ToLower(text)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package notiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// Fixtures 表示预置到服务器中的数据，可以从 JSON 文件加载
+//
+// 数据按用户、数据库、页面、块、评论的顺序写入，页面和块的父对象必须先出现。
+// Blocks 的键是父页面或父块的 ID，块的子块写在块类型字段的 children 中。
+type Fixtures struct {
+	Users     []notion.User             `json:"users"`
+	Databases []notion.Database         `json:"databases"`
+	Pages     []notion.Page             `json:"pages"`
+	Blocks    map[string][]notion.Block `json:"blocks"`
+	Comments  []notion.Comment          `json:"comments"`
+}
+
+// Seed 写入预置数据，父对象不存在或数据不合法时返回错误
+//
+// Blocks 按父对象 ID 的顺序写入，需要先写入的父块应使用单独的 AddBlocks 调用。
+func (s *Server) Seed(f *Fixtures) error {
+	for _, u := range f.Users {
+		s.AddUser(u)
+	}
+	for _, db := range f.Databases {
+		if _, err := s.AddDatabase(db); err != nil {
+			return err
+		}
+	}
+	for _, p := range f.Pages {
+		if _, err := s.AddPage(p); err != nil {
+			return err
+		}
+	}
+	for parentID, blocks := range f.Blocks {
+		if _, err := s.AddBlocks(parentID, blocks...); err != nil {
+			return err
+		}
+	}
+	for _, c := range f.Comments {
+		if _, err := s.AddComment(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadFixtures 从 JSON 文件读取 Fixtures 并写入服务器
+func (s *Server) LoadFixtures(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var f Fixtures
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("notiontest: 解析 %s 失败: %w", path, err)
+	}
+	return s.Seed(&f)
+}
+
+// AddUser 添加用户，未设置 ID 时自动生成，返回保存的用户
+func (s *Server) AddUser(u notion.User) *notion.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u.Object = "user"
+	if u.ID == "" {
+		u.ID = s.newID()
+	}
+	if u.Type == "" {
+		u.Type = "person"
+	}
+	stored := u
+	s.users = append(s.users, &stored)
+	return &u
+}
+
+// AddDatabase 添加数据库，返回保存的数据库
+//
+// 未设置的 ID、时间和属性 ID 自动生成；没有父对象时放在工作区中，没有属性时添加名为 "Name" 的标题属性。
+func (s *Server) AddDatabase(db notion.Database) (*notion.Database, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if db.Parent == (notion.Parent{}) {
+		db.Parent = notion.Parent{Type: "workspace"}
+	}
+	if len(db.Properties) == 0 {
+		db.Properties = map[string]notion.Property{"Name": {Type: notion.PropertyTypeTitle, Title: &notion.EmptyObject{}}}
+	}
+	props := make(map[string]notion.Property, len(db.Properties))
+	for name, prop := range db.Properties {
+		props[name] = prop
+	}
+	db.Properties = props
+	if err := s.insertDatabase(&db); err != nil {
+		return nil, fmt.Errorf("notiontest: 添加数据库失败: %w", err)
+	}
+	out := db
+	out.Properties = make(map[string]notion.Property, len(db.Properties))
+	for name, prop := range db.Properties {
+		out.Properties[name] = prop
+	}
+	return &out, nil
+}
+
+// AddPage 添加页面，返回保存的页面
+//
+// 未设置的 ID 和时间自动生成，数据库中的页面补充数据库定义的其他属性。预置的公式、汇总等只读属性的值会保留。
+func (s *Server) AddPage(p notion.Page) (*notion.Page, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.insertPage(&p, true); err != nil {
+		return nil, fmt.Errorf("notiontest: 添加页面失败: %w", err)
+	}
+	out := p
+	out.Properties = make(notion.Properties, len(p.Properties))
+	for name, v := range p.Properties {
+		out.Properties[name] = v
+	}
+	return &out, nil
+}
+
+// AddBlocks 在页面或块的末尾追加子块，返回新建的顶层块
+//
+// 块的子块写在块类型字段的 children 中，没有嵌套层数限制。设置了 ID 的块使用该 ID。
+func (s *Server) AddBlocks(parentID string, blocks ...notion.Block) ([]*notion.Block, error) {
+	data, err := json.Marshal(blocks)
+	if err != nil {
+		return nil, err
+	}
+	var items []interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	normalizeRichText(items)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parent, ok := s.blocks[key(parentID)]
+	if !ok {
+		return nil, fmt.Errorf("notiontest: 添加块失败: %w", notFound("block", parentID))
+	}
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); !ok || blockType(m) == "" {
+			return nil, fmt.Errorf("notiontest: 添加块失败: 块缺少类型")
+		}
+	}
+
+	created := s.insertBlocks(parent["id"].(string), items, "", true)
+	out := make([]*notion.Block, len(created))
+	for i, b := range created {
+		out[i] = new(notion.Block)
+		if err := decode(s.blockJSON(b), out[i]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// AddComment 在页面或块上添加评论，未设置的 ID、时间和讨论自动生成，返回保存的评论
+func (s *Server) AddComment(c notion.Comment) (*notion.Comment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.blocks[key(c.ParentID)]; !ok {
+		return nil, fmt.Errorf("notiontest: 添加评论失败: %w", notFound("block", c.ParentID))
+	}
+	s.insertComment(&c)
+	out := c
+	return &out, nil
+}
@@ -0,0 +1,314 @@
+package notiontest
+
+import (
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// pageParams 是创建和更新页面时读取的字段
+type pageParams struct {
+	Parent     notion.Parent     `json:"parent"`
+	Properties notion.Properties `json:"properties"`
+	Icon       *notion.Icon      `json:"icon"`
+	Cover      *notion.File      `json:"cover"`
+	Archived   *bool             `json:"archived"`
+	InTrash    *bool             `json:"in_trash"`
+}
+
+// createPage 处理 POST /pages
+func (s *Server) createPage(req map[string]interface{}) (interface{}, error) {
+	var params pageParams
+	if err := decode(req, &params); err != nil {
+		return nil, err
+	}
+	children, _ := req["children"].([]interface{})
+	if err := checkBlocks(children, 1); err != nil {
+		return nil, err
+	}
+
+	page := &notion.Page{
+		Parent:     params.Parent,
+		Properties: params.Properties,
+		Icon:       params.Icon,
+		Cover:      params.Cover,
+	}
+	if err := s.insertPage(page, false); err != nil {
+		return nil, err
+	}
+	if len(children) > 0 {
+		s.insertBlocks(page.ID, children, "", false)
+	}
+	return page, nil
+}
+
+// getPage 处理 GET /pages/{id}
+func (s *Server) getPage(id string) (interface{}, error) {
+	p, ok := s.pages[key(id)]
+	if !ok {
+		return nil, notFound("page", id)
+	}
+	return p, nil
+}
+
+// updatePage 处理 PATCH /pages/{id}，只修改请求中出现的属性
+func (s *Server) updatePage(id string, req map[string]interface{}) (interface{}, error) {
+	p, ok := s.pages[key(id)]
+	if !ok {
+		return nil, notFound("page", id)
+	}
+	var params pageParams
+	if err := decode(req, &params); err != nil {
+		return nil, err
+	}
+	props, err := s.checkProperties(p.Parent, params.Properties, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, v := range props {
+		p.Properties[name] = v
+	}
+	if v, ok := req["icon"]; ok {
+		p.Icon = params.Icon
+		if v == nil {
+			p.Icon = nil
+		}
+	}
+	if v, ok := req["cover"]; ok {
+		p.Cover = params.Cover
+		if v == nil {
+			p.Cover = nil
+		}
+	}
+	if params.Archived != nil {
+		s.setArchived(p.ID, *params.Archived)
+	}
+	if params.InTrash != nil {
+		s.setArchived(p.ID, *params.InTrash)
+	}
+	s.touchPage(p)
+	return p, nil
+}
+
+// insertPage 校验并保存页面，未设置的 ID 和时间自动生成，页面同时作为子页面块加入父页面的子块
+//
+// seeded 为 true 时保留只读属性的值，用于预置数据。
+func (s *Server) insertPage(p *notion.Page, seeded bool) error {
+	parentID, err := s.checkParent(&p.Parent)
+	if err != nil {
+		return err
+	}
+	props, err := s.checkProperties(p.Parent, p.Properties, seeded)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	p.Object = "page"
+	if p.ID == "" {
+		p.ID = s.newID()
+	}
+	if p.CreatedTime == "" {
+		p.CreatedTime = now
+	}
+	if p.LastEditedTime == "" {
+		p.LastEditedTime = now
+	}
+	if p.CreatedBy.ID == "" {
+		p.CreatedBy = s.userRef()
+	}
+	if p.LastEditedBy.ID == "" {
+		p.LastEditedBy = s.userRef()
+	}
+	p.URL = "https://www.notion.so/" + key(p.ID)
+	p.Properties = props
+	s.fillProperties(p)
+	s.pages[key(p.ID)] = p
+	s.order = append(s.order, key(p.ID))
+
+	b := s.newBlock(p.ID, p.Parent, string(notion.TypeChildPage), map[string]interface{}{"title": pageTitle(p)})
+	b["created_time"] = p.CreatedTime
+	b["last_edited_time"] = p.LastEditedTime
+	b["archived"] = p.Archived
+	b["in_trash"] = p.Archived
+	s.blocks[key(p.ID)] = b
+	if parentID != "" {
+		s.addChildren(parentID, []string{p.ID}, "")
+	}
+	return nil
+}
+
+// touchPage 更新页面和对应子页面块的最后编辑时间和标题
+func (s *Server) touchPage(p *notion.Page) {
+	p.LastEditedTime = s.now()
+	p.LastEditedBy = s.userRef()
+	s.refreshComputed(p)
+	if b, ok := s.blocks[key(p.ID)]; ok {
+		b["last_edited_time"] = p.LastEditedTime
+		b[string(notion.TypeChildPage)] = map[string]interface{}{"title": pageTitle(p)}
+	}
+}
+
+// checkParent 检查父对象是否存在，返回需要把新对象加入其子块列表的父对象 ID
+func (s *Server) checkParent(parent *notion.Parent) (string, error) {
+	if parent.Type == "" {
+		switch {
+		case parent.DatabaseID != "":
+			parent.Type = "database_id"
+		case parent.PageID != "":
+			parent.Type = "page_id"
+		case parent.BlockID != "":
+			parent.Type = "block_id"
+		}
+	}
+	switch parent.Type {
+	case "database_id":
+		if _, ok := s.databases[key(parent.DatabaseID)]; !ok {
+			return "", notFound("database", parent.DatabaseID)
+		}
+		return "", nil
+	case "page_id":
+		if _, ok := s.pages[key(parent.PageID)]; !ok {
+			return "", notFound("page", parent.PageID)
+		}
+		return parent.PageID, nil
+	case "block_id":
+		if _, ok := s.blocks[key(parent.BlockID)]; !ok {
+			return "", notFound("block", parent.BlockID)
+		}
+		return parent.BlockID, nil
+	case "workspace":
+		return "", nil
+	}
+	return "", invalid("body.parent should be defined, instead was `undefined`.")
+}
+
+// checkProperties 按父对象检查页面属性并补充属性 ID 和类型
+//
+// 数据库中的页面只能使用数据库已有的属性，属性可以用名称或 ID 引用；其他页面只有 title 属性。
+// 只读属性（公式、汇总、创建时间等）的值只在 keepReadOnly 为 true 时保留。
+func (s *Server) checkProperties(parent notion.Parent, props notion.Properties, keepReadOnly bool) (notion.Properties, error) {
+	out := notion.Properties{}
+	db := s.databases[key(parent.DatabaseID)]
+	for name, v := range props {
+		if db == nil {
+			if name != "title" && v.Type != notion.PropertyTypeTitle {
+				return nil, invalid("%s is not a property that exists.", name)
+			}
+			v.ID, v.Type = "title", notion.PropertyTypeTitle
+			out["title"] = v
+			continue
+		}
+
+		realName, prop, ok := schemaProperty(db, name)
+		if !ok {
+			return nil, invalid("%s is not a property that exists.", name)
+		}
+		if readOnly(prop.Type) && !keepReadOnly {
+			continue
+		}
+		if v.Type != "" && v.Type != prop.Type {
+			return nil, invalid("%s is expected to be %s.", name, prop.Type)
+		}
+		v.ID, v.Type = prop.ID, prop.Type
+		out[realName] = v
+	}
+	return out, nil
+}
+
+// fillProperties 为页面补充数据库中定义但未设置的属性，并计算只读属性
+func (s *Server) fillProperties(p *notion.Page) {
+	if p.Properties == nil {
+		p.Properties = notion.Properties{}
+	}
+	db := s.databases[key(p.Parent.DatabaseID)]
+	if db == nil {
+		if _, ok := p.Properties["title"]; !ok {
+			p.Properties["title"] = notion.PropertyValue{ID: "title", Type: notion.PropertyTypeTitle}
+		}
+		return
+	}
+
+	for name, prop := range db.Properties {
+		if _, ok := p.Properties[name]; ok {
+			continue
+		}
+		v := notion.PropertyValue{ID: prop.ID, Type: prop.Type}
+		if prop.Type == notion.PropertyTypeUniqueID {
+			v.UniqueID = &notion.UniqueIDValue{Number: s.countRows(db.ID) + 1}
+		}
+		p.Properties[name] = v
+	}
+	s.refreshComputed(p)
+}
+
+// refreshComputed 更新页面的创建时间、编辑时间、创建者和编辑者属性
+func (s *Server) refreshComputed(p *notion.Page) {
+	for name, v := range p.Properties {
+		switch v.Type {
+		case notion.PropertyTypeCreatedTime:
+			v.CreatedTime = p.CreatedTime
+		case notion.PropertyTypeLastEditedTime:
+			v.LastEditedTime = p.LastEditedTime
+		case notion.PropertyTypeCreatedBy:
+			user := p.CreatedBy
+			v.CreatedBy = &user
+		case notion.PropertyTypeLastEditedBy:
+			user := p.LastEditedBy
+			v.LastEditedBy = &user
+		default:
+			continue
+		}
+		p.Properties[name] = v
+	}
+}
+
+// countRows 返回数据库中的页面数，包括已归档的页面
+func (s *Server) countRows(databaseID string) int {
+	return len(s.rows(databaseID))
+}
+
+// schemaProperty 按名称或 ID 查找数据库属性
+func schemaProperty(db *notion.Database, nameOrID string) (string, notion.Property, bool) {
+	if prop, ok := db.Properties[nameOrID]; ok {
+		return nameOrID, prop, true
+	}
+	for name, prop := range db.Properties {
+		if prop.ID == nameOrID {
+			return name, prop, true
+		}
+	}
+	return "", notion.Property{}, false
+}
+
+// readOnly 判断属性类型是否由 Notion 计算
+func readOnly(typ notion.PropertyType) bool {
+	switch typ {
+	case notion.PropertyTypeFormula, notion.PropertyTypeRollup,
+		notion.PropertyTypeCreatedTime, notion.PropertyTypeCreatedBy,
+		notion.PropertyTypeLastEditedTime, notion.PropertyTypeLastEditedBy,
+		notion.PropertyTypeUniqueID:
+		return true
+	}
+	return false
+}
+
+// pageTitle 返回页面标题属性的纯文本
+func pageTitle(p *notion.Page) string {
+	for _, v := range p.Properties {
+		if v.Type == notion.PropertyTypeTitle {
+			return v.PlainText()
+		}
+	}
+	return ""
+}
+
+// rows 按创建顺序返回数据库中的页面，包括已归档的页面
+func (s *Server) rows(databaseID string) []*notion.Page {
+	var pages []*notion.Page
+	for _, k := range s.order {
+		if p, ok := s.pages[k]; ok && key(p.Parent.DatabaseID) == key(databaseID) {
+			pages = append(pages, p)
+		}
+	}
+	return pages
+}
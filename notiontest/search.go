@@ -0,0 +1,201 @@
+package notiontest
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+
+	notion "github.com/kuekiko/NotionGO"
+)
+
+// search 处理 POST /search，按标题匹配未归档的页面和数据库
+//
+// 标题包含 query（不区分大小写）即匹配，默认按最后编辑时间降序排列。
+func (s *Server) search(req map[string]interface{}) (interface{}, error) {
+	var params struct {
+		Query  string `json:"query"`
+		Filter *struct {
+			Value    string `json:"value"`
+			Property string `json:"property"`
+		} `json:"filter"`
+		Sort *notion.Sort `json:"sort"`
+	}
+	if err := decode(req, &params); err != nil {
+		return nil, err
+	}
+	object := ""
+	if params.Filter != nil && params.Filter.Value != "" {
+		if params.Filter.Property != "object" || params.Filter.Value != "page" && params.Filter.Value != "database" {
+			return nil, invalid("body.filter.value should be `page` or `database`, instead was `%s`.", params.Filter.Value)
+		}
+		object = params.Filter.Value
+	}
+	direction := "descending"
+	if params.Sort != nil && params.Sort.Direction != "" {
+		if params.Sort.Direction != "ascending" && params.Sort.Direction != "descending" {
+			return nil, invalid("body.sort.direction should be `ascending` or `descending`, instead was `%s`.", params.Sort.Direction)
+		}
+		direction = params.Sort.Direction
+	}
+
+	type hit struct {
+		edited string
+		value  interface{}
+	}
+	var hits []hit
+	query := strings.ToLower(params.Query)
+	for _, k := range s.order {
+		if p, ok := s.pages[k]; ok && !p.Archived && object != "database" &&
+			strings.Contains(strings.ToLower(pageTitle(p)), query) {
+			hits = append(hits, hit{p.LastEditedTime, p})
+		}
+		if db, ok := s.databases[k]; ok && !db.Archived && object != "page" &&
+			strings.Contains(strings.ToLower(plainText(db.Title)), query) {
+			hits = append(hits, hit{db.LastEditedTime, db})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if direction == "ascending" {
+			return hits[i].edited < hits[j].edited
+		}
+		return hits[i].edited > hits[j].edited
+	})
+
+	results := make([]interface{}, len(hits))
+	for i, h := range hits {
+		results[i] = h.value
+	}
+	cursor, size := bodyPage(req)
+	resp := paginate(results, cursor, size)
+	resp["type"] = "page_or_database"
+	resp["page_or_database"] = map[string]interface{}{}
+	return resp, nil
+}
+
+// listUsers 处理 GET /users
+func (s *Server) listUsers(query url.Values) (interface{}, error) {
+	results := make([]interface{}, len(s.users))
+	for i, u := range s.users {
+		results[i] = u
+	}
+	cursor, size := queryPage(query)
+	resp := paginate(results, cursor, size)
+	resp["type"] = "user"
+	resp["user"] = map[string]interface{}{}
+	return resp, nil
+}
+
+// getUser 处理 GET /users/{id} 和 GET /users/me
+func (s *Server) getUser(id string) (interface{}, error) {
+	if id == "me" {
+		return s.bot, nil
+	}
+	for _, u := range s.users {
+		if key(u.ID) == key(id) {
+			return u, nil
+		}
+	}
+	return nil, notFound("user", id)
+}
+
+// listComments 处理 GET /comments?block_id=，返回页面或块上未解决的评论
+func (s *Server) listComments(query url.Values) (interface{}, error) {
+	blockID := query.Get("block_id")
+	if blockID == "" {
+		return nil, invalid("block_id should be defined, instead was `undefined`.")
+	}
+	if _, ok := s.blocks[key(blockID)]; !ok {
+		return nil, notFound("block", blockID)
+	}
+	var results []interface{}
+	for _, c := range s.comments {
+		if key(c.ParentID) == key(blockID) && !c.Resolved {
+			results = append(results, c)
+		}
+	}
+	cursor, size := queryPage(query)
+	resp := paginate(results, cursor, size)
+	resp["type"] = "comment"
+	resp["comment"] = map[string]interface{}{}
+	return resp, nil
+}
+
+// createComment 处理 POST /comments
+//
+// 父对象可以用 Notion 的 parent 对象或 parent_id/parent_type 指定，回复已有讨论时使用 discussion_id。
+func (s *Server) createComment(req map[string]interface{}) (interface{}, error) {
+	var params struct {
+		notion.CreateCommentParams
+		Parent       *notion.Parent `json:"parent"`
+		DiscussionID string         `json:"discussion_id"`
+	}
+	if err := decode(req, &params); err != nil {
+		return nil, err
+	}
+	if len(params.RichText) == 0 {
+		return nil, invalid("body.rich_text should be defined, instead was `undefined`.")
+	}
+
+	c := &notion.Comment{
+		ParentID:   params.ParentID,
+		ParentType: params.ParentType,
+		RichText:   params.RichText,
+		Discussion: params.Discussion,
+	}
+	if params.Parent != nil {
+		c.ParentType = params.Parent.Type
+		c.ParentID = params.Parent.PageID
+		if c.ParentID == "" {
+			c.ParentID = params.Parent.BlockID
+		}
+	}
+	if params.DiscussionID != "" {
+		for _, other := range s.comments {
+			if other.Discussion != nil && key(other.Discussion.ID) == key(params.DiscussionID) {
+				c.ParentID, c.ParentType = other.ParentID, other.ParentType
+				c.Discussion = other.Discussion
+			}
+		}
+		if c.Discussion == nil {
+			return nil, notFound("discussion", params.DiscussionID)
+		}
+	}
+	if c.ParentID == "" {
+		return nil, invalid("body.parent should be defined, instead was `undefined`.")
+	}
+	if _, ok := s.blocks[key(c.ParentID)]; !ok {
+		return nil, notFound("block", c.ParentID)
+	}
+	s.insertComment(c)
+	return c, nil
+}
+
+// insertComment 保存评论，未设置的 ID、时间和讨论自动生成
+func (s *Server) insertComment(c *notion.Comment) {
+	now := s.now()
+	c.Object = "comment"
+	if c.ID == "" {
+		c.ID = s.newID()
+	}
+	if c.ParentType == "" {
+		c.ParentType = "page_id"
+		if _, ok := s.pages[key(c.ParentID)]; !ok {
+			c.ParentType = "block_id"
+		}
+	}
+	if c.CreatedTime == "" {
+		c.CreatedTime = now
+	}
+	if c.LastEditedTime == "" {
+		c.LastEditedTime = now
+	}
+	if len(c.CreatedBy) == 0 {
+		c.CreatedBy, _ = json.Marshal(s.userRef())
+	}
+	if c.Discussion == nil || c.Discussion.ID == "" {
+		id := s.newID()
+		c.Discussion = &notion.Discussion{ID: id, ThreadID: id}
+	}
+	s.comments = append(s.comments, c)
+}
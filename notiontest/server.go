@@ -0,0 +1,498 @@
+// Package notiontest 提供内存中的 Notion API 模拟服务器，用于测试使用 SDK 的代码
+//
+// 服务器实现了页面、数据库（包括带过滤和排序的查询）、块、搜索、用户和评论接口，
+// 可以预置数据、注入故障并记录收到的请求：
+//
+//	client, srv := notiontest.NewClient(t)
+//	db := srv.AddDatabase(notion.Database{...})
+//	srv.Inject(notiontest.Fault{Path: "/databases", Status: 429, Times: 1})
+//	pages, err := client.Database.QueryAll(ctx, db.ID, nil, 0)
+package notiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	notion "github.com/kuekiko/NotionGO"
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// timeLayout 是 Notion 使用的时间格式
+const timeLayout = "2006-01-02T15:04:05.000Z"
+
+// Server 是内存中的 Notion API 模拟服务器
+type Server struct {
+	// URL 是服务器的 API 基础地址，以 /v1/ 结尾
+	URL string
+
+	// Token 不为空时只接受使用该令牌的请求，其他请求返回 401
+	Token string
+
+	// Now 返回服务器的当前时间，用于创建时间和最后编辑时间，默认为 time.Now
+	Now func() time.Time
+
+	ts *httptest.Server
+
+	mu        sync.Mutex
+	seq       int
+	pages     map[string]*notion.Page
+	databases map[string]*notion.Database
+	blocks    map[string]map[string]interface{} // 块以 JSON 对象保存，页面同时保存为 child_page 块
+	children  map[string][]string               // 父对象的键到子块 ID 的有序列表
+	order     []string                          // 页面和数据库的键，按创建顺序排列
+	users     []*notion.User
+	comments  []*notion.Comment
+	bot       *notion.User
+	faults    []*Fault
+	requests  []Request
+}
+
+// Fault 表示注入的故障，匹配的请求在处理前先等待 Latency，Status 不为 0 时直接返回错误响应
+type Fault struct {
+	Method     string        // 匹配的请求方法，为空时匹配所有方法
+	Path       string        // 匹配的路径前缀，例如 "/databases"，为空时匹配所有路径
+	Status     int           // 返回的状态码，例如 429 或 503，0 表示只增加延迟
+	RetryAfter time.Duration // 响应的 Retry-After 头，按秒向上取整
+	Latency    time.Duration // 处理请求前的延迟
+	Times      int           // 生效的次数，0 表示一直生效
+}
+
+// Request 表示服务器收到的请求
+type Request struct {
+	Method string
+	Path   string // 不含 /v1 前缀和查询字符串，例如 "/pages/abc"
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// NewServer 创建并启动模拟服务器，使用完毕后调用 Close
+//
+// 服务器包含一个代表当前集成的机器人用户，通过 /users/me 返回。
+func NewServer() *Server {
+	s := &Server{
+		Now:       time.Now,
+		pages:     make(map[string]*notion.Page),
+		databases: make(map[string]*notion.Database),
+		blocks:    make(map[string]map[string]interface{}),
+		children:  make(map[string][]string),
+	}
+	s.bot = &notion.User{
+		Object: "user",
+		ID:     s.newID(),
+		Type:   "bot",
+		Name:   "notiontest",
+		Bot:    &notion.Bot{Owner: &notion.Owner{Type: "workspace", Workspace: true}},
+	}
+	s.users = append(s.users, s.bot)
+	s.ts = httptest.NewServer(s)
+	s.URL = s.ts.URL + "/v1/"
+	return s
+}
+
+// NewClient 创建模拟服务器和连接到它的客户端，测试结束时自动关闭服务器
+//
+// 客户端不限制速率，重试等待时间很短，opts 可以覆盖这些设置。
+func NewClient(t testing.TB, opts ...notion.ClientOption) (*notion.Client, *Server) {
+	s := NewServer()
+	t.Cleanup(s.Close)
+	return s.Client(opts...), s
+}
+
+// Client 返回连接到服务器的客户端
+func (s *Server) Client(opts ...notion.ClientOption) *notion.Client {
+	token := s.Token
+	if token == "" {
+		token = "notiontest-token"
+	}
+	base := []notion.ClientOption{
+		notion.WithBaseURL(s.URL),
+		notion.WithRateLimit(0, 0),
+		notion.WithRetryWaitTime(time.Millisecond, 10*time.Millisecond),
+	}
+	return notion.NewClient(token, append(base, opts...)...)
+}
+
+// Close 关闭服务器
+func (s *Server) Close() {
+	s.ts.Close()
+}
+
+// Inject 注入故障，多个故障按注入顺序匹配，只有第一个匹配的故障生效
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults 删除所有注入的故障
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests 返回服务器收到的所有请求，包括被注入故障的请求
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ResetRequests 清空记录的请求
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// ServeHTTP 实现 http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	path := "/" + strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1"), "/")
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	requestID := fmt.Sprintf("notiontest-%d", len(s.requests))
+	fault := s.matchFault(r.Method, path)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Notion-Request-Id", requestID)
+	if fault != nil {
+		if fault.Latency > 0 {
+			time.Sleep(fault.Latency)
+		}
+		if fault.Status != 0 {
+			if fault.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(fault.RetryAfter.Seconds()))))
+			}
+			writeError(w, fault.Status, string(errors.CodeForStatus(fault.Status)), "notiontest: 注入的故障")
+			return
+		}
+	}
+
+	if s.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, string(errors.ErrUnauthorized), "API token is invalid.")
+		return
+	}
+
+	var req map[string]interface{}
+	if len(body) > 0 {
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err != nil {
+			writeError(w, http.StatusBadRequest, string(errors.ErrInvalidJSON), "Error parsing JSON body.")
+			return
+		}
+		normalizeRichText(v)
+		req, _ = v.(map[string]interface{})
+	}
+	if req == nil {
+		req = map[string]interface{}{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, resp := s.route(r.Method, strings.Split(strings.Trim(path, "/"), "/"), r.URL.Query(), req)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// matchFault 返回匹配请求的故障并减少剩余次数，调用方必须持有锁
+func (s *Server) matchFault(method, path string) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, method) {
+			continue
+		}
+		if f.Path != "" && !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+		matched := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+// apiError 表示处理请求时的错误响应
+type apiError struct {
+	status  int
+	code    errors.ErrorCode
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+// notFound 返回对象不存在的错误
+func notFound(kind, id string) *apiError {
+	return &apiError{http.StatusNotFound, errors.ErrObjectNotFound,
+		fmt.Sprintf("Could not find %s with ID: %s.", kind, id)}
+}
+
+// invalid 返回参数校验错误
+func invalid(format string, args ...interface{}) *apiError {
+	return &apiError{http.StatusBadRequest, errors.ErrValidation, fmt.Sprintf(format, args...)}
+}
+
+// route 分发请求，返回状态码和响应体，调用方必须持有锁
+func (s *Server) route(method string, seg []string, query url.Values, req map[string]interface{}) (int, interface{}) {
+	resp, err := s.dispatch(method, seg, query, req)
+	if err != nil {
+		e, ok := err.(*apiError)
+		if !ok {
+			e = &apiError{http.StatusBadRequest, errors.ErrInvalidRequest, err.Error()}
+		}
+		return e.status, errorBody(e.status, string(e.code), e.message)
+	}
+	return http.StatusOK, resp
+}
+
+// dispatch 根据方法和路径调用对应的处理函数
+func (s *Server) dispatch(method string, seg []string, query url.Values, req map[string]interface{}) (interface{}, error) {
+	n := len(seg)
+	switch {
+	case n == 1 && seg[0] == "pages" && method == http.MethodPost:
+		return s.createPage(req)
+	case n == 2 && seg[0] == "pages":
+		switch method {
+		case http.MethodGet:
+			return s.getPage(seg[1])
+		case http.MethodPatch:
+			return s.updatePage(seg[1], req)
+		}
+
+	case n == 1 && seg[0] == "databases":
+		switch method {
+		case http.MethodPost:
+			return s.createDatabase(req)
+		case http.MethodGet:
+			return s.listDatabases(query)
+		}
+	case n == 2 && seg[0] == "databases":
+		switch method {
+		case http.MethodGet:
+			return s.getDatabase(seg[1])
+		case http.MethodPatch:
+			return s.updateDatabase(seg[1], req)
+		}
+	case n == 3 && seg[0] == "databases" && seg[2] == "query" && method == http.MethodPost:
+		return s.queryDatabase(seg[1], req)
+
+	case n == 2 && seg[0] == "blocks":
+		switch method {
+		case http.MethodGet:
+			return s.getBlock(seg[1])
+		case http.MethodPatch:
+			return s.updateBlock(seg[1], req)
+		case http.MethodDelete:
+			return s.archiveBlock(seg[1])
+		}
+	case n == 3 && seg[0] == "blocks" && seg[2] == "children":
+		switch method {
+		case http.MethodGet:
+			return s.listChildren(seg[1], query)
+		case http.MethodPatch:
+			return s.appendChildren(seg[1], req)
+		}
+
+	case n == 1 && seg[0] == "search" && method == http.MethodPost:
+		return s.search(req)
+
+	case n == 1 && seg[0] == "users" && method == http.MethodGet:
+		return s.listUsers(query)
+	case n == 2 && seg[0] == "users" && method == http.MethodGet:
+		return s.getUser(seg[1])
+
+	case n == 1 && seg[0] == "comments":
+		switch method {
+		case http.MethodGet:
+			return s.listComments(query)
+		case http.MethodPost:
+			return s.createComment(req)
+		}
+	}
+	return nil, &apiError{http.StatusBadRequest, errors.ErrInvalidRequestURL,
+		fmt.Sprintf("notiontest: 不支持的接口 %s /%s", method, strings.Join(seg, "/"))}
+}
+
+// errorBody 返回 Notion 格式的错误响应体
+func errorBody(status int, code, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"object":  "error",
+		"status":  status,
+		"code":    code,
+		"message": message,
+	}
+}
+
+// writeError 写入错误响应
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorBody(status, code, message))
+}
+
+// newID 返回新的 UUID 格式 ID，调用方必须持有锁（NewServer 中除外）
+func (s *Server) newID() string {
+	s.seq++
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", s.seq, s.seq)
+}
+
+// now 返回 Notion 格式的当前时间
+func (s *Server) now() string {
+	return s.Now().UTC().Format(timeLayout)
+}
+
+// key 返回对象 ID 的查找键，带连字符和不带连字符的 ID 对应同一个对象
+func key(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "-", ""))
+}
+
+// paginate 返回分页的结果，start_cursor 是结果的下标
+func paginate(results []interface{}, cursor string, pageSize int) map[string]interface{} {
+	start, _ := strconv.Atoi(cursor)
+	if start < 0 || start > len(results) {
+		start = len(results)
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 100
+	}
+	end := start + pageSize
+	if end > len(results) {
+		end = len(results)
+	}
+
+	resp := map[string]interface{}{
+		"object":      "list",
+		"results":     append([]interface{}{}, results[start:end]...),
+		"has_more":    end < len(results),
+		"next_cursor": nil,
+	}
+	if end < len(results) {
+		resp["next_cursor"] = strconv.Itoa(end)
+	}
+	return resp
+}
+
+// queryPage 从查询字符串中读取分页参数
+func queryPage(query url.Values) (string, int) {
+	size, _ := strconv.Atoi(query.Get("page_size"))
+	return query.Get("start_cursor"), size
+}
+
+// bodyPage 从请求体中读取分页参数
+func bodyPage(req map[string]interface{}) (string, int) {
+	cursor, _ := req["start_cursor"].(string)
+	size, _ := req["page_size"].(float64)
+	return cursor, int(size)
+}
+
+// decode 将 JSON 值解码到 v
+func decode(from interface{}, v interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return invalid("body failed validation: %v", err)
+	}
+	return nil
+}
+
+// normalizeRichText 为请求中的富文本补充 plain_text、type、href 和 annotations，与 Notion 的响应保持一致
+func normalizeRichText(v interface{}) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, item := range v {
+			normalizeRichText(item)
+		}
+	case map[string]interface{}:
+		if text, ok := v["text"].(map[string]interface{}); ok {
+			if content, ok := text["content"].(string); ok {
+				if v["type"] == nil || v["type"] == "" {
+					v["type"] = "text"
+				}
+				if v["plain_text"] == nil || v["plain_text"] == "" {
+					v["plain_text"] = content
+				}
+				if link, ok := text["link"].(map[string]interface{}); ok && v["href"] == nil {
+					v["href"] = link["url"]
+				}
+				if _, ok := v["annotations"]; !ok {
+					v["annotations"] = map[string]interface{}{
+						"bold": false, "italic": false, "strikethrough": false,
+						"underline": false, "code": false, "color": "default",
+					}
+				}
+				return
+			}
+		}
+		if eq, ok := v["equation"].(map[string]interface{}); ok && v["type"] == "equation" {
+			if v["plain_text"] == nil || v["plain_text"] == "" {
+				v["plain_text"] = eq["expression"]
+			}
+		}
+		if m, ok := v["mention"].(map[string]interface{}); ok && (v["type"] == "mention" || v["type"] == nil) {
+			normalizeMention(v, m)
+			return
+		}
+		for _, item := range v {
+			normalizeRichText(item)
+		}
+	}
+}
+
+// normalizeMention 补充提及的 type、plain_text 和 href，页面和数据库提及链接到对象的 URL
+func normalizeMention(v, m map[string]interface{}) {
+	v["type"] = "mention"
+	var rt notion.RichText
+	if err := decode(v, &rt); err != nil || rt.Mention == nil {
+		return
+	}
+	if v["plain_text"] == nil || v["plain_text"] == "" {
+		v["plain_text"] = rt.Content(nil)
+	}
+	if v["href"] == nil {
+		switch {
+		case rt.Mention.Page != nil:
+			v["href"] = "https://www.notion.so/" + key(rt.Mention.Page.ID)
+		case rt.Mention.Database != nil:
+			v["href"] = "https://www.notion.so/" + key(rt.Mention.Database.ID)
+		case rt.Mention.LinkPreview != nil:
+			v["href"] = rt.Mention.LinkPreview.URL
+		}
+	}
+	if _, ok := v["annotations"]; !ok {
+		v["annotations"] = map[string]interface{}{
+			"bold": false, "italic": false, "strikethrough": false,
+			"underline": false, "code": false, "color": "default",
+		}
+	}
+	if _, ok := m["type"]; !ok {
+		for typ := range m {
+			m["type"] = typ
+		}
+	}
+}
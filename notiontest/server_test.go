@@ -0,0 +1,455 @@
+package notiontest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	notion "github.com/kuekiko/NotionGO"
+	"github.com/kuekiko/NotionGO/errors"
+	"github.com/kuekiko/NotionGO/filter"
+)
+
+// title 返回页面的标题
+func title(t *testing.T, p *notion.Page) string {
+	t.Helper()
+	for _, v := range p.Properties {
+		if v.Type == notion.PropertyTypeTitle {
+			return v.PlainText()
+		}
+	}
+	t.Fatalf("页面 %s 没有标题属性", p.ID)
+	return ""
+}
+
+func TestPagesAndBlocks(t *testing.T) {
+	client, srv := NewClient(t)
+	ctx := context.Background()
+
+	root, err := srv.AddPage(notion.Page{
+		Parent:     notion.Parent{Type: "workspace"},
+		Properties: notion.Properties{"title": notion.NewTitleValue("根页面")},
+	})
+	if err != nil {
+		t.Fatalf("添加页面失败: %v", err)
+	}
+
+	page, err := client.Pages.Create(ctx, &notion.PageCreateParams{
+		Parent:     notion.Parent{Type: "page_id", PageID: root.ID},
+		Properties: notion.Properties{"title": notion.NewTitleValue("子页面")},
+		Children: []notion.Block{
+			notion.Heading1("标题"),
+			notion.Toggle("折叠", notion.Paragraph("里面")),
+		},
+	})
+	if err != nil {
+		t.Fatalf("创建页面失败: %v", err)
+	}
+	if title(t, page) != "子页面" || page.Parent.PageID != root.ID {
+		t.Errorf("创建的页面 = %+v", page)
+	}
+
+	// 子页面出现在父页面的子块中
+	children, err := client.Blocks.ListChildren(ctx, root.ID, nil)
+	if err != nil {
+		t.Fatalf("列出子块失败: %v", err)
+	}
+	if len(children.Results) != 1 || children.Results[0].Type != notion.TypeChildPage ||
+		children.Results[0].ChildPage.Title != "子页面" || !children.Results[0].HasChildren {
+		t.Errorf("父页面的子块 = %+v", children.Results)
+	}
+
+	tree, err := client.Blocks.GetTree(ctx, page.ID, nil)
+	if err != nil {
+		t.Fatalf("获取块树失败: %v", err)
+	}
+	if len(tree) != 2 || len(tree[1].Children) != 1 || tree[1].Children[0].Paragraph.RichText[0].PlainText != "里面" {
+		t.Errorf("块树不正确: %+v", tree)
+	}
+
+	// 修改标题后子页面块同步
+	page.Properties["title"] = notion.NewTitleValue("改名")
+	if _, err := client.Pages.Update(ctx, page.ID, page); err != nil {
+		t.Fatalf("更新页面失败: %v", err)
+	}
+	block, err := client.Blocks.Get(ctx, page.ID)
+	if err != nil {
+		t.Fatalf("获取块失败: %v", err)
+	}
+	if block.ChildPage == nil || block.ChildPage.Title != "改名" {
+		t.Errorf("子页面块的标题 = %+v", block.ChildPage)
+	}
+
+	// 删除的块不再出现在子块列表中
+	if err := client.Blocks.Delete(ctx, tree[0].ID); err != nil {
+		t.Fatalf("删除块失败: %v", err)
+	}
+	children, err = client.Blocks.ListChildren(ctx, page.ID, nil)
+	if err != nil {
+		t.Fatalf("列出子块失败: %v", err)
+	}
+	if len(children.Results) != 1 || children.Results[0].Type != notion.TypeToggle {
+		t.Errorf("删除后的子块 = %+v", children.Results)
+	}
+
+	// 不存在的对象返回 object_not_found
+	_, err = client.Pages.Get(ctx, "missing")
+	var apiErr *errors.Error
+	if !stderrors.As(err, &apiErr) || apiErr.Code != errors.ErrObjectNotFound || apiErr.RequestID == "" {
+		t.Errorf("错误 = %v, 期望带请求 ID 的 object_not_found", err)
+	}
+}
+
+func TestAppendChildrenLimits(t *testing.T) {
+	client, srv := NewClient(t)
+	ctx := context.Background()
+
+	page, _ := srv.AddPage(notion.Page{Parent: notion.Parent{Type: "workspace"}})
+	blocks := make([]notion.Block, 150)
+	for i := range blocks {
+		blocks[i] = notion.Paragraph("段落")
+	}
+	// 三层嵌套超过单次请求的限制，由 SDK 拆分
+	blocks[0] = notion.Toggle("一", notion.Toggle("二", notion.Paragraph("三")))
+
+	srv.ResetRequests()
+	if _, err := client.Blocks.AppendChildren(ctx, page.ID, blocks); err != nil {
+		t.Fatalf("追加子块失败: %v", err)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("请求数 = %d, 期望 3", n)
+	}
+	all, err := client.Blocks.ListChildrenIter(ctx, page.ID, nil).All(0)
+	if err != nil {
+		t.Fatalf("列出子块失败: %v", err)
+	}
+	if len(all) != 150 {
+		t.Errorf("子块数 = %d, 期望 150", len(all))
+	}
+
+	// 直接发送超过限制的请求返回 validation_error
+	body, _ := json.Marshal(map[string]interface{}{
+		"children": []notion.Block{notion.Toggle("一", notion.Toggle("二", notion.Paragraph("三")))},
+	})
+	req, _ := http.NewRequest(http.MethodPatch, srv.URL+"blocks/"+page.ID+"/children", bytes.NewReader(body))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+	var e errors.Error
+	json.NewDecoder(resp.Body).Decode(&e)
+	if resp.StatusCode != http.StatusBadRequest || e.Code != errors.ErrValidation {
+		t.Errorf("响应 = %d %s, 期望 400 validation_error", resp.StatusCode, e.Code)
+	}
+}
+
+// seedTasks 创建任务数据库和四个任务
+func seedTasks(t *testing.T, srv *Server) *notion.Database {
+	t.Helper()
+	db, err := srv.AddDatabase(notion.Database{
+		Title: []notion.RichText{notion.Plain("任务")},
+		Properties: map[string]notion.Property{
+			"名称": {Type: notion.PropertyTypeTitle, Title: &notion.EmptyObject{}},
+			"状态": {Type: notion.PropertyTypeSelect, Select: &notion.SelectConfig{}},
+			"分数": {Type: notion.PropertyTypeNumber, Number: &notion.NumberConfig{Format: "number"}},
+			"完成": {Type: notion.PropertyTypeCheckbox, Checkbox: &notion.EmptyObject{}},
+			"截止": {Type: notion.PropertyTypeDate, Date: &notion.EmptyObject{}},
+			"标签": {Type: notion.PropertyTypeMultiSelect, MultiSelect: &notion.SelectConfig{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("添加数据库失败: %v", err)
+	}
+	rows := []notion.Properties{
+		{"名称": notion.NewTitleValue("写文档"), "状态": notion.NewSelectValue("进行中"), "分数": notion.NewNumberValue(3),
+			"截止": notion.NewDateValue("2024-05-01", ""), "标签": notion.NewMultiSelectValue("文档")},
+		{"名称": notion.NewTitleValue("修复 Bug"), "状态": notion.NewSelectValue("进行中"), "分数": notion.NewNumberValue(8),
+			"截止": notion.NewDateValue("2024-04-01", ""), "标签": notion.NewMultiSelectValue("代码", "紧急")},
+		{"名称": notion.NewTitleValue("发布"), "状态": notion.NewSelectValue("完成"), "分数": notion.NewNumberValue(5),
+			"完成": notion.NewCheckboxValue(true)},
+		{"名称": notion.NewTitleValue("规划")},
+	}
+	for _, props := range rows {
+		if _, err := srv.AddPage(notion.Page{Parent: notion.Parent{Type: "database_id", DatabaseID: db.ID}, Properties: props}); err != nil {
+			t.Fatalf("添加页面失败: %v", err)
+		}
+	}
+	return db
+}
+
+// titles 返回页面标题列表
+func titles(t *testing.T, pages []*notion.Page) []string {
+	names := make([]string, len(pages))
+	for i, p := range pages {
+		names[i] = title(t, p)
+	}
+	return names
+}
+
+func TestArchive(t *testing.T) {
+	client, srv := NewClient(t)
+	ctx := context.Background()
+	db := seedTasks(t, srv)
+	page, err := client.Pages.Create(ctx, &notion.PageCreateParams{
+		Parent:     notion.Parent{Type: "database_id", DatabaseID: db.ID},
+		Properties: notion.Properties{"名称": notion.NewTitleValue("待归档")},
+	})
+	if err != nil {
+		t.Fatalf("创建页面失败: %v", err)
+	}
+
+	// Notion 没有删除页面和数据库的接口，DELETE 请求返回 invalid_request_url
+	var apiErr *errors.Error
+	if err := client.Pages.Delete(ctx, page.ID); !stderrors.As(err, &apiErr) || apiErr.Code != errors.ErrInvalidRequestURL {
+		t.Errorf("删除页面错误 = %v, 期望 invalid_request_url", err)
+	}
+	if err := client.Database.Delete(ctx, db.ID); !stderrors.As(err, &apiErr) || apiErr.Code != errors.ErrInvalidRequestURL {
+		t.Errorf("删除数据库错误 = %v, 期望 invalid_request_url", err)
+	}
+
+	archived, err := client.Pages.Archive(ctx, page.ID)
+	if err != nil {
+		t.Fatalf("归档页面失败: %v", err)
+	}
+	if !archived.Archived {
+		t.Error("归档后页面应标记为 archived")
+	}
+	pages, err := client.Database.QueryAll(ctx, db.ID, nil, 0)
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	for _, p := range pages {
+		if p.ID == page.ID {
+			t.Error("归档的页面不应出现在查询结果中")
+		}
+	}
+}
+
+func TestDatabaseQuery(t *testing.T) {
+	client, srv := NewClient(t)
+	ctx := context.Background()
+	db := seedTasks(t, srv)
+
+	tests := []struct {
+		name   string
+		params *notion.DatabaseQueryParams
+		want   []string
+	}{
+		{"无条件按创建顺序", nil, []string{"写文档", "修复 Bug", "发布", "规划"}},
+		{"复合条件", &notion.DatabaseQueryParams{
+			Filter: filter.And(filter.Select("状态").Equals("进行中"), filter.Number("分数").GreaterThan(5)),
+		}, []string{"修复 Bug"}},
+		{"或条件", &notion.DatabaseQueryParams{
+			Filter: filter.Or(filter.Checkbox("完成").Equals(true), filter.MultiSelect("标签").Contains("紧急")),
+		}, []string{"修复 Bug", "发布"}},
+		{"文本包含", &notion.DatabaseQueryParams{Filter: filter.Title("名称").Contains("bug")}, []string{"修复 Bug"}},
+		{"日期", &notion.DatabaseQueryParams{Filter: filter.Date("截止").Before("2024-04-15")}, []string{"修复 Bug"}},
+		{"空值", &notion.DatabaseQueryParams{Filter: filter.Select("状态").IsEmpty()}, []string{"规划"}},
+		{"按数字降序，空值在后", &notion.DatabaseQueryParams{
+			Sorts: []notion.Sort{{Property: "分数", Direction: "descending"}},
+		}, []string{"修复 Bug", "发布", "写文档", "规划"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages, err := client.Database.QueryAll(ctx, db.ID, tt.params, 0)
+			if err != nil {
+				t.Fatalf("查询失败: %v", err)
+			}
+			got := titles(t, pages)
+			if len(got) != len(tt.want) {
+				t.Fatalf("结果 = %v, 期望 %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("结果 = %v, 期望 %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	// 分页
+	list, err := client.Database.Query(ctx, db.ID, &notion.DatabaseQueryParams{PageSize: 3})
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if len(list.Results) != 3 || !list.HasMore || list.NextCursor == "" {
+		t.Errorf("第一页 = %d 条, has_more = %v, next_cursor = %q", len(list.Results), list.HasMore, list.NextCursor)
+	}
+
+	// 不存在的属性返回 validation_error
+	_, err = client.Database.Query(ctx, db.ID, &notion.DatabaseQueryParams{Filter: filter.Number("不存在").Equals(1)})
+	var apiErr *errors.Error
+	if !stderrors.As(err, &apiErr) || apiErr.Code != errors.ErrValidation {
+		t.Errorf("错误 = %v, 期望 validation_error", err)
+	}
+
+	// 新建的页面包含数据库的所有属性
+	page, err := client.Pages.Create(ctx, &notion.PageCreateParams{
+		Parent:     notion.Parent{Type: "database_id", DatabaseID: db.ID},
+		Properties: notion.Properties{"名称": notion.NewTitleValue("新任务")},
+	})
+	if err != nil {
+		t.Fatalf("创建页面失败: %v", err)
+	}
+	if len(page.Properties) != len(db.Properties) || page.Properties["分数"].Type != notion.PropertyTypeNumber {
+		t.Errorf("新页面的属性 = %+v", page.Properties)
+	}
+}
+
+func TestSearchUsersAndComments(t *testing.T) {
+	client, srv := NewClient(t)
+	ctx := context.Background()
+	db := seedTasks(t, srv)
+	alice := srv.AddUser(notion.User{Name: "Alice", Person: &notion.Person{Email: "alice@example.com"}})
+
+	result, err := client.Search.Search(ctx, &notion.SearchParams{Query: "任务"})
+	if err != nil {
+		t.Fatalf("搜索失败: %v", err)
+	}
+	if len(result.Results) != 1 {
+		t.Errorf("搜索结果 = %d 条, 期望 1", len(result.Results))
+	}
+	result, err = client.Search.Search(ctx, &notion.SearchParams{Filter: &notion.SearchFilter{Property: "object", Value: "page"}})
+	if err != nil {
+		t.Fatalf("搜索失败: %v", err)
+	}
+	if len(result.Results) != 4 {
+		t.Errorf("页面搜索结果 = %d 条, 期望 4", len(result.Results))
+	}
+
+	users, err := client.Users.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("列出用户失败: %v", err)
+	}
+	if len(users.Results) != 2 {
+		t.Errorf("用户数 = %d, 期望 2", len(users.Results))
+	}
+	me, err := client.Users.Me(ctx)
+	if err != nil || me.Type != "bot" {
+		t.Errorf("Me() = %+v, %v", me, err)
+	}
+	u, err := client.Users.Get(ctx, alice.ID)
+	if err != nil || u.Name != "Alice" {
+		t.Errorf("Get() = %+v, %v", u, err)
+	}
+
+	pages, _ := client.Database.QueryAll(ctx, db.ID, nil, 0)
+	if _, err := client.Comments.Create(ctx, &notion.CreateCommentParams{
+		ParentID:   pages[0].ID,
+		ParentType: "page_id",
+		RichText:   []notion.RichText{notion.Plain("看起来不错")},
+	}); err != nil {
+		t.Fatalf("创建评论失败: %v", err)
+	}
+	comments, err := client.Comments.List(ctx, pages[0].ID, nil)
+	if err != nil {
+		t.Fatalf("列出评论失败: %v", err)
+	}
+	if len(comments.Results) != 1 || comments.Results[0].RichText[0].PlainText != "看起来不错" {
+		t.Errorf("评论 = %+v", comments.Results)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	client, srv := NewClient(t)
+	ctx := context.Background()
+	page, _ := srv.AddPage(notion.Page{Parent: notion.Parent{Type: "workspace"}})
+
+	// 两次 429 之后成功，客户端自动重试
+	srv.Inject(Fault{Path: "/pages", Status: http.StatusTooManyRequests, Times: 2})
+	srv.ResetRequests()
+	if _, err := client.Pages.Get(ctx, page.ID); err != nil {
+		t.Fatalf("重试后仍然失败: %v", err)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("请求数 = %d, 期望 3", n)
+	}
+
+	// 只匹配指定方法和路径
+	srv.Inject(Fault{Method: http.MethodPost, Path: "/search", Status: http.StatusServiceUnavailable})
+	if _, err := client.Pages.Get(ctx, page.ID); err != nil {
+		t.Errorf("不匹配的请求不应失败: %v", err)
+	}
+	noRetry := srv.Client(notion.WithMaxRetries(0))
+	_, err := noRetry.Search.Search(ctx, &notion.SearchParams{})
+	var apiErr *errors.Error
+	if !stderrors.As(err, &apiErr) || apiErr.Status != http.StatusServiceUnavailable || apiErr.Code != errors.ErrServiceUnavailable {
+		t.Errorf("错误 = %v, 期望 503 service_unavailable", err)
+	}
+	srv.ClearFaults()
+
+	// 延迟
+	srv.Inject(Fault{Latency: 50 * time.Millisecond, Times: 1})
+	start := time.Now()
+	if _, err := client.Users.Me(ctx); err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Errorf("耗时 %v, 期望至少 50ms", d)
+	}
+
+	// 请求记录包含方法、路径和请求头
+	reqs := srv.Requests()
+	last := reqs[len(reqs)-1]
+	if last.Method != http.MethodGet || last.Path != "/users/me" || last.Header.Get("Authorization") != "Bearer notiontest-token" {
+		t.Errorf("最后一个请求 = %s %s %v", last.Method, last.Path, last.Header)
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	client, srv := NewClient(t)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	data := `{
+		"pages": [{"id": "11111111-1111-1111-1111-111111111111", "parent": {"type": "workspace", "workspace": true},
+			"properties": {"title": {"title": [{"text": {"content": "首页"}}]}}}],
+		"blocks": {"11111111-1111-1111-1111-111111111111": [
+			{"type": "paragraph", "paragraph": {"rich_text": [{"text": {"content": "你好"}}]}}
+		]}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.LoadFixtures(path); err != nil {
+		t.Fatalf("加载预置数据失败: %v", err)
+	}
+
+	// 不带连字符的 ID 指向同一个页面
+	page, err := client.Pages.Get(ctx, "11111111111111111111111111111111")
+	if err != nil {
+		t.Fatalf("获取页面失败: %v", err)
+	}
+	if title(t, page) != "首页" {
+		t.Errorf("标题 = %q", title(t, page))
+	}
+	children, err := client.Blocks.ListChildren(ctx, page.ID, nil)
+	if err != nil {
+		t.Fatalf("列出子块失败: %v", err)
+	}
+	if len(children.Results) != 1 || children.Results[0].Paragraph.RichText[0].PlainText != "你好" {
+		t.Errorf("子块 = %+v", children.Results)
+	}
+}
+
+func TestToken(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Token = "secret"
+
+	if _, err := srv.Client().Users.Me(context.Background()); err != nil {
+		t.Errorf("正确的令牌应通过验证: %v", err)
+	}
+	_, err := notion.NewClient("wrong", notion.WithBaseURL(srv.URL)).Users.Me(context.Background())
+	var apiErr *errors.Error
+	if !stderrors.As(err, &apiErr) || apiErr.Code != errors.ErrUnauthorized {
+		t.Errorf("错误 = %v, 期望 unauthorized", err)
+	}
+}
@@ -0,0 +1,218 @@
+package notion
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/kuekiko/NotionGO/client"
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// OAuthConfig 表示公共集成的 OAuth 配置
+type OAuthConfig struct {
+	ClientID     string     // 集成的 OAuth client ID
+	ClientSecret string     // 集成的 OAuth client secret
+	RedirectURI  string     // 集成设置中登记的回调地址，只登记了一个地址时可以为空
+	Store        TokenStore // 保存授权得到的令牌，为 nil 时使用 MemoryTokenStore
+}
+
+// Token 表示授权码换取的访问令牌和对应的工作区
+type Token struct {
+	AccessToken          string `json:"access_token"`
+	TokenType            string `json:"token_type"` // 总是 "bearer"
+	BotID                string `json:"bot_id"`     // 这次授权创建的机器人用户 ID
+	WorkspaceID          string `json:"workspace_id"`
+	WorkspaceName        string `json:"workspace_name,omitempty"`
+	WorkspaceIcon        string `json:"workspace_icon,omitempty"`
+	Owner                *Owner `json:"owner,omitempty"`                  // 授权的用户或工作区
+	DuplicatedTemplateID string `json:"duplicated_template_id,omitempty"` // 用户选择复制模板时为复制出的页面 ID
+}
+
+// TokenInfo 表示令牌的检查结果
+type TokenInfo struct {
+	Active   bool   `json:"active"`          // 令牌是否有效
+	Scope    string `json:"scope,omitempty"` // 令牌的权限范围
+	IssuedAt int64  `json:"iat,omitempty"`   // 签发时间，Unix 秒
+}
+
+// OAuth 实现公共集成的授权流程，并为每个工作区创建客户端
+//
+// 授权码换取的令牌按机器人 ID 和工作区 ID 两个键保存到 TokenStore，之后使用 ClientFor 获取对应的客户端：
+//
+//	auth := notion.NewOAuth(notion.OAuthConfig{ClientID: id, ClientSecret: secret, RedirectURI: redirect})
+//	http.Redirect(w, r, auth.AuthURL(state), http.StatusFound)
+//	// 回调中
+//	token, err := auth.Exchange(ctx, r.URL.Query().Get("code"))
+//	c, err := auth.ClientFor(ctx, token.WorkspaceID)
+type OAuth struct {
+	config OAuthConfig
+	opts   []ClientOption
+	client *client.Client // 使用 Basic 认证请求 oauth 接口
+
+	mu      sync.Mutex
+	clients map[string]*Client // 按访问令牌缓存的客户端，使同一个工作区共用连接和速率限制
+}
+
+// NewOAuth 创建 OAuth 流程，opts 同时用于 oauth 接口的请求和 ClientFor 创建的客户端
+//
+// oauth 接口的请求体和响应体包含授权码和访问令牌，即使设置了 WithLogBody 也不记录。
+func NewOAuth(config OAuthConfig, opts ...ClientOption) *OAuth {
+	if config.Store == nil {
+		config.Store = NewMemoryTokenStore()
+	}
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	clientOpts := append(o.clientOptions[:len(o.clientOptions):len(o.clientOptions)],
+		client.WithBasicAuth(config.ClientID, config.ClientSecret),
+		client.WithLogBody(0))
+
+	return &OAuth{
+		config:  config,
+		opts:    opts,
+		client:  client.NewClient("", clientOpts...),
+		clients: make(map[string]*Client),
+	}
+}
+
+// Store 返回保存令牌的 TokenStore
+func (a *OAuth) Store() TokenStore {
+	return a.config.Store
+}
+
+// AuthURL 返回授权页面的地址，state 会原样传回回调地址，用于防止 CSRF
+func (a *OAuth) AuthURL(state string) string {
+	query := url.Values{}
+	query.Set("client_id", a.config.ClientID)
+	query.Set("response_type", "code")
+	query.Set("owner", "user")
+	if a.config.RedirectURI != "" {
+		query.Set("redirect_uri", a.config.RedirectURI)
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+	return a.client.BaseURL() + "oauth/authorize?" + query.Encode()
+}
+
+// Exchange 使用回调收到的授权码换取访问令牌，并保存到 TokenStore
+func (a *OAuth) Exchange(ctx context.Context, code string) (*Token, error) {
+	if code == "" {
+		return nil, errors.NewError(errors.ErrInvalidInput, "授权码不能为空", 0)
+	}
+	body := map[string]string{
+		"grant_type": "authorization_code",
+		"code":       code,
+	}
+	if a.config.RedirectURI != "" {
+		body["redirect_uri"] = a.config.RedirectURI
+	}
+
+	token := new(Token)
+	if err := a.client.Post(ctx, "oauth/token", body, token); err != nil {
+		return nil, err
+	}
+	for _, key := range tokenKeys(token) {
+		if err := a.config.Store.Set(ctx, key, token); err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
+}
+
+// Introspect 检查访问令牌是否仍然有效
+func (a *OAuth) Introspect(ctx context.Context, accessToken string) (*TokenInfo, error) {
+	info := new(TokenInfo)
+	err := a.client.Post(ctx, "oauth/introspect", map[string]string{"token": accessToken}, info)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Revoke 撤销令牌，并从 TokenStore 中删除仍然指向该令牌的条目
+//
+// 令牌在进程内共享的速率限制器同时被丢弃；通过 ContextFor 使用过该令牌的 Client 可以调用 Client.ForgetToken。
+func (a *OAuth) Revoke(ctx context.Context, token *Token) error {
+	if token == nil || token.AccessToken == "" {
+		return errors.NewError(errors.ErrInvalidInput, "令牌不能为空", 0)
+	}
+	if err := a.client.Post(ctx, "oauth/revoke", map[string]string{"token": token.AccessToken}, nil); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	delete(a.clients, token.AccessToken)
+	a.mu.Unlock()
+	client.ForgetToken(token.AccessToken)
+
+	for _, key := range tokenKeys(token) {
+		// 同一个工作区可能已经被重新授权，只删除旧令牌
+		stored, err := a.config.Store.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if stored != nil && stored.AccessToken == token.AccessToken {
+			if err := a.config.Store.Delete(ctx, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ClientFor 返回使用 key（工作区 ID 或机器人 ID）对应令牌的客户端
+//
+// 使用同一个令牌的调用返回同一个客户端；TokenStore 中没有该键时返回 errors.ErrTokenNotFound。
+func (a *OAuth) ClientFor(ctx context.Context, key string) (*Client, error) {
+	token, err := a.token(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	c, ok := a.clients[token.AccessToken]
+	if !ok {
+		c = NewClient(token.AccessToken, a.opts...)
+		a.clients[token.AccessToken] = c
+	}
+	return c, nil
+}
+
+// ContextFor 返回使用 key（工作区 ID 或机器人 ID）对应令牌的 ctx，用于通过一个共用的 Client 访问多个工作区
+//
+// TokenStore 中没有该键时返回 errors.ErrTokenNotFound。
+func (a *OAuth) ContextFor(ctx context.Context, key string) (context.Context, error) {
+	token, err := a.token(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return ContextWithToken(ctx, token.AccessToken), nil
+}
+
+// token 返回 key 对应的令牌
+func (a *OAuth) token(ctx context.Context, key string) (*Token, error) {
+	token, err := a.config.Store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || token.AccessToken == "" {
+		return nil, errors.NewError(errors.ErrTokenNotFound, "没有 "+key+" 的令牌", 0)
+	}
+	return token, nil
+}
+
+// tokenKeys 返回保存令牌使用的键
+func tokenKeys(token *Token) []string {
+	keys := make([]string, 0, 2)
+	if token.BotID != "" {
+		keys = append(keys, token.BotID)
+	}
+	if token.WorkspaceID != "" && token.WorkspaceID != token.BotID {
+		keys = append(keys, token.WorkspaceID)
+	}
+	return keys
+}
@@ -0,0 +1,344 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kuekiko/NotionGO/client"
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// oauthStub 是模拟 Notion OAuth 接口的服务器，每个授权码换取一个新的令牌
+type oauthStub struct {
+	mu      sync.Mutex
+	active  map[string]bool
+	auth    []string
+	revoked []string
+}
+
+func (s *oauthStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	auth := r.Header.Get("Authorization")
+	s.auth = append(s.auth, auth)
+	var body map[string]string
+	json.NewDecoder(r.Body).Decode(&body)
+
+	if strings.HasPrefix(r.URL.Path, "/oauth/") && auth != "Basic Y2xpZW50LWlkOmNsaWVudC1zZWNyZXQ=" { // client-id:client-secret
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"object":"error","status":401,"code":"unauthorized","message":"invalid client"}`))
+		return
+	}
+
+	switch r.URL.Path {
+	case "/oauth/token":
+		if body["grant_type"] != "authorization_code" || body["redirect_uri"] != "https://example.com/callback" || !strings.HasPrefix(body["code"], "code-") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"object":"error","status":400,"code":"invalid_grant","message":"Invalid code."}`))
+			return
+		}
+		workspace := strings.TrimPrefix(body["code"], "code-")
+		token := "secret_" + workspace + "_" + string(rune('0'+len(s.active)))
+		s.active[token] = true
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":   token,
+			"token_type":     "bearer",
+			"bot_id":         "bot-" + token,
+			"workspace_id":   workspace,
+			"workspace_name": "工作区 " + workspace,
+			"workspace_icon": nil,
+			"owner":          map[string]interface{}{"type": "user", "user": map[string]interface{}{"object": "user", "id": "u1"}},
+		})
+	case "/oauth/introspect":
+		json.NewEncoder(w).Encode(map[string]interface{}{"active": s.active[body["token"]], "scope": "read_content", "iat": 1700000000})
+	case "/oauth/revoke":
+		delete(s.active, body["token"])
+		s.revoked = append(s.revoked, body["token"])
+		w.Write([]byte(`{}`))
+	case "/users/me":
+		if token := strings.TrimPrefix(auth, "Bearer "); !s.active[token] {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"object":"error","status":401,"code":"unauthorized","message":"API token is invalid."}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"object": "user", "id": "bot-" + strings.TrimPrefix(auth, "Bearer "), "type": "bot"})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newOAuthStub(t *testing.T, store TokenStore) (*OAuth, *oauthStub) {
+	stub := &oauthStub{active: make(map[string]bool)}
+	ts := httptest.NewServer(stub)
+	t.Cleanup(ts.Close)
+	auth := NewOAuth(OAuthConfig{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURI:  "https://example.com/callback",
+		Store:        store,
+	}, WithBaseURL(ts.URL), WithRateLimit(0, 0), WithMaxRetries(0))
+	return auth, stub
+}
+
+func TestOAuthAuthURL(t *testing.T) {
+	auth := NewOAuth(OAuthConfig{ClientID: "client-id", RedirectURI: "https://example.com/callback"})
+	u, err := url.Parse(auth.AuthURL("xyz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme+"://"+u.Host+u.Path != "https://api.notion.com/v1/oauth/authorize" {
+		t.Errorf("授权地址错误: %s", u)
+	}
+	want := url.Values{
+		"client_id":     {"client-id"},
+		"response_type": {"code"},
+		"owner":         {"user"},
+		"redirect_uri":  {"https://example.com/callback"},
+		"state":         {"xyz"},
+	}
+	if u.Query().Encode() != want.Encode() {
+		t.Errorf("授权地址参数 = %s，期望 %s", u.RawQuery, want.Encode())
+	}
+}
+
+func TestOAuthFlow(t *testing.T) {
+	ctx := context.Background()
+	auth, stub := newOAuthStub(t, nil)
+
+	token, err := auth.Exchange(ctx, "code-ws1")
+	if err != nil {
+		t.Fatalf("换取令牌失败: %v", err)
+	}
+	if token.WorkspaceID != "ws1" || token.WorkspaceName != "工作区 ws1" || token.Owner == nil || token.Owner.User == nil {
+		t.Errorf("令牌解码错误: %+v", token)
+	}
+
+	// 按工作区 ID 和机器人 ID 都可以取得客户端，同一个令牌共用客户端
+	c1, err := auth.ClientFor(ctx, "ws1")
+	if err != nil {
+		t.Fatalf("获取客户端失败: %v", err)
+	}
+	c2, err := auth.ClientFor(ctx, token.BotID)
+	if err != nil || c1 != c2 {
+		t.Errorf("同一个令牌应返回同一个客户端: %v", err)
+	}
+	me, err := c1.Users.Me(ctx)
+	if err != nil || me.ID != token.BotID {
+		t.Errorf("客户端没有使用工作区的令牌: %+v, %v", me, err)
+	}
+
+	other, err := auth.Exchange(ctx, "code-ws2")
+	if err != nil {
+		t.Fatalf("换取第二个令牌失败: %v", err)
+	}
+	c3, err := auth.ClientFor(ctx, "ws2")
+	if err != nil || c3 == c1 {
+		t.Errorf("不同工作区应使用不同的客户端: %v", err)
+	}
+
+	info, err := auth.Introspect(ctx, other.AccessToken)
+	if err != nil || !info.Active || info.Scope != "read_content" || info.IssuedAt != 1700000000 {
+		t.Errorf("检查令牌结果错误: %+v, %v", info, err)
+	}
+
+	if err := auth.Revoke(ctx, token); err != nil {
+		t.Fatalf("撤销令牌失败: %v", err)
+	}
+	if _, err := auth.ClientFor(ctx, "ws1"); !isCode(err, errors.ErrTokenNotFound) {
+		t.Errorf("撤销后应返回 ErrTokenNotFound，得到 %v", err)
+	}
+	if info, err := auth.Introspect(ctx, token.AccessToken); err != nil || info.Active {
+		t.Errorf("撤销后令牌仍然有效: %+v, %v", info, err)
+	}
+	if _, err := auth.ClientFor(ctx, "ws2"); err != nil {
+		t.Errorf("撤销其他工作区的令牌不应影响 ws2: %v", err)
+	}
+
+	for _, a := range stub.auth {
+		if strings.Contains(a, "client-secret") {
+			t.Errorf("请求头中出现明文的 client secret: %s", a)
+		}
+	}
+}
+
+func TestOAuthReauthorizeKeepsNewToken(t *testing.T) {
+	ctx := context.Background()
+	auth, _ := newOAuthStub(t, nil)
+
+	old, err := auth.Exchange(ctx, "code-ws1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Exchange(ctx, "code-ws1"); err != nil {
+		t.Fatal(err)
+	}
+	// 旧令牌撤销后，工作区仍然指向重新授权得到的令牌
+	if err := auth.Revoke(ctx, old); err != nil {
+		t.Fatalf("撤销令牌失败: %v", err)
+	}
+	c, err := auth.ClientFor(ctx, "ws1")
+	if err != nil {
+		t.Fatalf("重新授权的令牌被删除: %v", err)
+	}
+	if _, err := c.Users.Me(ctx); err != nil {
+		t.Errorf("重新授权的客户端请求失败: %v", err)
+	}
+}
+
+func TestOAuthExchangeErrors(t *testing.T) {
+	ctx := context.Background()
+	auth, _ := newOAuthStub(t, nil)
+
+	if _, err := auth.Exchange(ctx, ""); !isCode(err, errors.ErrInvalidInput) {
+		t.Errorf("空授权码应返回 ErrInvalidInput，得到 %v", err)
+	}
+	if _, err := auth.Exchange(ctx, "bad"); !isCode(err, errors.ErrInvalidGrant) {
+		t.Errorf("无效授权码应返回 ErrInvalidGrant，得到 %v", err)
+	}
+
+	wrong := NewOAuth(OAuthConfig{ClientID: "client-id", ClientSecret: "wrong"}, WithBaseURL(auth.client.BaseURL()), WithMaxRetries(0))
+	if _, err := wrong.Exchange(ctx, "code-ws1"); !errors.IsUnauthorized(err) {
+		t.Errorf("错误的 client secret 应返回 401，得到 %v", err)
+	}
+}
+
+func TestOAuthDoesNotLogSecrets(t *testing.T) {
+	ctx := context.Background()
+	stub := &oauthStub{active: make(map[string]bool)}
+	ts := httptest.NewServer(stub)
+	defer ts.Close()
+
+	var logs []string
+	logger := client.LoggerFunc(func(ctx context.Context, level client.LogLevel, msg string, args ...interface{}) {
+		logs = append(logs, msg+fmt.Sprint(args...))
+	})
+	auth := NewOAuth(OAuthConfig{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURI:  "https://example.com/callback",
+	}, WithBaseURL(ts.URL), WithRateLimit(0, 0), WithLogger(logger), WithLogBody(4096))
+
+	token, err := auth.Exchange(ctx, "code-ws1")
+	if err != nil {
+		t.Fatalf("换取令牌失败: %v", err)
+	}
+	if _, err := auth.Introspect(ctx, token.AccessToken); err != nil {
+		t.Fatalf("检查令牌失败: %v", err)
+	}
+	if len(logs) == 0 {
+		t.Fatal("没有记录日志")
+	}
+	for _, line := range logs {
+		if strings.Contains(line, token.AccessToken) || strings.Contains(line, "code-ws1") {
+			t.Errorf("日志中出现授权码或访问令牌: %s", line)
+		}
+	}
+}
+
+func TestFileTokenStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "oauth", "tokens.json")
+	store, err := NewFileTokenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth, _ := newOAuthStub(t, store)
+	token, err := auth.Exchange(ctx, "code-ws1")
+	if err != nil {
+		t.Fatalf("换取令牌失败: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("令牌文件不存在: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("令牌文件权限 = %v，期望 0600", info.Mode().Perm())
+	}
+
+	// 重新打开文件后令牌仍然存在
+	reopened, err := NewFileTokenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reopened.Get(ctx, "ws1")
+	if err != nil || got == nil || got.AccessToken != token.AccessToken {
+		t.Errorf("重新打开后读取的令牌 = %+v, %v", got, err)
+	}
+	if err := reopened.Delete(ctx, "ws1"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Get(ctx, "ws1"); got != nil || err != nil {
+		t.Errorf("删除后仍然读取到令牌: %+v, %v", got, err)
+	}
+	if got, _ := store.Get(ctx, token.BotID); got == nil {
+		t.Error("删除工作区的键不应影响机器人 ID 的键")
+	}
+}
+
+func TestOAuthContextFor(t *testing.T) {
+	ctx := context.Background()
+	auth, _ := newOAuthStub(t, nil)
+	a, err := auth.Exchange(ctx, "code-ws1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := auth.Exchange(ctx, "code-ws2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 一个共用的客户端通过 ctx 访问两个工作区
+	shared := NewClient("", WithBaseURL(auth.client.BaseURL()), WithRateLimit(0, 0), WithMaxRetries(0))
+	for _, token := range []*Token{a, b} {
+		wctx, err := auth.ContextFor(ctx, token.WorkspaceID)
+		if err != nil {
+			t.Fatalf("获取 %s 的 ctx 失败: %v", token.WorkspaceID, err)
+		}
+		me, err := shared.Users.Me(wctx)
+		if err != nil || me.ID != token.BotID {
+			t.Errorf("%s 的请求没有使用对应的令牌: %+v, %v", token.WorkspaceID, me, err)
+		}
+	}
+	if _, err := auth.ContextFor(ctx, "ws3"); !isCode(err, errors.ErrTokenNotFound) {
+		t.Errorf("没有令牌时应返回 ErrTokenNotFound，得到 %v", err)
+	}
+}
+
+func TestOAuthWithWorkspaceContext(t *testing.T) {
+	ctx := context.Background()
+	auth, _ := newOAuthStub(t, nil)
+	token, err := auth.Exchange(ctx, "code-ws1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ContextFor 返回的 ctx 传给 oauth 接口时仍然使用 client secret 认证
+	wctx, err := auth.ContextFor(ctx, "ws1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, err := auth.Introspect(wctx, token.AccessToken); err != nil || !info.Active {
+		t.Errorf("使用工作区 ctx 检查令牌失败: %+v, %v", info, err)
+	}
+	if _, err := auth.Exchange(wctx, "code-ws2"); err != nil {
+		t.Errorf("使用工作区 ctx 换取令牌失败: %v", err)
+	}
+	if err := auth.Revoke(wctx, token); err != nil {
+		t.Fatalf("使用工作区 ctx 撤销令牌失败: %v", err)
+	}
+	if _, err := auth.ClientFor(ctx, "ws1"); !isCode(err, errors.ErrTokenNotFound) {
+		t.Errorf("撤销后应返回 ErrTokenNotFound，得到 %v", err)
+	}
+}
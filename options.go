@@ -0,0 +1,168 @@
+package notion
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/kuekiko/NotionGO/cache"
+	"github.com/kuekiko/NotionGO/client"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ClientOption 表示 Client 的配置选项
+type ClientOption func(*options)
+
+// options 保存 NewClient 使用的配置
+type options struct {
+	clientOptions []client.Option
+}
+
+// WithClientOptions 直接传入底层 client 包的配置选项
+func WithClientOptions(opts ...client.Option) ClientOption {
+	return func(o *options) {
+		o.clientOptions = append(o.clientOptions, opts...)
+	}
+}
+
+// WithBaseURL 设置 API 基础 URL，例如指向本地模拟服务器或企业出口代理
+func WithBaseURL(baseURL string) ClientOption {
+	return WithClientOptions(client.WithBaseURL(baseURL))
+}
+
+// WithAPIVersion 设置 Notion-Version 请求头
+func WithAPIVersion(version string) ClientOption {
+	return WithClientOptions(client.WithAPIVersion(version))
+}
+
+// WithUserAgent 设置 User-Agent 请求头
+func WithUserAgent(userAgent string) ClientOption {
+	return WithClientOptions(client.WithUserAgent(userAgent))
+}
+
+// WithTimeout 设置单次调用（包括重试）的总超时时间，0 表示不限制
+func WithTimeout(timeout time.Duration) ClientOption {
+	return WithClientOptions(client.WithTimeout(timeout))
+}
+
+// WithReadTimeout 设置读取响应的超时时间
+func WithReadTimeout(timeout time.Duration) ClientOption {
+	return WithClientOptions(client.WithReadTimeout(timeout))
+}
+
+// WithWriteTimeout 设置发送请求的超时时间
+func WithWriteTimeout(timeout time.Duration) ClientOption {
+	return WithClientOptions(client.WithWriteTimeout(timeout))
+}
+
+// WithMaxConnsPerHost 设置每个主机的最大连接数
+func WithMaxConnsPerHost(n int) ClientOption {
+	return WithClientOptions(client.WithMaxConnsPerHost(n))
+}
+
+// WithMaxIdleConnDuration 设置空闲连接的保留时间
+func WithMaxIdleConnDuration(d time.Duration) ClientOption {
+	return WithClientOptions(client.WithMaxIdleConnDuration(d))
+}
+
+// WithMaxResponseBodySize 设置响应体的最大字节数
+func WithMaxResponseBodySize(size int) ClientOption {
+	return WithClientOptions(client.WithMaxResponseBodySize(size))
+}
+
+// WithDialer 设置建立连接使用的拨号函数
+func WithDialer(dial fasthttp.DialFunc) ClientOption {
+	return WithClientOptions(client.WithDialer(dial))
+}
+
+// WithProxy 通过代理服务器发送请求，支持 http:// 和 socks5:// 代理
+func WithProxy(proxyURL string) ClientOption {
+	return WithClientOptions(client.WithProxy(proxyURL))
+}
+
+// WithTLSConfig 设置 TLS 配置
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return WithClientOptions(client.WithTLSConfig(config))
+}
+
+// WithTransport 使用自定义的 client.Transport 发送请求
+func WithTransport(t client.Transport) ClientOption {
+	return WithClientOptions(client.WithTransport(t))
+}
+
+// WithHTTPClient 使用 net/http 代替 fasthttp 发送请求，c 为 nil 时根据其他选项创建，支持 HTTP/2 和代理环境变量
+func WithHTTPClient(c *http.Client) ClientOption {
+	return WithClientOptions(client.WithHTTPClient(c))
+}
+
+// WithMiddleware 添加中间件，先添加的中间件先看到请求，例如
+// notion.WithMiddleware(client.ReadOnly(), client.PropagateRequestID("", nil))
+func WithMiddleware(middleware ...client.Middleware) ClientOption {
+	return WithClientOptions(client.WithMiddleware(middleware...))
+}
+
+// WithMaxRetries 设置默认重试策略的最大重试次数
+func WithMaxRetries(n int) ClientOption {
+	return WithClientOptions(client.WithMaxRetries(n))
+}
+
+// WithRetryWaitTime 设置默认重试策略的最小和最大等待时间
+func WithRetryWaitTime(min, max time.Duration) ClientOption {
+	return WithClientOptions(client.WithRetryWaitTime(min, max))
+}
+
+// WithRetryPolicy 设置重试策略
+func WithRetryPolicy(policy client.RetryPolicy) ClientOption {
+	return WithClientOptions(client.WithRetryPolicy(policy))
+}
+
+// WithLogger 设置日志记录器，默认不输出日志
+func WithLogger(logger client.Logger) ClientOption {
+	return WithClientOptions(client.WithLogger(logger))
+}
+
+// WithLogBody 在 Debug 级别记录请求体和响应体，超过 maxBytes 的部分被截断
+func WithLogBody(maxBytes int) ClientOption {
+	return WithClientOptions(client.WithLogBody(maxBytes))
+}
+
+// WithTracerProvider 为每个 API 调用记录一个以服务方法命名的 span，例如 "Pages.Get"
+func WithTracerProvider(tp trace.TracerProvider) ClientOption {
+	return WithClientOptions(client.WithTracerProvider(tp))
+}
+
+// WithMeterProvider 记录请求数、耗时、429 次数和收发的字节数
+func WithMeterProvider(mp metric.MeterProvider) ClientOption {
+	return WithClientOptions(client.WithMeterProvider(mp))
+}
+
+// WithRateLimit 设置每秒允许的请求数和突发请求数，默认为每秒 3 个、突发 10 个，
+// requestsPerSecond 不大于 0 时不限制速率
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return WithClientOptions(client.WithRateLimit(requestsPerSecond, burst))
+}
+
+// WithRateLimiter 使用自定义的速率限制器
+func WithRateLimiter(limiter client.RateLimiter) ClientOption {
+	return WithClientOptions(client.WithRateLimiter(limiter))
+}
+
+// WithSharedRateLimit 让使用同一个令牌的所有 Client 共用一个速率限制器，
+// 第一个创建的 Client 的 WithRateLimit 设置生效
+func WithSharedRateLimit() ClientOption {
+	return WithClientOptions(client.WithSharedRateLimit())
+}
+
+// WithMaxConcurrentRequests 设置同时进行的最大请求数，0 表示不限制
+func WithMaxConcurrentRequests(n int) ClientOption {
+	return WithClientOptions(client.WithMaxConcurrentRequests(n))
+}
+
+// WithCache 缓存页面、数据库、用户和块的 GET 响应，例如 notion.WithCache(cache.NewMemoryCache())
+//
+// 通过同一个客户端修改的对象会立即失效，其他响应中的 last_edited_time 用于重新验证缓存。
+func WithCache(c cache.Cache) ClientOption {
+	return WithClientOptions(client.WithCache(c))
+}
@@ -1,19 +1,23 @@
 package notion
 
+import (
+	"context"
+)
+
 // Page 表示页面对象
 type Page struct {
-	Object         string                 `json:"object"`           // 总是 "page"
-	ID             string                 `json:"id"`               // 页面 ID
-	CreatedTime    string                 `json:"created_time"`     // 创建时间
-	LastEditedTime string                 `json:"last_edited_time"` // 最后编辑时间
-	CreatedBy      User                   `json:"created_by"`       // 创建者
-	LastEditedBy   User                   `json:"last_edited_by"`   // 最后编辑者
-	Parent         Parent                 `json:"parent"`           // 父对象
-	Archived       bool                   `json:"archived"`         // 是否已归档
-	Properties     map[string]interface{} `json:"properties"`       // 属性
-	URL            string                 `json:"url"`              // URL
-	Icon           *Icon                  `json:"icon,omitempty"`   // 图标
-	Cover          *File                  `json:"cover,omitempty"`  // 封面
+	Object         string     `json:"object"`           // 总是 "page"
+	ID             string     `json:"id"`               // 页面 ID
+	CreatedTime    string     `json:"created_time"`     // 创建时间
+	LastEditedTime string     `json:"last_edited_time"` // 最后编辑时间
+	CreatedBy      User       `json:"created_by"`       // 创建者
+	LastEditedBy   User       `json:"last_edited_by"`   // 最后编辑者
+	Parent         Parent     `json:"parent"`           // 父对象
+	Archived       bool       `json:"archived"`         // 是否已归档
+	Properties     Properties `json:"properties"`       // 属性
+	URL            string     `json:"url"`              // URL
+	Icon           *Icon      `json:"icon,omitempty"`   // 图标
+	Cover          *File      `json:"cover,omitempty"`  // 封面
 }
 
 // PageService 表示页面服务
@@ -28,17 +32,17 @@ func NewPageService(client *Client) *PageService {
 
 // PageCreateParams 表示创建页面的参数
 type PageCreateParams struct {
-	Parent     Parent                 `json:"parent"`             // 父对象
-	Properties map[string]interface{} `json:"properties"`         // 属性
-	Children   []Block                `json:"children,omitempty"` // 子块
-	Icon       *Icon                  `json:"icon,omitempty"`     // 图标
-	Cover      *File                  `json:"cover,omitempty"`    // 封面
+	Parent     Parent     `json:"parent"`             // 父对象
+	Properties Properties `json:"properties"`         // 属性
+	Children   []Block    `json:"children,omitempty"` // 子块
+	Icon       *Icon      `json:"icon,omitempty"`     // 图标
+	Cover      *File      `json:"cover,omitempty"`    // 封面
 }
 
 // Create 创建页面
-func (s *PageService) Create(params *PageCreateParams) (*Page, error) {
+func (s *PageService) Create(ctx context.Context, params *PageCreateParams) (*Page, error) {
 	page := new(Page)
-	err := s.client.post("pages", params, page)
+	err := s.client.post(ctx, "pages", params, page)
 	if err != nil {
 		return nil, err
 	}
@@ -46,10 +50,10 @@ func (s *PageService) Create(params *PageCreateParams) (*Page, error) {
 }
 
 // Get 获取页面
-func (s *PageService) Get(pageID string) (*Page, error) {
+func (s *PageService) Get(ctx context.Context, pageID string) (*Page, error) {
 	path := "pages/" + pageID
 	page := new(Page)
-	err := s.client.get(path, nil, page)
+	err := s.client.get(ctx, path, nil, page)
 	if err != nil {
 		return nil, err
 	}
@@ -57,24 +61,47 @@ func (s *PageService) Get(pageID string) (*Page, error) {
 }
 
 // Update 更新页面
-func (s *PageService) Update(pageID string, params *Page) (*Page, error) {
+func (s *PageService) Update(ctx context.Context, pageID string, params *Page) (*Page, error) {
 	path := "pages/" + pageID
 	page := new(Page)
-	err := s.client.patch(path, params, page)
+	err := s.client.patch(ctx, path, params, page)
 	if err != nil {
 		return nil, err
 	}
 	return page, nil
 }
 
+// UpdateProperties 只更新页面的属性，props 中没有的属性保持不变
+func (s *PageService) UpdateProperties(ctx context.Context, pageID string, props Properties) (*Page, error) {
+	page := new(Page)
+	body := map[string]interface{}{"properties": props}
+	if err := s.client.patch(ctx, "pages/"+pageID, body, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// Archive 归档页面，Notion API 通过归档删除页面，归档的页面可以在回收站中恢复
+func (s *PageService) Archive(ctx context.Context, pageID string) (*Page, error) {
+	page := new(Page)
+	body := map[string]interface{}{"archived": true}
+	if err := s.client.patch(ctx, "pages/"+pageID, body, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
 // Delete 删除页面
-func (s *PageService) Delete(pageID string) error {
+//
+// Deprecated: Notion API 没有删除页面的端点，请使用 Archive。
+func (s *PageService) Delete(ctx context.Context, pageID string) error {
 	path := "pages/" + pageID
-	return s.client.delete(path)
+	return s.client.delete(ctx, path)
 }
 
 // PropertyItem 表示属性项
 type PropertyItem struct {
+	Object   string      `json:"object"` // 总是 "property_item"
 	ID       string      `json:"id"`
 	Type     string      `json:"type"`
 	NextURL  string      `json:"next_url,omitempty"`
@@ -82,10 +109,10 @@ type PropertyItem struct {
 }
 
 // GetProperty 获取页面属性
-func (s *PageService) GetProperty(pageID, propertyID string) (*PropertyItem, error) {
+func (s *PageService) GetProperty(ctx context.Context, pageID, propertyID string) (*PropertyItem, error) {
 	path := "pages/" + pageID + "/properties/" + propertyID
 	property := new(PropertyItem)
-	err := s.client.get(path, nil, property)
+	err := s.client.get(ctx, path, nil, property)
 	if err != nil {
 		return nil, err
 	}
@@ -93,12 +120,28 @@ func (s *PageService) GetProperty(pageID, propertyID string) (*PropertyItem, err
 }
 
 // GetPropertyList 获取页面属性列表
-func (s *PageService) GetPropertyList(pageID string, params *ListParams) (*ListResponse, error) {
+func (s *PageService) GetPropertyList(ctx context.Context, pageID string, params *ListParams) (*PropertyItemList, error) {
 	path := "pages/" + pageID + "/properties"
-	response := new(ListResponse)
-	err := s.client.get(path, params, response)
+	response := new(PropertyItemList)
+	err := s.client.get(ctx, path, params, response)
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
+
+// GetPropertyListIter 返回遍历所有属性项的迭代器
+func (s *PageService) GetPropertyListIter(ctx context.Context, pageID string, params *ListParams) *Iterator[*PropertyItem] {
+	p := ListParams{}
+	if params != nil {
+		p = *params
+	}
+	return newIterator(ctx, p.StartCursor, func(ctx context.Context, cursor string) ([]*PropertyItem, string, bool, error) {
+		p.StartCursor = cursor
+		list, err := s.GetPropertyList(ctx, pageID, &p)
+		if err != nil {
+			return nil, "", false, err
+		}
+		return list.Results, list.NextCursor, list.HasMore, nil
+	})
+}
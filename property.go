@@ -0,0 +1,661 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// PropertyType 表示属性类型
+type PropertyType string
+
+const (
+	PropertyTypeTitle          PropertyType = "title"
+	PropertyTypeRichText       PropertyType = "rich_text"
+	PropertyTypeNumber         PropertyType = "number"
+	PropertyTypeSelect         PropertyType = "select"
+	PropertyTypeMultiSelect    PropertyType = "multi_select"
+	PropertyTypeStatus         PropertyType = "status"
+	PropertyTypeDate           PropertyType = "date"
+	PropertyTypePeople         PropertyType = "people"
+	PropertyTypeFiles          PropertyType = "files"
+	PropertyTypeCheckbox       PropertyType = "checkbox"
+	PropertyTypeURL            PropertyType = "url"
+	PropertyTypeEmail          PropertyType = "email"
+	PropertyTypePhoneNumber    PropertyType = "phone_number"
+	PropertyTypeFormula        PropertyType = "formula"
+	PropertyTypeRelation       PropertyType = "relation"
+	PropertyTypeRollup         PropertyType = "rollup"
+	PropertyTypeCreatedTime    PropertyType = "created_time"
+	PropertyTypeCreatedBy      PropertyType = "created_by"
+	PropertyTypeLastEditedTime PropertyType = "last_edited_time"
+	PropertyTypeLastEditedBy   PropertyType = "last_edited_by"
+	PropertyTypeUniqueID       PropertyType = "unique_id"
+	PropertyTypeVerification   PropertyType = "verification"
+)
+
+// PropertyValue 表示页面属性的值，Type 决定哪个字段有效
+type PropertyValue struct {
+	ID   string       `json:"id,omitempty"`
+	Type PropertyType `json:"type,omitempty"`
+
+	Title          []RichText         `json:"title,omitempty"`
+	RichText       []RichText         `json:"rich_text,omitempty"`
+	Number         *float64           `json:"number,omitempty"`
+	Select         *Option            `json:"select,omitempty"`
+	MultiSelect    []Option           `json:"multi_select,omitempty"`
+	Status         *Option            `json:"status,omitempty"`
+	Date           *DateValue         `json:"date,omitempty"`
+	People         []User             `json:"people,omitempty"`
+	Files          []File             `json:"files,omitempty"`
+	Checkbox       bool               `json:"checkbox,omitempty"`
+	URL            *string            `json:"url,omitempty"`
+	Email          *string            `json:"email,omitempty"`
+	PhoneNumber    *string            `json:"phone_number,omitempty"`
+	Formula        *FormulaValue      `json:"formula,omitempty"`
+	Relation       []RelationValue    `json:"relation,omitempty"`
+	Rollup         *RollupValue       `json:"rollup,omitempty"`
+	CreatedTime    string             `json:"created_time,omitempty"`
+	CreatedBy      *User              `json:"created_by,omitempty"`
+	LastEditedTime string             `json:"last_edited_time,omitempty"`
+	LastEditedBy   *User              `json:"last_edited_by,omitempty"`
+	UniqueID       *UniqueIDValue     `json:"unique_id,omitempty"`
+	Verification   *VerificationValue `json:"verification,omitempty"`
+
+	// HasMore 表示关联等属性的值被截断，需要通过 PageService.GetProperty 获取完整值
+	HasMore bool `json:"has_more,omitempty"`
+}
+
+// DateValue 表示日期值
+type DateValue struct {
+	Start    string  `json:"start"`               // ISO 8601 日期或日期时间
+	End      *string `json:"end"`                 // 结束日期，可为空
+	TimeZone *string `json:"time_zone,omitempty"` // 时区
+}
+
+// StartTime 解析开始时间
+func (d *DateValue) StartTime() (time.Time, error) {
+	return parseNotionTime(d.Start)
+}
+
+// EndTime 解析结束时间，没有结束时间时返回零值
+func (d *DateValue) EndTime() (time.Time, error) {
+	if d.End == nil {
+		return time.Time{}, nil
+	}
+	return parseNotionTime(*d.End)
+}
+
+// FormulaValue 表示公式的计算结果
+type FormulaValue struct {
+	Type    string     `json:"type"` // "string"、"number"、"boolean" 或 "date"
+	String  *string    `json:"string,omitempty"`
+	Number  *float64   `json:"number,omitempty"`
+	Boolean *bool      `json:"boolean,omitempty"`
+	Date    *DateValue `json:"date,omitempty"`
+}
+
+// RelationValue 表示关联的页面
+type RelationValue struct {
+	ID string `json:"id"`
+}
+
+// RollupValue 表示汇总的计算结果
+type RollupValue struct {
+	Type     string          `json:"type"` // "number"、"date"、"array"、"unsupported" 或 "incomplete"
+	Number   *float64        `json:"number,omitempty"`
+	Date     *DateValue      `json:"date,omitempty"`
+	Array    []PropertyValue `json:"array,omitempty"`
+	Function string          `json:"function,omitempty"`
+}
+
+// UniqueIDValue 表示唯一 ID
+type UniqueIDValue struct {
+	Number int     `json:"number"`
+	Prefix *string `json:"prefix"`
+}
+
+// String 返回带前缀的 ID，例如 "TASK-12"
+func (u *UniqueIDValue) String() string {
+	if u.Prefix != nil && *u.Prefix != "" {
+		return fmt.Sprintf("%s-%d", *u.Prefix, u.Number)
+	}
+	return fmt.Sprintf("%d", u.Number)
+}
+
+// VerificationValue 表示 wiki 页面的验证状态
+type VerificationValue struct {
+	State      string     `json:"state"` // "verified"、"unverified" 或 "expired"
+	VerifiedBy *User      `json:"verified_by,omitempty"`
+	Date       *DateValue `json:"date,omitempty"`
+}
+
+// value 返回 Type 对应的字段值，用于序列化
+func (v PropertyValue) value() interface{} {
+	switch v.Type {
+	case PropertyTypeTitle:
+		return nonNilRichText(v.Title)
+	case PropertyTypeRichText:
+		return nonNilRichText(v.RichText)
+	case PropertyTypeNumber:
+		return v.Number
+	case PropertyTypeSelect:
+		return v.Select
+	case PropertyTypeMultiSelect:
+		if v.MultiSelect == nil {
+			return []Option{}
+		}
+		return v.MultiSelect
+	case PropertyTypeStatus:
+		return v.Status
+	case PropertyTypeDate:
+		return v.Date
+	case PropertyTypePeople:
+		if v.People == nil {
+			return []User{}
+		}
+		return v.People
+	case PropertyTypeFiles:
+		if v.Files == nil {
+			return []File{}
+		}
+		return v.Files
+	case PropertyTypeCheckbox:
+		return v.Checkbox
+	case PropertyTypeURL:
+		return v.URL
+	case PropertyTypeEmail:
+		return v.Email
+	case PropertyTypePhoneNumber:
+		return v.PhoneNumber
+	case PropertyTypeFormula:
+		return v.Formula
+	case PropertyTypeRelation:
+		if v.Relation == nil {
+			return []RelationValue{}
+		}
+		return v.Relation
+	case PropertyTypeRollup:
+		return v.Rollup
+	case PropertyTypeCreatedTime:
+		return v.CreatedTime
+	case PropertyTypeCreatedBy:
+		return v.CreatedBy
+	case PropertyTypeLastEditedTime:
+		return v.LastEditedTime
+	case PropertyTypeLastEditedBy:
+		return v.LastEditedBy
+	case PropertyTypeUniqueID:
+		return v.UniqueID
+	case PropertyTypeVerification:
+		return v.Verification
+	}
+	return nil
+}
+
+// propertyValueAlias 用于避免 MarshalJSON/UnmarshalJSON 递归
+type propertyValueAlias PropertyValue
+
+// MarshalJSON 只序列化 Type 对应的字段，空值会序列化为 null 或 []，以便清空属性
+func (v PropertyValue) MarshalJSON() ([]byte, error) {
+	if v.Type == "" {
+		return json.Marshal(propertyValueAlias(v))
+	}
+
+	m := map[string]interface{}{
+		"type":         v.Type,
+		string(v.Type): v.value(),
+	}
+	if v.ID != "" {
+		m["id"] = v.ID
+	}
+	if v.HasMore {
+		m["has_more"] = true
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON 解码属性值，缺少 type 字段时根据出现的字段推断类型
+func (v *PropertyValue) UnmarshalJSON(data []byte) error {
+	var alias propertyValueAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+	*v = PropertyValue(alias)
+
+	if v.Type == "" {
+		var keys map[string]json.RawMessage
+		if err := json.Unmarshal(data, &keys); err != nil {
+			return err
+		}
+		for key := range keys {
+			if key != "id" && key != "has_more" {
+				v.Type = PropertyType(key)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// nonNilRichText 保证富文本序列化为 [] 而不是 null
+func nonNilRichText(rt []RichText) []RichText {
+	if rt == nil {
+		return []RichText{}
+	}
+	return rt
+}
+
+// PlainText 返回属性值的纯文本表示
+func (v PropertyValue) PlainText() string {
+	switch v.Type {
+	case PropertyTypeTitle:
+		return plainText(v.Title)
+	case PropertyTypeRichText:
+		return plainText(v.RichText)
+	case PropertyTypeNumber:
+		if v.Number != nil {
+			return formatNumber(*v.Number)
+		}
+	case PropertyTypeSelect:
+		if v.Select != nil {
+			return v.Select.Name
+		}
+	case PropertyTypeStatus:
+		if v.Status != nil {
+			return v.Status.Name
+		}
+	case PropertyTypeMultiSelect:
+		names := make([]string, len(v.MultiSelect))
+		for i, o := range v.MultiSelect {
+			names[i] = o.Name
+		}
+		return strings.Join(names, ", ")
+	case PropertyTypeDate:
+		if v.Date != nil {
+			if v.Date.End != nil {
+				return v.Date.Start + " → " + *v.Date.End
+			}
+			return v.Date.Start
+		}
+	case PropertyTypeCheckbox:
+		if v.Checkbox {
+			return "true"
+		}
+		return "false"
+	case PropertyTypeURL:
+		return stringValue(v.URL)
+	case PropertyTypeEmail:
+		return stringValue(v.Email)
+	case PropertyTypePhoneNumber:
+		return stringValue(v.PhoneNumber)
+	case PropertyTypeCreatedTime:
+		return v.CreatedTime
+	case PropertyTypeLastEditedTime:
+		return v.LastEditedTime
+	case PropertyTypeUniqueID:
+		if v.UniqueID != nil {
+			return v.UniqueID.String()
+		}
+	}
+	return ""
+}
+
+// plainText 拼接富文本的纯文本
+func plainText(rt []RichText) string {
+	return PlainTextOf(rt, nil)
+}
+
+// formatNumber 格式化数字，整数不带小数点
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// stringValue 返回字符串指针的值
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// parseNotionTime 解析 Notion 返回的日期或日期时间
+func parseNotionTime(s string) (time.Time, error) {
+	if len(s) == len("2006-01-02") {
+		return time.Parse("2006-01-02", s)
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// Properties 表示页面的属性集合，键为属性名称
+type Properties map[string]PropertyValue
+
+// get 获取指定类型的属性
+func (p Properties) get(name string, typ PropertyType) (*PropertyValue, error) {
+	v, ok := p[name]
+	if !ok {
+		return nil, errors.NewError(errors.ErrPropertyNotFound, fmt.Sprintf("属性 %q 不存在", name), 0)
+	}
+	if v.Type != typ {
+		return nil, errors.NewError(errors.ErrPropertyTypeMismatch, fmt.Sprintf("属性 %q 的类型是 %s，不是 %s", name, v.Type, typ), 0)
+	}
+	return &v, nil
+}
+
+// Title 返回标题属性的纯文本
+func (p Properties) Title(name string) (string, error) {
+	v, err := p.get(name, PropertyTypeTitle)
+	if err != nil {
+		return "", err
+	}
+	return plainText(v.Title), nil
+}
+
+// RichText 返回富文本属性的纯文本
+func (p Properties) RichText(name string) (string, error) {
+	v, err := p.get(name, PropertyTypeRichText)
+	if err != nil {
+		return "", err
+	}
+	return plainText(v.RichText), nil
+}
+
+// Number 返回数字属性的值，属性为空时返回 0
+func (p Properties) Number(name string) (float64, error) {
+	v, err := p.get(name, PropertyTypeNumber)
+	if err != nil || v.Number == nil {
+		return 0, err
+	}
+	return *v.Number, nil
+}
+
+// Select 返回单选属性选中的选项名称，未选择时返回空字符串
+func (p Properties) Select(name string) (string, error) {
+	v, err := p.get(name, PropertyTypeSelect)
+	if err != nil || v.Select == nil {
+		return "", err
+	}
+	return v.Select.Name, nil
+}
+
+// MultiSelect 返回多选属性选中的选项名称
+func (p Properties) MultiSelect(name string) ([]string, error) {
+	v, err := p.get(name, PropertyTypeMultiSelect)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(v.MultiSelect))
+	for i, o := range v.MultiSelect {
+		names[i] = o.Name
+	}
+	return names, nil
+}
+
+// Status 返回状态属性的名称
+func (p Properties) Status(name string) (string, error) {
+	v, err := p.get(name, PropertyTypeStatus)
+	if err != nil || v.Status == nil {
+		return "", err
+	}
+	return v.Status.Name, nil
+}
+
+// Date 返回日期属性的值，属性为空时返回 nil
+func (p Properties) Date(name string) (*DateValue, error) {
+	v, err := p.get(name, PropertyTypeDate)
+	if err != nil {
+		return nil, err
+	}
+	return v.Date, nil
+}
+
+// People 返回人员属性的用户
+func (p Properties) People(name string) ([]User, error) {
+	v, err := p.get(name, PropertyTypePeople)
+	if err != nil {
+		return nil, err
+	}
+	return v.People, nil
+}
+
+// Files 返回文件属性的文件
+func (p Properties) Files(name string) ([]File, error) {
+	v, err := p.get(name, PropertyTypeFiles)
+	if err != nil {
+		return nil, err
+	}
+	return v.Files, nil
+}
+
+// Checkbox 返回复选框属性的值
+func (p Properties) Checkbox(name string) (bool, error) {
+	v, err := p.get(name, PropertyTypeCheckbox)
+	if err != nil {
+		return false, err
+	}
+	return v.Checkbox, nil
+}
+
+// URL 返回 URL 属性的值
+func (p Properties) URL(name string) (string, error) {
+	v, err := p.get(name, PropertyTypeURL)
+	if err != nil {
+		return "", err
+	}
+	return stringValue(v.URL), nil
+}
+
+// Email 返回邮箱属性的值
+func (p Properties) Email(name string) (string, error) {
+	v, err := p.get(name, PropertyTypeEmail)
+	if err != nil {
+		return "", err
+	}
+	return stringValue(v.Email), nil
+}
+
+// PhoneNumber 返回电话属性的值
+func (p Properties) PhoneNumber(name string) (string, error) {
+	v, err := p.get(name, PropertyTypePhoneNumber)
+	if err != nil {
+		return "", err
+	}
+	return stringValue(v.PhoneNumber), nil
+}
+
+// Formula 返回公式属性的计算结果
+func (p Properties) Formula(name string) (*FormulaValue, error) {
+	v, err := p.get(name, PropertyTypeFormula)
+	if err != nil {
+		return nil, err
+	}
+	return v.Formula, nil
+}
+
+// Relation 返回关联属性中的页面 ID
+func (p Properties) Relation(name string) ([]string, error) {
+	v, err := p.get(name, PropertyTypeRelation)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(v.Relation))
+	for i, r := range v.Relation {
+		ids[i] = r.ID
+	}
+	return ids, nil
+}
+
+// Rollup 返回汇总属性的计算结果
+func (p Properties) Rollup(name string) (*RollupValue, error) {
+	v, err := p.get(name, PropertyTypeRollup)
+	if err != nil {
+		return nil, err
+	}
+	return v.Rollup, nil
+}
+
+// CreatedTime 返回创建时间属性的值
+func (p Properties) CreatedTime(name string) (time.Time, error) {
+	v, err := p.get(name, PropertyTypeCreatedTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseNotionTime(v.CreatedTime)
+}
+
+// CreatedBy 返回创建者属性的值
+func (p Properties) CreatedBy(name string) (*User, error) {
+	v, err := p.get(name, PropertyTypeCreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	return v.CreatedBy, nil
+}
+
+// LastEditedTime 返回最后编辑时间属性的值
+func (p Properties) LastEditedTime(name string) (time.Time, error) {
+	v, err := p.get(name, PropertyTypeLastEditedTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseNotionTime(v.LastEditedTime)
+}
+
+// LastEditedBy 返回最后编辑者属性的值
+func (p Properties) LastEditedBy(name string) (*User, error) {
+	v, err := p.get(name, PropertyTypeLastEditedBy)
+	if err != nil {
+		return nil, err
+	}
+	return v.LastEditedBy, nil
+}
+
+// UniqueID 返回唯一 ID 属性的值
+func (p Properties) UniqueID(name string) (*UniqueIDValue, error) {
+	v, err := p.get(name, PropertyTypeUniqueID)
+	if err != nil {
+		return nil, err
+	}
+	return v.UniqueID, nil
+}
+
+// Verification 返回验证属性的值
+func (p Properties) Verification(name string) (*VerificationValue, error) {
+	v, err := p.get(name, PropertyTypeVerification)
+	if err != nil {
+		return nil, err
+	}
+	return v.Verification, nil
+}
+
+// textRichText 创建纯文本富文本
+func textRichText(content string) []RichText {
+	return []RichText{{Type: "text", Text: &Text{Content: content}}}
+}
+
+// NewTitleValue 创建标题属性值
+func NewTitleValue(content string) PropertyValue {
+	return PropertyValue{Type: PropertyTypeTitle, Title: textRichText(content)}
+}
+
+// NewRichTextValue 创建富文本属性值
+func NewRichTextValue(content string) PropertyValue {
+	return PropertyValue{Type: PropertyTypeRichText, RichText: textRichText(content)}
+}
+
+// NewNumberValue 创建数字属性值
+func NewNumberValue(n float64) PropertyValue {
+	return PropertyValue{Type: PropertyTypeNumber, Number: &n}
+}
+
+// NewSelectValue 创建单选属性值，name 为空时清空选择
+func NewSelectValue(name string) PropertyValue {
+	v := PropertyValue{Type: PropertyTypeSelect}
+	if name != "" {
+		v.Select = &Option{Name: name}
+	}
+	return v
+}
+
+// NewMultiSelectValue 创建多选属性值
+func NewMultiSelectValue(names ...string) PropertyValue {
+	options := make([]Option, len(names))
+	for i, name := range names {
+		options[i] = Option{Name: name}
+	}
+	return PropertyValue{Type: PropertyTypeMultiSelect, MultiSelect: options}
+}
+
+// NewStatusValue 创建状态属性值
+func NewStatusValue(name string) PropertyValue {
+	return PropertyValue{Type: PropertyTypeStatus, Status: &Option{Name: name}}
+}
+
+// NewDateValue 创建日期属性值，start 和 end 为 ISO 8601 日期或日期时间，end 为空表示没有结束日期
+func NewDateValue(start, end string) PropertyValue {
+	date := &DateValue{Start: start}
+	if end != "" {
+		date.End = &end
+	}
+	return PropertyValue{Type: PropertyTypeDate, Date: date}
+}
+
+// NewDateTimeValue 使用 time.Time 创建日期属性值
+func NewDateTimeValue(t time.Time) PropertyValue {
+	return NewDateValue(t.Format(time.RFC3339), "")
+}
+
+// NewPeopleValue 创建人员属性值
+func NewPeopleValue(userIDs ...string) PropertyValue {
+	people := make([]User, len(userIDs))
+	for i, id := range userIDs {
+		people[i] = User{Object: "user", ID: id}
+	}
+	return PropertyValue{Type: PropertyTypePeople, People: people}
+}
+
+// NewFilesValue 创建文件属性值
+func NewFilesValue(files ...File) PropertyValue {
+	return PropertyValue{Type: PropertyTypeFiles, Files: files}
+}
+
+// NewCheckboxValue 创建复选框属性值
+func NewCheckboxValue(checked bool) PropertyValue {
+	return PropertyValue{Type: PropertyTypeCheckbox, Checkbox: checked}
+}
+
+// NewURLValue 创建 URL 属性值，url 为空时清空属性
+func NewURLValue(url string) PropertyValue {
+	return PropertyValue{Type: PropertyTypeURL, URL: optionalString(url)}
+}
+
+// NewEmailValue 创建邮箱属性值，email 为空时清空属性
+func NewEmailValue(email string) PropertyValue {
+	return PropertyValue{Type: PropertyTypeEmail, Email: optionalString(email)}
+}
+
+// NewPhoneNumberValue 创建电话属性值，phone 为空时清空属性
+func NewPhoneNumberValue(phone string) PropertyValue {
+	return PropertyValue{Type: PropertyTypePhoneNumber, PhoneNumber: optionalString(phone)}
+}
+
+// NewRelationValue 创建关联属性值
+func NewRelationValue(pageIDs ...string) PropertyValue {
+	relation := make([]RelationValue, len(pageIDs))
+	for i, id := range pageIDs {
+		relation[i] = RelationValue{ID: id}
+	}
+	return PropertyValue{Type: PropertyTypeRelation, Relation: relation}
+}
+
+// optionalString 将空字符串转换为 nil
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,170 @@
+package notion
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+const testPageJSON = `{
+	"object": "page",
+	"id": "page-1",
+	"properties": {
+		"Name": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "任务"}, "plain_text": "任务"}]},
+		"完成度": {"id": "a", "type": "number", "number": 0.3},
+		"空数字": {"id": "b", "type": "number", "number": null},
+		"状态": {"id": "c", "type": "status", "status": {"id": "s1", "name": "进行中", "color": "blue"}},
+		"标签": {"id": "d", "type": "multi_select", "multi_select": [{"name": "go"}, {"name": "sdk"}]},
+		"截止日期": {"id": "e", "type": "date", "date": {"start": "2024-12-08", "end": null, "time_zone": null}},
+		"完成": {"id": "f", "type": "checkbox", "checkbox": true},
+		"链接": {"id": "g", "type": "url", "url": "https://example.com"},
+		"公式": {"id": "h", "type": "formula", "formula": {"type": "number", "number": 42}},
+		"关联": {"id": "i", "type": "relation", "relation": [{"id": "page-2"}], "has_more": false},
+		"汇总": {"id": "j", "type": "rollup", "rollup": {"type": "array", "array": [{"type": "number", "number": 1}], "function": "show_original"}},
+		"编号": {"id": "k", "type": "unique_id", "unique_id": {"number": 12, "prefix": "TASK"}},
+		"创建时间": {"id": "l", "type": "created_time", "created_time": "2024-12-08T10:00:00.000Z"},
+		"创建者": {"id": "m", "type": "created_by", "created_by": {"object": "user", "id": "user-1"}},
+		"验证": {"id": "n", "type": "verification", "verification": {"state": "verified", "verified_by": null, "date": null}}
+	}
+}`
+
+func TestPropertiesAccessors(t *testing.T) {
+	var page Page
+	if err := json.Unmarshal([]byte(testPageJSON), &page); err != nil {
+		t.Fatalf("解码页面失败: %v", err)
+	}
+	props := page.Properties
+
+	if title, err := props.Title("Name"); err != nil || title != "任务" {
+		t.Errorf("Title = %q, %v", title, err)
+	}
+	if n, err := props.Number("完成度"); err != nil || n != 0.3 {
+		t.Errorf("Number = %v, %v", n, err)
+	}
+	if n, err := props.Number("空数字"); err != nil || n != 0 {
+		t.Errorf("空 Number = %v, %v", n, err)
+	}
+	if s, err := props.Status("状态"); err != nil || s != "进行中" {
+		t.Errorf("Status = %q, %v", s, err)
+	}
+	if tags, err := props.MultiSelect("标签"); err != nil || len(tags) != 2 || tags[1] != "sdk" {
+		t.Errorf("MultiSelect = %v, %v", tags, err)
+	}
+	if d, err := props.Date("截止日期"); err != nil || d.Start != "2024-12-08" {
+		t.Errorf("Date = %+v, %v", d, err)
+	} else if start, err := d.StartTime(); err != nil || start.Day() != 8 {
+		t.Errorf("StartTime = %v, %v", start, err)
+	}
+	if c, err := props.Checkbox("完成"); err != nil || !c {
+		t.Errorf("Checkbox = %v, %v", c, err)
+	}
+	if u, err := props.URL("链接"); err != nil || u != "https://example.com" {
+		t.Errorf("URL = %q, %v", u, err)
+	}
+	if f, err := props.Formula("公式"); err != nil || *f.Number != 42 {
+		t.Errorf("Formula = %+v, %v", f, err)
+	}
+	if ids, err := props.Relation("关联"); err != nil || len(ids) != 1 || ids[0] != "page-2" {
+		t.Errorf("Relation = %v, %v", ids, err)
+	}
+	if r, err := props.Rollup("汇总"); err != nil || len(r.Array) != 1 || *r.Array[0].Number != 1 {
+		t.Errorf("Rollup = %+v, %v", r, err)
+	}
+	if id, err := props.UniqueID("编号"); err != nil || id.String() != "TASK-12" {
+		t.Errorf("UniqueID = %v, %v", id, err)
+	}
+	if ct, err := props.CreatedTime("创建时间"); err != nil || ct.Year() != 2024 {
+		t.Errorf("CreatedTime = %v, %v", ct, err)
+	}
+	if u, err := props.CreatedBy("创建者"); err != nil || u.ID != "user-1" {
+		t.Errorf("CreatedBy = %+v, %v", u, err)
+	}
+	if v, err := props.Verification("验证"); err != nil || v.State != "verified" {
+		t.Errorf("Verification = %+v, %v", v, err)
+	}
+
+	if _, err := props.Title("不存在"); !isCode(err, errors.ErrPropertyNotFound) {
+		t.Errorf("期望属性不存在错误，得到 %v", err)
+	}
+	if _, err := props.Number("Name"); !isCode(err, errors.ErrPropertyTypeMismatch) {
+		t.Errorf("期望类型不匹配错误，得到 %v", err)
+	}
+}
+
+func isCode(err error, code errors.ErrorCode) bool {
+	e, ok := err.(*errors.Error)
+	return ok && e.Code == code
+}
+
+func TestPropertyValueMarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value PropertyValue
+		want  string
+	}{
+		{"title", NewTitleValue("Hi"), `{"title":[{"type":"text","text":{"content":"Hi"},"plain_text":""}],"type":"title"}`},
+		{"number", NewNumberValue(30), `{"number":30,"type":"number"}`},
+		{"clear select", NewSelectValue(""), `{"select":null,"type":"select"}`},
+		{"empty multi_select", NewMultiSelectValue(), `{"multi_select":[],"type":"multi_select"}`},
+		{"date", NewDateValue("2024-12-08", ""), `{"date":{"start":"2024-12-08","end":null},"type":"date"}`},
+		{"checkbox", NewCheckboxValue(false), `{"checkbox":false,"type":"checkbox"}`},
+		{"clear url", NewURLValue(""), `{"type":"url","url":null}`},
+		{"relation", NewRelationValue("p1"), `{"relation":[{"id":"p1"}],"type":"relation"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("编码失败: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("得到 %s，期望 %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestPropertyValueRoundTrip(t *testing.T) {
+	var page Page
+	if err := json.Unmarshal([]byte(testPageJSON), &page); err != nil {
+		t.Fatalf("解码页面失败: %v", err)
+	}
+	data, err := json.Marshal(page.Properties)
+	if err != nil {
+		t.Fatalf("编码失败: %v", err)
+	}
+	var props Properties
+	if err := json.Unmarshal(data, &props); err != nil {
+		t.Fatalf("再次解码失败: %v", err)
+	}
+	if len(props) != len(page.Properties) {
+		t.Fatalf("属性数量不一致: %d != %d", len(props), len(page.Properties))
+	}
+	for name, v := range page.Properties {
+		if props[name].Type != v.Type || props[name].PlainText() != v.PlainText() {
+			t.Errorf("属性 %s 往返后不一致: %+v != %+v", name, props[name], v)
+		}
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		n    float64
+		want string
+	}{
+		{42, "42"},
+		{-3, "-3"},
+		{1.5, "1.5"},
+		{0.1, "0.1"},
+		{1e-7, "0.0000001"},
+		{123456.789012345, "123456.789012345"},
+		{1e21, "1000000000000000000000"},
+	}
+	for _, tt := range tests {
+		if got := formatNumber(tt.n); got != tt.want {
+			t.Errorf("formatNumber(%v) = %q, 期望 %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package notion
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// Plain 创建纯文本片段，可以继续调用 Bold、Italic、WithColor、WithLink 等方法设置样式
+//
+// Plain 不会拆分内容，超过 errors.SizeLimits.MaxRichTextContent 的文本请使用 RichTextBuilder。
+func Plain(content string) RichText {
+	return RichText{Type: "text", Text: &Text{Content: content}, PlainText: content}
+}
+
+// MentionUser 创建提及用户的文本片段
+func MentionUser(userID string) RichText {
+	return mention(Mention{Type: MentionTypeUser, User: &User{Object: "user", ID: userID}})
+}
+
+// MentionPage 创建提及页面的文本片段
+func MentionPage(pageID string) RichText {
+	return mention(Mention{Type: MentionTypePage, Page: &ObjectReference{ID: pageID}})
+}
+
+// MentionDatabase 创建提及数据库的文本片段
+func MentionDatabase(databaseID string) RichText {
+	return mention(Mention{Type: MentionTypeDatabase, Database: &ObjectReference{ID: databaseID}})
+}
+
+// MentionDate 创建提及日期的文本片段，start 和 end 为 ISO 8601 日期或日期时间，end 为空表示单个日期
+func MentionDate(start, end string) RichText {
+	return mention(Mention{Type: MentionTypeDate, Date: &DateValue{Start: start, End: optionalString(end)}})
+}
+
+// mention 创建提及片段
+func mention(m Mention) RichText {
+	return RichText{Type: "mention", Mention: &m}
+}
+
+// EquationText 创建行内公式片段，expression 为 KaTeX 表达式
+func EquationText(expression string) RichText {
+	return RichText{Type: "equation", Equation: &InlineEquation{Expression: expression}, PlainText: expression}
+}
+
+// LinkURL 返回文本片段链接到的地址：文本使用 Text.Link，其他片段使用 Notion 填写的 Href
+func (t RichText) LinkURL() string {
+	if t.Text != nil && t.Text.Link != nil {
+		return t.Text.Link.URL
+	}
+	return t.Href
+}
+
+// MentionResolver 返回提及显示的文本，例如根据页面 ID 查询标题，返回空字符串时使用默认文本
+type MentionResolver func(m *Mention) string
+
+// Content 返回文本片段显示的文本，resolver 可以为 nil
+//
+// 提及先使用 resolver 的结果，然后使用 PlainText，最后使用由提及内容生成的文本：
+// 用户为 "@名称" 或 "@ID"，页面和数据库为 ID，日期为 "开始 → 结束"，链接预览为地址。
+func (t RichText) Content(resolver MentionResolver) string {
+	switch {
+	case t.Mention != nil:
+		if resolver != nil {
+			if s := resolver(t.Mention); s != "" {
+				return s
+			}
+		}
+		if t.PlainText != "" {
+			return t.PlainText
+		}
+		return t.Mention.defaultText()
+	case t.PlainText != "":
+		return t.PlainText
+	case t.Text != nil:
+		return t.Text.Content
+	case t.Equation != nil:
+		return t.Equation.Expression
+	}
+	return ""
+}
+
+// defaultText 返回没有 plain_text 时提及显示的文本
+func (m *Mention) defaultText() string {
+	switch m.Type {
+	case MentionTypeUser:
+		if m.User == nil {
+			return ""
+		}
+		if m.User.Name != "" {
+			return "@" + m.User.Name
+		}
+		return "@" + m.User.ID
+	case MentionTypePage:
+		if m.Page != nil {
+			return m.Page.ID
+		}
+	case MentionTypeDatabase:
+		if m.Database != nil {
+			return m.Database.ID
+		}
+	case MentionTypeDate:
+		if m.Date == nil {
+			return ""
+		}
+		if m.Date.End != nil && *m.Date.End != "" {
+			return m.Date.Start + " → " + *m.Date.End
+		}
+		return m.Date.Start
+	case MentionTypeLinkPreview:
+		if m.LinkPreview != nil {
+			return m.LinkPreview.URL
+		}
+	case MentionTypeTemplateMention:
+		if tm := m.TemplateMention; tm != nil {
+			if tm.TemplateMentionUser != "" {
+				return "@" + tm.TemplateMentionUser
+			}
+			return "@" + tm.TemplateMentionDate
+		}
+	}
+	return ""
+}
+
+// PlainTextOf 拼接富文本显示的文本，提及通过 resolver 解析，resolver 可以为 nil
+func PlainTextOf(rt []RichText, resolver MentionResolver) string {
+	var sb strings.Builder
+	for _, t := range rt {
+		sb.WriteString(t.Content(resolver))
+	}
+	return sb.String()
+}
+
+// annotated 返回注释的副本，避免修改共享的注释
+func (t RichText) annotated() (RichText, *Annotation) {
+	a := Annotation{Color: ColorDefault}
+	if t.Annotations != nil {
+		a = *t.Annotations
+	}
+	t.Annotations = &a
+	return t, &a
+}
+
+// Bold 返回加粗的文本片段
+func (t RichText) Bold() RichText {
+	t, a := t.annotated()
+	a.Bold = true
+	return t
+}
+
+// Italic 返回斜体的文本片段
+func (t RichText) Italic() RichText {
+	t, a := t.annotated()
+	a.Italic = true
+	return t
+}
+
+// Strikethrough 返回带删除线的文本片段
+func (t RichText) Strikethrough() RichText {
+	t, a := t.annotated()
+	a.Strikethrough = true
+	return t
+}
+
+// Underline 返回带下划线的文本片段
+func (t RichText) Underline() RichText {
+	t, a := t.annotated()
+	a.Underline = true
+	return t
+}
+
+// Code 返回行内代码样式的文本片段
+func (t RichText) Code() RichText {
+	t, a := t.annotated()
+	a.Code = true
+	return t
+}
+
+// WithColor 返回指定颜色的文本片段
+func (t RichText) WithColor(color Color) RichText {
+	t, a := t.annotated()
+	a.Color = color
+	return t
+}
+
+// WithLink 返回链接到 url 的文本片段，提及和公式片段只设置 Href，不会发送给 Notion
+func (t RichText) WithLink(url string) RichText {
+	if t.Text != nil {
+		text := *t.Text
+		text.Link = &Link{URL: url}
+		t.Text = &text
+	}
+	t.Href = url
+	return t
+}
+
+// RichTextBuilder 按顺序拼接带样式的文本片段
+//
+// 用法：
+//
+//	rt := notion.NewRichText().
+//		Text("访问 ").
+//		Link("Go 官网", "https://go.dev").
+//		Text(" 下载，运行 ").
+//		Code("go version").
+//		Build()
+type RichTextBuilder struct {
+	spans []RichText
+}
+
+// NewRichText 创建富文本构建器
+func NewRichText() *RichTextBuilder {
+	return &RichTextBuilder{}
+}
+
+// add 追加内容，超长的内容按 errors.SizeLimits.MaxRichTextContent 拆分为多个片段
+func (b *RichTextBuilder) add(content string, style func(RichText) RichText) *RichTextBuilder {
+	for _, chunk := range splitContent(content, errors.SizeLimits.MaxRichTextContent) {
+		b.spans = append(b.spans, style(Plain(chunk)))
+	}
+	return b
+}
+
+// Text 追加纯文本
+func (b *RichTextBuilder) Text(content string) *RichTextBuilder {
+	return b.add(content, func(t RichText) RichText { return t })
+}
+
+// Bold 追加加粗文本
+func (b *RichTextBuilder) Bold(content string) *RichTextBuilder {
+	return b.add(content, RichText.Bold)
+}
+
+// Italic 追加斜体文本
+func (b *RichTextBuilder) Italic(content string) *RichTextBuilder {
+	return b.add(content, RichText.Italic)
+}
+
+// Strikethrough 追加带删除线的文本
+func (b *RichTextBuilder) Strikethrough(content string) *RichTextBuilder {
+	return b.add(content, RichText.Strikethrough)
+}
+
+// Underline 追加带下划线的文本
+func (b *RichTextBuilder) Underline(content string) *RichTextBuilder {
+	return b.add(content, RichText.Underline)
+}
+
+// Code 追加行内代码
+func (b *RichTextBuilder) Code(content string) *RichTextBuilder {
+	return b.add(content, RichText.Code)
+}
+
+// Color 追加指定颜色的文本
+func (b *RichTextBuilder) Color(content string, color Color) *RichTextBuilder {
+	return b.add(content, func(t RichText) RichText { return t.WithColor(color) })
+}
+
+// Link 追加链接
+func (b *RichTextBuilder) Link(content, url string) *RichTextBuilder {
+	return b.add(content, func(t RichText) RichText { return t.WithLink(url) })
+}
+
+// Equation 追加行内公式
+func (b *RichTextBuilder) Equation(expression string) *RichTextBuilder {
+	b.spans = append(b.spans, EquationText(expression))
+	return b
+}
+
+// Append 追加已构建的文本片段，超长的片段按 errors.SizeLimits.MaxRichTextContent 拆分并保留样式
+func (b *RichTextBuilder) Append(spans ...RichText) *RichTextBuilder {
+	for _, span := range spans {
+		if span.Text == nil {
+			b.spans = append(b.spans, span)
+			continue
+		}
+		for _, chunk := range splitContent(span.Text.Content, errors.SizeLimits.MaxRichTextContent) {
+			t := span
+			text := *span.Text
+			text.Content = chunk
+			t.Text = &text
+			t.PlainText = chunk
+			b.spans = append(b.spans, t)
+		}
+	}
+	return b
+}
+
+// Build 返回构建的富文本
+func (b *RichTextBuilder) Build() []RichText {
+	return nonNilRichText(append([]RichText(nil), b.spans...))
+}
+
+// splitContent 将 s 按最多 limit 个字符拆分，空字符串返回 nil
+func splitContent(s string, limit int) []string {
+	if s == "" {
+		return nil
+	}
+	if limit <= 0 || utf8.RuneCountInString(s) <= limit {
+		return []string{s}
+	}
+
+	var chunks []string
+	for s != "" {
+		end, n := 0, 0
+		for end < len(s) && n < limit {
+			_, size := utf8.DecodeRuneInString(s[end:])
+			end += size
+			n++
+		}
+		chunks = append(chunks, s[:end])
+		s = s[end:]
+	}
+	return chunks
+}
+
+// richTextOf 将纯文本和附加片段组合为块使用的富文本
+func richTextOf(text string, spans []RichText) []RichText {
+	return NewRichText().Text(text).Append(spans...).Build()
+}
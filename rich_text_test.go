@@ -0,0 +1,138 @@
+package notion
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const testRichTextJSON = `[
+	{"type": "text", "text": {"content": "见 ", "link": null}, "annotations": {"bold": false, "italic": false, "strikethrough": false, "underline": false, "code": false, "color": "default"}, "plain_text": "见 ", "href": null},
+	{"type": "mention", "mention": {"type": "user", "user": {"object": "user", "id": "user-1"}}, "plain_text": "@张三", "href": null},
+	{"type": "mention", "mention": {"type": "page", "page": {"id": "page-1"}}, "plain_text": "设计文档", "href": "https://www.notion.so/page1"},
+	{"type": "mention", "mention": {"type": "database", "database": {"id": "db-1"}}, "plain_text": "任务", "href": "https://www.notion.so/db1"},
+	{"type": "mention", "mention": {"type": "date", "date": {"start": "2024-12-08", "end": "2024-12-09", "time_zone": null}}, "plain_text": "2024-12-08 → 2024-12-09", "href": null},
+	{"type": "mention", "mention": {"type": "link_preview", "link_preview": {"url": "https://github.com/kuekiko"}}, "plain_text": "https://github.com/kuekiko", "href": "https://github.com/kuekiko"},
+	{"type": "mention", "mention": {"type": "template_mention", "template_mention": {"type": "template_mention_date", "template_mention_date": "today"}}, "plain_text": "@Today", "href": null},
+	{"type": "mention", "mention": {"type": "custom_emoji", "custom_emoji": {"id": "e-1", "name": "party"}}, "plain_text": ":party:", "href": null},
+	{"type": "equation", "equation": {"expression": "e^{i\\pi} + 1 = 0"}, "plain_text": "e^{i\\pi} + 1 = 0", "href": null}
+]`
+
+func TestRichTextDecodeVariants(t *testing.T) {
+	var rt []RichText
+	if err := json.Unmarshal([]byte(testRichTextJSON), &rt); err != nil {
+		t.Fatalf("解码富文本失败: %v", err)
+	}
+	if len(rt) != 9 {
+		t.Fatalf("解码得到 %d 个片段，期望 9 个", len(rt))
+	}
+
+	if m := rt[1].Mention; m == nil || m.Type != MentionTypeUser || m.User == nil || m.User.ID != "user-1" {
+		t.Errorf("用户提及解码错误: %+v", rt[1].Mention)
+	}
+	if m := rt[2].Mention; m == nil || m.Page == nil || m.Page.ID != "page-1" || rt[2].LinkURL() != "https://www.notion.so/page1" {
+		t.Errorf("页面提及解码错误: %+v", rt[2])
+	}
+	if m := rt[3].Mention; m == nil || m.Database == nil || m.Database.ID != "db-1" {
+		t.Errorf("数据库提及解码错误: %+v", rt[3].Mention)
+	}
+	if m := rt[4].Mention; m == nil || m.Date == nil || m.Date.Start != "2024-12-08" || m.Date.End == nil || *m.Date.End != "2024-12-09" {
+		t.Errorf("日期提及解码错误: %+v", rt[4].Mention)
+	}
+	if m := rt[5].Mention; m == nil || m.LinkPreview == nil || m.LinkPreview.URL != "https://github.com/kuekiko" {
+		t.Errorf("链接预览提及解码错误: %+v", rt[5].Mention)
+	}
+	if m := rt[6].Mention; m == nil || m.TemplateMention == nil || m.TemplateMention.TemplateMentionDate != "today" {
+		t.Errorf("模板提及解码错误: %+v", rt[6].Mention)
+	}
+	if rt[8].Equation == nil || rt[8].Equation.Expression != `e^{i\pi} + 1 = 0` {
+		t.Errorf("行内公式解码错误: %+v", rt[8])
+	}
+}
+
+func TestRichTextRoundTrip(t *testing.T) {
+	var rt []RichText
+	if err := json.Unmarshal([]byte(testRichTextJSON), &rt); err != nil {
+		t.Fatalf("解码富文本失败: %v", err)
+	}
+	data, err := json.Marshal(rt)
+	if err != nil {
+		t.Fatalf("编码富文本失败: %v", err)
+	}
+	var again []RichText
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("再次解码失败: %v", err)
+	}
+	again[7].Mention.raw, rt[7].Mention.raw = nil, nil // 原始 JSON 的空白不同
+	if !reflect.DeepEqual(rt, again) {
+		t.Errorf("往返后富文本不同:\n%s", data)
+	}
+
+	// 未知类型的提及保留原始内容
+	var spans []map[string]interface{}
+	if err := json.Unmarshal(data, &spans); err != nil {
+		t.Fatal(err)
+	}
+	emoji, _ := spans[7]["mention"].(map[string]interface{})["custom_emoji"].(map[string]interface{})
+	if emoji["name"] != "party" {
+		t.Errorf("未知提及没有保留原始内容: %v", spans[7]["mention"])
+	}
+}
+
+func TestMentionHelpers(t *testing.T) {
+	tests := []struct {
+		rt   RichText
+		want string
+	}{
+		{MentionUser("user-1"), `{"type":"mention","mention":{"type":"user","user":{"object":"user","id":"user-1"}},"plain_text":""}`},
+		{MentionPage("page-1"), `{"type":"mention","mention":{"type":"page","page":{"id":"page-1"}},"plain_text":""}`},
+		{MentionDatabase("db-1"), `{"type":"mention","mention":{"type":"database","database":{"id":"db-1"}},"plain_text":""}`},
+		{MentionDate("2024-12-08", ""), `{"type":"mention","mention":{"type":"date","date":{"start":"2024-12-08","end":null}},"plain_text":""}`},
+		{EquationText("x^2"), `{"type":"equation","equation":{"expression":"x^2"},"plain_text":"x^2"}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.rt)
+		if err != nil {
+			t.Fatalf("编码失败: %v", err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("编码结果 = %s，期望 %s", data, tt.want)
+		}
+	}
+
+	rt := NewRichText().Text("负责人 ").Append(MentionUser("user-1").Bold()).Equation("n+1").Build()
+	if len(rt) != 3 || rt[1].Mention == nil || !rt[1].Annotations.Bold || rt[2].Equation == nil {
+		t.Errorf("构建器结果错误: %+v", rt)
+	}
+}
+
+func TestPlainTextOf(t *testing.T) {
+	var rt []RichText
+	if err := json.Unmarshal([]byte(testRichTextJSON), &rt); err != nil {
+		t.Fatalf("解码富文本失败: %v", err)
+	}
+	want := "见 @张三设计文档任务2024-12-08 → 2024-12-09https://github.com/kuekiko@Today:party:e^{i\\pi} + 1 = 0"
+	if got := PlainTextOf(rt, nil); got != want {
+		t.Errorf("PlainTextOf = %q，期望 %q", got, want)
+	}
+
+	titles := map[string]string{"page-1": "新标题"}
+	resolver := func(m *Mention) string {
+		if m.Page != nil {
+			return titles[m.Page.ID]
+		}
+		return ""
+	}
+	if got := rt[2].Content(resolver); got != "新标题" {
+		t.Errorf("resolver 解析的页面提及 = %q", got)
+	}
+	if got := rt[3].Content(resolver); got != "任务" {
+		t.Errorf("resolver 返回空字符串时应使用 plain_text，实际为 %q", got)
+	}
+
+	// 本地创建的提及没有 plain_text
+	local := []RichText{MentionUser("user-2"), Plain(" "), MentionDate("2024-01-01", "2024-01-02"), Plain(" "), MentionPage("page-2")}
+	if got := PlainTextOf(local, nil); got != "@user-2 2024-01-01 → 2024-01-02 page-2" {
+		t.Errorf("本地提及的文本 = %q", got)
+	}
+}
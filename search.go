@@ -1,5 +1,9 @@
 package notion
 
+import (
+	"context"
+)
+
 // SearchService 表示搜索服务
 type SearchService struct {
 	client *Client
@@ -35,15 +39,31 @@ type SearchSort struct {
 }
 
 // Search 搜索
-func (s *SearchService) Search(params *SearchParams) (*ListResponse, error) {
+func (s *SearchService) Search(ctx context.Context, params *SearchParams) (*ListResponse, error) {
 	response := new(ListResponse)
-	err := s.client.post("search", params, response)
+	err := s.client.post(ctx, "search", params, response)
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
+// SearchIter 返回遍历所有搜索结果的迭代器，结果为 *Page 或 *Database
+func (s *SearchService) SearchIter(ctx context.Context, params *SearchParams) *Iterator[interface{}] {
+	p := SearchParams{}
+	if params != nil {
+		p = *params
+	}
+	return newIterator(ctx, p.StartCursor, func(ctx context.Context, cursor string) ([]interface{}, string, bool, error) {
+		p.StartCursor = cursor
+		list, err := s.Search(ctx, &p)
+		if err != nil {
+			return nil, "", false, err
+		}
+		return list.Results, list.NextCursor, list.HasMore, nil
+	})
+}
+
 // SearchBuilder 表示搜索构建器
 type SearchBuilder struct {
 	params SearchParams
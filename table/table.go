@@ -0,0 +1,125 @@
+// Package table 把 Notion 数据库当作 Go 结构体的集合来读写
+//
+//	tasks := table.New[Task](client, databaseID)
+//	row, err := tasks.Insert(ctx, &Task{Name: "写文档"})
+//	rows, err := tasks.Query(ctx, filter.Checkbox("完成").Equals(false))
+//
+// 结构体使用 notion 标签声明字段对应的属性，规则见 notion.Marshal。
+package table
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	notion "github.com/kuekiko/NotionGO"
+	"github.com/kuekiko/NotionGO/errors"
+)
+
+// Table 将数据库的页面映射为 T 类型的结构体
+//
+// 需要更新或删除行时 T 应包含 `notion:",id"` 字段保存页面 ID。
+type Table[T any] struct {
+	client     *notion.Client
+	databaseID string
+}
+
+// New 创建数据库对应的 Table
+func New[T any](client *notion.Client, databaseID string) *Table[T] {
+	return &Table[T]{client: client, databaseID: databaseID}
+}
+
+// DatabaseID 返回数据库 ID
+func (t *Table[T]) DatabaseID() string {
+	return t.databaseID
+}
+
+// Insert 在数据库中创建一行，返回根据响应解析的行，其中包含页面 ID 和 Notion 计算的属性
+func (t *Table[T]) Insert(ctx context.Context, row *T) (*T, error) {
+	props, err := notion.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+	page, err := t.client.Pages.Create(ctx, &notion.PageCreateParams{
+		Parent:     notion.Parent{Type: "database_id", DatabaseID: t.databaseID},
+		Properties: props,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decode[T](page)
+}
+
+// Get 获取一行，页面不属于该数据库时返回 ErrInvalidInput
+func (t *Table[T]) Get(ctx context.Context, pageID string) (*T, error) {
+	page, err := t.client.Pages.Get(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	if !sameID(page.Parent.DatabaseID, t.databaseID) {
+		return nil, errors.NewError(errors.ErrInvalidInput,
+			fmt.Sprintf("页面 %s 不属于数据库 %s", pageID, t.databaseID), 0)
+	}
+	return decode[T](page)
+}
+
+// Update 使用 row 中的页面 ID 更新一行的属性，返回更新后的行
+func (t *Table[T]) Update(ctx context.Context, row *T) (*T, error) {
+	pageID, err := notion.RowID(row)
+	if err != nil {
+		return nil, err
+	}
+	props, err := notion.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+	page, err := t.client.Pages.UpdateProperties(ctx, pageID, props)
+	if err != nil {
+		return nil, err
+	}
+	return decode[T](page)
+}
+
+// Delete 归档 row 对应的页面，Notion API 没有删除页面的端点，归档的页面可以在回收站中恢复
+func (t *Table[T]) Delete(ctx context.Context, row *T) error {
+	pageID, err := notion.RowID(row)
+	if err != nil {
+		return err
+	}
+	_, err = t.client.Pages.Archive(ctx, pageID)
+	return err
+}
+
+// Query 查询数据库并返回所有匹配的行，filter 为 nil 时返回全部行
+//
+// filter 可以是 filter 包构建的过滤器或任意可以编码为 Notion 过滤器的值。
+func (t *Table[T]) Query(ctx context.Context, filter interface{}, sorts ...notion.Sort) ([]T, error) {
+	pages, err := t.client.Database.QueryAll(ctx, t.databaseID, &notion.DatabaseQueryParams{Filter: filter, Sorts: sorts}, 0)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]T, len(pages))
+	for i, page := range pages {
+		if err := notion.Unmarshal(page, &rows[i]); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// decode 将页面解析为行
+func decode[T any](page *notion.Page) (*T, error) {
+	row := new(T)
+	if err := notion.Unmarshal(page, row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// sameID 判断两个 ID 是否相同，忽略大小写和连字符
+func sameID(a, b string) bool {
+	normalize := func(id string) string {
+		return strings.ToLower(strings.ReplaceAll(id, "-", ""))
+	}
+	return normalize(a) == normalize(b)
+}
@@ -0,0 +1,127 @@
+package table_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	notion "github.com/kuekiko/NotionGO"
+	"github.com/kuekiko/NotionGO/errors"
+	"github.com/kuekiko/NotionGO/filter"
+	"github.com/kuekiko/NotionGO/notiontest"
+	"github.com/kuekiko/NotionGO/table"
+)
+
+type task struct {
+	ID      string    `notion:",id"`
+	Name    string    `notion:"Name,title"`
+	Points  int       `notion:"点数"`
+	Done    bool      `notion:"完成"`
+	Tags    []string  `notion:"标签,multi_select"`
+	Due     time.Time `notion:"截止日期,date,omitempty"`
+	Created time.Time `notion:"创建时间,created_time"`
+}
+
+func TestTable(t *testing.T) {
+	client, srv := notiontest.NewClient(t)
+	db, err := srv.AddDatabase(notion.Database{
+		Properties: map[string]notion.Property{
+			"Name": {Type: notion.PropertyTypeTitle, Title: &notion.EmptyObject{}},
+			"点数":   {Type: notion.PropertyTypeNumber, Number: &notion.NumberConfig{Format: "number"}},
+			"完成":   {Type: notion.PropertyTypeCheckbox, Checkbox: &notion.EmptyObject{}},
+			"标签":   {Type: notion.PropertyTypeMultiSelect, MultiSelect: &notion.SelectConfig{}},
+			"截止日期": {Type: notion.PropertyTypeDate, Date: &notion.EmptyObject{}},
+			"创建时间": {Type: notion.PropertyTypeCreatedTime, CreatedTime: &notion.EmptyObject{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("添加数据库失败: %v", err)
+	}
+	ctx := context.Background()
+	tasks := table.New[task](client, db.ID)
+
+	due := time.Date(2024, 12, 8, 9, 0, 0, 0, time.UTC)
+	first, err := tasks.Insert(ctx, &task{Name: "写文档", Points: 3, Tags: []string{"docs"}, Due: due})
+	if err != nil {
+		t.Fatalf("Insert 失败: %v", err)
+	}
+	if first.ID == "" || first.Created.IsZero() {
+		t.Errorf("Insert 应返回页面 ID 和创建时间: %+v", first)
+	}
+	if first.Name != "写文档" || first.Points != 3 || !first.Due.Equal(due) {
+		t.Errorf("Insert 返回 %+v", first)
+	}
+	if _, err := tasks.Insert(ctx, &task{Name: "发布", Points: 5, Done: true}); err != nil {
+		t.Fatalf("Insert 失败: %v", err)
+	}
+
+	first.Done = true
+	first.Tags = append(first.Tags, "go")
+	updated, err := tasks.Update(ctx, first)
+	if err != nil {
+		t.Fatalf("Update 失败: %v", err)
+	}
+	if !updated.Done || len(updated.Tags) != 2 {
+		t.Errorf("Update 返回 %+v", updated)
+	}
+
+	got, err := tasks.Get(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("Get 失败: %v", err)
+	}
+	if !got.Done || got.Name != "写文档" {
+		t.Errorf("Get 返回 %+v", got)
+	}
+
+	rows, err := tasks.Query(ctx, filter.Checkbox("完成").Equals(true),
+		notion.Sort{Property: "点数", Direction: "descending"})
+	if err != nil {
+		t.Fatalf("Query 失败: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Name != "发布" || rows[1].Name != "写文档" {
+		t.Errorf("Query 返回 %+v", rows)
+	}
+
+	if _, err := tasks.Update(ctx, &task{Name: "没有 ID"}); !isInvalidInput(err) {
+		t.Errorf("没有页面 ID 时应返回 ErrInvalidInput，实际为 %v", err)
+	}
+
+	srv.ResetRequests()
+	if err := tasks.Delete(ctx, first); err != nil {
+		t.Fatalf("Delete 失败: %v", err)
+	}
+	if reqs := srv.Requests(); len(reqs) != 1 || reqs[0].Method != "PATCH" || string(reqs[0].Body) != `{"archived":true}` {
+		t.Errorf("Delete 应发送 PATCH 归档页面，实际请求 %+v", reqs)
+	}
+	rows, err = tasks.Query(ctx, nil)
+	if err != nil {
+		t.Fatalf("Query 失败: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("删除后 Query 返回 %d 行，期望 1 行", len(rows))
+	}
+}
+
+func TestTableGetOtherDatabase(t *testing.T) {
+	client, srv := notiontest.NewClient(t)
+	db, err := srv.AddDatabase(notion.Database{})
+	if err != nil {
+		t.Fatalf("添加数据库失败: %v", err)
+	}
+	page, err := srv.AddPage(notion.Page{
+		Parent:     notion.Parent{Type: "workspace"},
+		Properties: notion.Properties{"title": notion.NewTitleValue("独立页面")},
+	})
+	if err != nil {
+		t.Fatalf("添加页面失败: %v", err)
+	}
+	_, err = table.New[task](client, db.ID).Get(context.Background(), page.ID)
+	if !isInvalidInput(err) {
+		t.Errorf("页面不属于数据库时应返回 ErrInvalidInput，实际为 %v", err)
+	}
+}
+
+func isInvalidInput(err error) bool {
+	e, ok := err.(*errors.Error)
+	return ok && e.Code == errors.ErrInvalidInput
+}
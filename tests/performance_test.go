@@ -1,30 +1,37 @@
 package tests
 
 import (
-	"net/http"
-	"net/http/httptest"
+	"context"
 	"runtime"
 	"sync"
 	"testing"
 	"time"
 
 	notion "github.com/kuekiko/NotionGO"
+	"github.com/kuekiko/NotionGO/notiontest"
 )
 
-// 创建测试服务器
-func newTestServer() *httptest.Server {
-	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"object": "list", "results": []}`))
-	}))
+// newTestClient 创建连接到模拟服务器的客户端，服务器中有一个 ID 为 test-block-id 的段落块
+//
+// 模拟服务器的客户端不限制速率。
+func newTestClient(t *testing.T) *notion.Client {
+	client, srv := notiontest.NewClient(t)
+	page, err := srv.AddPage(notion.Page{Parent: notion.Parent{Type: "workspace"}})
+	if err != nil {
+		t.Fatalf("添加页面失败: %v", err)
+	}
+	block := notion.Paragraph("性能测试")
+	block.ID = "test-block-id"
+	if _, err := srv.AddBlocks(page.ID, block); err != nil {
+		t.Fatalf("添加块失败: %v", err)
+	}
+	return client
 }
 
 // TestClientPerformance 测试客户端性能
 func TestClientPerformance(t *testing.T) {
-	ts := newTestServer()
-	defer ts.Close()
-
-	client := notion.NewClient("test-token")
+	client := newTestClient(t)
+	ctx := context.Background()
 
 	concurrency := 10
 	requests := 1000
@@ -37,9 +44,9 @@ func TestClientPerformance(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			for j := 0; j < requests/concurrency; j++ {
-				_, err := client.Blocks.Get("test-block-id")
+				_, err := client.Blocks.Get(ctx, "test-block-id")
 				if err != nil {
-					t.Logf("请求错误: %v", err)
+					t.Errorf("请求错误: %v", err)
 				}
 			}
 		}()
@@ -95,10 +102,8 @@ func TestMemoryUsage(t *testing.T) {
 
 // TestConcurrentRequests 测试并发请求
 func TestConcurrentRequests(t *testing.T) {
-	ts := newTestServer()
-	defer ts.Close()
-
-	client := notion.NewClient("test-token")
+	client := newTestClient(t)
+	ctx := context.Background()
 
 	concurrency := 100
 	iterations := 100
@@ -113,7 +118,7 @@ func TestConcurrentRequests(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			for j := 0; j < iterations; j++ {
-				_, err := client.Blocks.Get("test-block-id")
+				_, err := client.Blocks.Get(ctx, "test-block-id")
 				if err != nil {
 					errors <- err
 				}
@@ -131,4 +136,7 @@ func TestConcurrentRequests(t *testing.T) {
 	t.Logf("错误数: %d", errorCount)
 	t.Logf("总耗时: %v", duration)
 	t.Logf("QPS: %.2f", float64(concurrency*iterations)/duration.Seconds())
+	if errorCount > 0 {
+		t.Errorf("第一个错误: %v", <-errors)
+	}
 }
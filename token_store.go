@@ -0,0 +1,152 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// TokenStore 保存 OAuth 授权得到的令牌，键为工作区 ID 或机器人 ID，实现必须是并发安全的
+type TokenStore interface {
+	// Get 返回 key 对应的令牌，不存在时返回 nil, nil
+	Get(ctx context.Context, key string) (*Token, error)
+	// Set 保存令牌，已存在时覆盖
+	Set(ctx context.Context, key string, token *Token) error
+	// Delete 删除令牌，不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryTokenStore 是保存在内存中的 TokenStore，进程退出后令牌丢失
+type MemoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]Token
+}
+
+// NewMemoryTokenStore 创建内存中的 TokenStore
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: make(map[string]Token)}
+}
+
+// Get 实现 TokenStore
+func (s *MemoryTokenStore) Get(ctx context.Context, key string) (*Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	token, ok := s.tokens[key]
+	if !ok {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+// Set 实现 TokenStore
+func (s *MemoryTokenStore) Set(ctx context.Context, key string, token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[key] = *token
+	return nil
+}
+
+// Delete 实现 TokenStore
+func (s *MemoryTokenStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, key)
+	return nil
+}
+
+// FileTokenStore 是保存在 JSON 文件中的 TokenStore，文件权限为 0600
+//
+// 每次读写都重新读取文件，多个进程可以共用同一个文件，但同时写入时后写入的一方覆盖另一方的修改。
+type FileTokenStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileTokenStore 创建保存在 path 中的 TokenStore，所在目录不存在时自动创建
+func NewFileTokenStore(path string) (*FileTokenStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	return &FileTokenStore{path: path}, nil
+}
+
+// Get 实现 TokenStore
+func (s *FileTokenStore) Get(ctx context.Context, key string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return tokens[key], nil
+}
+
+// Set 实现 TokenStore
+func (s *FileTokenStore) Set(ctx context.Context, key string, token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	tokens[key] = token
+	return s.save(tokens)
+}
+
+// Delete 实现 TokenStore
+func (s *FileTokenStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key]; !ok {
+		return nil
+	}
+	delete(tokens, key)
+	return s.save(tokens)
+}
+
+// load 读取文件中的所有令牌，文件不存在时返回空的映射
+func (s *FileTokenStore) load() (map[string]*Token, error) {
+	tokens := make(map[string]*Token)
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// save 写入所有令牌，先写入临时文件再重命名，避免其他进程读到不完整的内容
+func (s *FileTokenStore) save(tokens map[string]*Token) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".tokens-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
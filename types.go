@@ -86,13 +86,94 @@ type Link struct {
 	URL string `json:"url"`
 }
 
-// RichText 表示富文本内容
+// RichText 表示富文本内容，Type 为 "text"、"mention" 或 "equation"，决定哪个字段有效
+//
+// PlainText 和 Href 由 Notion 在响应中填写，创建内容时会被忽略：文本的 Href 是链接地址，
+// 页面和数据库提及的 Href 是对象的 URL，链接预览的 Href 是预览的地址。
 type RichText struct {
-	Type        string      `json:"type"`
-	Text        *Text       `json:"text,omitempty"`
-	Annotations *Annotation `json:"annotations,omitempty"`
-	PlainText   string      `json:"plain_text"`
-	Href        string      `json:"href,omitempty"`
+	Type        string          `json:"type"`
+	Text        *Text           `json:"text,omitempty"`
+	Mention     *Mention        `json:"mention,omitempty"`
+	Equation    *InlineEquation `json:"equation,omitempty"`
+	Annotations *Annotation     `json:"annotations,omitempty"`
+	PlainText   string          `json:"plain_text"`
+	Href        string          `json:"href,omitempty"`
+}
+
+// 提及的类型
+const (
+	MentionTypeUser            = "user"
+	MentionTypePage            = "page"
+	MentionTypeDatabase        = "database"
+	MentionTypeDate            = "date"
+	MentionTypeLinkPreview     = "link_preview"
+	MentionTypeTemplateMention = "template_mention"
+)
+
+// Mention 表示富文本中的提及，Type 决定哪个字段有效
+//
+// 未知类型的提及在解码时保留原始 JSON，编码时原样输出。
+type Mention struct {
+	Type            string           `json:"type"`
+	User            *User            `json:"user,omitempty"`
+	Page            *ObjectReference `json:"page,omitempty"`
+	Database        *ObjectReference `json:"database,omitempty"`
+	Date            *DateValue       `json:"date,omitempty"`
+	LinkPreview     *Link            `json:"link_preview,omitempty"`
+	TemplateMention *TemplateMention `json:"template_mention,omitempty"`
+
+	raw json.RawMessage
+}
+
+// mentionJSON 用于编解码 Mention，避免递归调用 MarshalJSON 和 UnmarshalJSON
+type mentionJSON Mention
+
+// MarshalJSON 编码提及，未知类型输出解码时的原始 JSON
+func (m Mention) MarshalJSON() ([]byte, error) {
+	if len(m.raw) > 0 && !knownMentionType(m.Type) {
+		return m.raw, nil
+	}
+	return json.Marshal(mentionJSON(m))
+}
+
+// UnmarshalJSON 解码提及，未知类型保留原始 JSON
+func (m *Mention) UnmarshalJSON(data []byte) error {
+	var v mentionJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*m = Mention(v)
+	if !knownMentionType(m.Type) {
+		m.raw = append(json.RawMessage(nil), data...)
+	}
+	return nil
+}
+
+// knownMentionType 判断是否是支持的提及类型
+func knownMentionType(typ string) bool {
+	switch typ {
+	case MentionTypeUser, MentionTypePage, MentionTypeDatabase, MentionTypeDate,
+		MentionTypeLinkPreview, MentionTypeTemplateMention:
+		return true
+	}
+	return false
+}
+
+// ObjectReference 表示对页面或数据库的引用
+type ObjectReference struct {
+	ID string `json:"id"`
+}
+
+// TemplateMention 表示模板中的提及，在使用模板时替换为当天日期、当前时间或当前用户
+type TemplateMention struct {
+	Type                string `json:"type"`                            // "template_mention_date" 或 "template_mention_user"
+	TemplateMentionDate string `json:"template_mention_date,omitempty"` // "today" 或 "now"
+	TemplateMentionUser string `json:"template_mention_user,omitempty"` // "me"
+}
+
+// InlineEquation 表示行内公式
+type InlineEquation struct {
+	Expression string `json:"expression"` // KaTeX 表达式
 }
 
 // Annotation 表示文本注释
@@ -117,8 +198,8 @@ type Parent struct {
 type User struct {
 	Object    string  `json:"object"`               // 总是 "user"
 	ID        string  `json:"id"`                   // 用户 ID
-	Type      string  `json:"type"`                 // "person" 或 "bot"
-	Name      string  `json:"name"`                 // 用户名称
+	Type      string  `json:"type,omitempty"`       // "person" 或 "bot"
+	Name      string  `json:"name,omitempty"`       // 用户名称
 	AvatarURL string  `json:"avatar_url,omitempty"` // 头像 URL
 	Person    *Person `json:"person,omitempty"`     // 个人用户信息
 	Bot       *Bot    `json:"bot,omitempty"`        // 机器人用户信息
@@ -137,13 +218,15 @@ type Bot struct {
 
 // Owner 表示机器人所有者
 type Owner struct {
-	Type      string `json:"type"`
-	Workspace bool   `json:"workspace"`
+	Type      string `json:"type"`           // "workspace" 或 "user"
+	Workspace bool   `json:"workspace"`      // 当 Type 为 "workspace" 时为 true
+	User      *User  `json:"user,omitempty"` // 当 Type 为 "user" 时
 }
 
 // File 表示文件
 type File struct {
 	Type     string     `json:"type"`               // "external" 或 "file"
+	Name     string     `json:"name,omitempty"`     // 文件名，用于文件属性
 	External *External  `json:"external,omitempty"` // 当 Type 为 "external" 时
 	File     *FileInfo  `json:"file,omitempty"`     // 当 Type 为 "file" 时
 	Caption  []RichText `json:"caption,omitempty"`
@@ -175,6 +258,7 @@ type SelectOptions struct {
 
 // Option 表示选择选项
 type Option struct {
+	ID    string `json:"id,omitempty"`
 	Name  string `json:"name"`
 	Color Color  `json:"color,omitempty"`
 }
@@ -237,7 +321,7 @@ type StatusGroup struct {
 // Property 表示数据库属性
 type Property struct {
 	ID             string          `json:"id"`                         // 属性 ID
-	Type           string          `json:"type"`                       // 属性类型
+	Type           PropertyType    `json:"type"`                       // 属性类型
 	Name           string          `json:"name"`                       // 属性名称
 	Title          *EmptyObject    `json:"title,omitempty"`            // 标题属性
 	RichText       *EmptyObject    `json:"rich_text,omitempty"`        // 富文本属性
@@ -269,7 +353,11 @@ type ListParams struct {
 }
 
 // ListResponse 表示列出资源的响应
+//
+// Results 中的元素按 object 字段解码为 *Page、*Database、*Block、*User、*Comment 或 *PropertyItem。
 type ListResponse struct {
+	Object     string        `json:"object"`
+	Type       string        `json:"type,omitempty"`
 	Results    []interface{} `json:"results"`
 	HasMore    bool          `json:"has_more"`
 	NextCursor string        `json:"next_cursor,omitempty"`
@@ -277,6 +365,7 @@ type ListResponse struct {
 
 // Comment 表示评论
 type Comment struct {
+	Object         string          `json:"object"` // 总是 "comment"
 	ID             string          `json:"id"`
 	ParentID       string          `json:"parent_id"`
 	ParentType     string          `json:"parent_type"`
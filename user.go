@@ -1,5 +1,9 @@
 package notion
 
+import (
+	"context"
+)
+
 // UserService 表示用户服务
 type UserService struct {
 	client *Client
@@ -11,10 +15,10 @@ func NewUserService(client *Client) *UserService {
 }
 
 // Get 获取用户
-func (s *UserService) Get(userID string) (*User, error) {
+func (s *UserService) Get(ctx context.Context, userID string) (*User, error) {
 	path := "users/" + userID
 	user := new(User)
-	err := s.client.get(path, nil, user)
+	err := s.client.get(ctx, path, nil, user)
 	if err != nil {
 		return nil, err
 	}
@@ -22,19 +26,35 @@ func (s *UserService) Get(userID string) (*User, error) {
 }
 
 // List 列出用户
-func (s *UserService) List(params *ListParams) (*ListResponse, error) {
-	response := new(ListResponse)
-	err := s.client.get("users", params, response)
+func (s *UserService) List(ctx context.Context, params *ListParams) (*UserList, error) {
+	response := new(UserList)
+	err := s.client.get(ctx, "users", params, response)
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
+// ListIter 返回遍历所有用户的迭代器
+func (s *UserService) ListIter(ctx context.Context, params *ListParams) *Iterator[*User] {
+	p := ListParams{}
+	if params != nil {
+		p = *params
+	}
+	return newIterator(ctx, p.StartCursor, func(ctx context.Context, cursor string) ([]*User, string, bool, error) {
+		p.StartCursor = cursor
+		list, err := s.List(ctx, &p)
+		if err != nil {
+			return nil, "", false, err
+		}
+		return list.Results, list.NextCursor, list.HasMore, nil
+	})
+}
+
 // Me 获取当前用户
-func (s *UserService) Me() (*User, error) {
+func (s *UserService) Me(ctx context.Context) (*User, error) {
 	user := new(User)
-	err := s.client.get("users/me", nil, user)
+	err := s.client.get(ctx, "users/me", nil, user)
 	if err != nil {
 		return nil, err
 	}